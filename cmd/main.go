@@ -8,11 +8,20 @@ import (
 	"syscall"
 
 	"github.com/KarolosLykos/json-validation-service/internal/api"
+	"github.com/KarolosLykos/json-validation-service/internal/api/rpc"
 	"github.com/KarolosLykos/json-validation-service/internal/api/server"
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
 	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/jobs/purge"
+	"github.com/KarolosLykos/json-validation-service/internal/jobs/webhooks"
 	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/service/events"
+	"github.com/KarolosLykos/json-validation-service/internal/service/policy"
+	"github.com/KarolosLykos/json-validation-service/internal/service/stats"
 	"github.com/KarolosLykos/json-validation-service/internal/service/validator"
 	"github.com/KarolosLykos/json-validation-service/internal/storage"
+	"github.com/KarolosLykos/json-validation-service/internal/storage/cache"
 	"github.com/KarolosLykos/json-validation-service/internal/storage/store"
 )
 
@@ -44,23 +53,63 @@ func start() error {
 		return err
 	}
 
-	srv := validator.New(log, db)
+	if cfg.Cache.Enabled {
+		db = cache.New(cfg, log, db)
+	}
+
+	bus := events.New(cfg, log, db)
+
+	// the schemas changed by other replicas are dropped from the cache as soon as their events are read.
+	if c, ok := db.(cache.Invalidator); ok {
+		bus.Watch(func(e *event.Event) {
+			c.Invalidate(e.Namespace, e.SchemaID)
+		})
+	}
+
+	bus.Start(ctx)
+
+	recorder := stats.New(cfg, log, db)
+	recorder.Start(ctx)
+
+	srv := policy.New(log, validator.New(cfg, log, db, bus, recorder))
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGQUIT, syscall.SIGTERM)
 
-	s := server.New(ctx, cfg, log, srv)
+	authenticator, err := middleware.NewAuthenticator(cfg, srv)
+	if err != nil {
+		return err
+	}
+
+	limiter, err := middleware.NewRateLimiter(cfg, srv)
+	if err != nil {
+		return err
+	}
+
+	s := server.New(ctx, cfg, log, srv, authenticator, limiter)
 	s.Start(ctx)
 
+	g := rpc.New(ctx, cfg, log, srv, authenticator, limiter)
+	g.Start(ctx)
+
+	purger := purge.New(cfg, log, db)
+	purger.Start(ctx)
+
+	dispatcher := webhooks.New(cfg, log, db)
+	dispatcher.Start(ctx)
+
 	event := <-quit
 
 	log.Debug(ctx, fmt.Sprintf("received signal: %v", event))
 
-	return shutdown(ctx, s, db)
+	// the event streams must end before the server can shut down.
+	return shutdown(ctx, db, bus, s, g, purger, dispatcher, recorder)
 }
 
-func shutdown(ctx context.Context, s api.API, db storage.Storage) error {
-	s.Shutdown(ctx)
+func shutdown(ctx context.Context, db storage.Storage, apis ...api.API) error {
+	for _, a := range apis {
+		a.Shutdown(ctx)
+	}
 
 	return db.Shutdown(ctx)
 }
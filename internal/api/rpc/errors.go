@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/rpc/pb"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// statusCodes maps the HTTP statuses of the service errors to gRPC codes, the others being internal.
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusGone:                  codes.FailedPrecondition,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusPreconditionRequired:  codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+}
+
+func toStatus(p *exceptions.Problem) error {
+	code, ok := statusCodes[p.Status]
+	if !ok {
+		code = codes.Internal
+	}
+
+	if p.Code == exceptions.ErrQuotaExceeded.Code {
+		code = codes.ResourceExhausted
+	}
+
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+
+	st, err := status.New(code, message).WithDetails(toError(p))
+	if err != nil {
+		return status.Error(code, message)
+	}
+
+	return st.Err()
+}
+
+func toError(p *exceptions.Problem) *pb.Error {
+	return &pb.Error{
+		Code:   p.Code,
+		Title:  p.Title,
+		Detail: p.Detail,
+	}
+}
@@ -0,0 +1,194 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/rpc/pb"
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/service"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsonguard"
+)
+
+// handler implements the ValidationService over the same service.Service as the HTTP handlers.
+type handler struct {
+	pb.UnimplementedValidationServiceServer
+
+	log    logger.Logger
+	srv    service.Service
+	limits jsonguard.Limits
+}
+
+func (h *handler) Upload(ctx context.Context, req *pb.UploadRequest) (*pb.UploadResponse, error) {
+	meta := schema.Metadata{
+		Description: req.GetMetadata().GetDescription(),
+		Owner:       req.GetMetadata().GetOwner(),
+		Labels:      req.GetMetadata().GetLabels(),
+		Lifecycle:   schema.Lifecycle{State: req.GetState()},
+	}
+
+	if err := h.srv.UploadSchema(ctx, req.GetSchemaId(), string(req.GetSchema()), meta); err != nil {
+		return nil, h.responseError(ctx, "uploadSchema", err)
+	}
+
+	return &pb.UploadResponse{SchemaId: req.GetSchemaId()}, nil
+}
+
+func (h *handler) Download(ctx context.Context, req *pb.DownloadRequest) (*pb.DownloadResponse, error) {
+	s, err := h.srv.DownloadSchema(ctx, req.GetSchemaId())
+	if err != nil {
+		return nil, h.responseError(ctx, "downloadSchema", err)
+	}
+
+	res := &pb.DownloadResponse{Info: info(s)}
+
+	if s.Schema != nil {
+		res.Schema = *s.Schema
+	}
+
+	return res, nil
+}
+
+func (h *handler) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+	filter := schema.Filter{
+		Owner:  req.GetOwner(),
+		Labels: schema.Labels{},
+	}
+
+	for k, v := range req.GetLabels() {
+		filter.Labels[k] = v
+	}
+
+	schemas, err := h.srv.ListSchemas(ctx, filter)
+	if err != nil {
+		return nil, h.responseError(ctx, "listSchemas", err)
+	}
+
+	res := &pb.ListResponse{Schemas: make([]*pb.SchemaInfo, 0, len(schemas))}
+
+	for _, s := range schemas {
+		res.Schemas = append(res.Schemas, info(s))
+	}
+
+	return res, nil
+}
+
+func (h *handler) Validate(ctx context.Context, req *pb.ValidateRequest) (*pb.ValidateResponse, error) {
+	res, err := h.validate(ctx, req)
+	if err != nil {
+		return nil, h.responseError(ctx, "validateSchema", err)
+	}
+
+	return res, nil
+}
+
+// ValidateStream answers every request in order, failed validations not ending the stream.
+func (h *handler) ValidateStream(stream pb.ValidationService_ValidateStreamServer) error {
+	ctx := stream.Context()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		res, err := h.validate(ctx, req)
+		if err != nil {
+			res.Error = toError(h.problem(ctx, "validateSchema", err))
+		}
+
+		if err = stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+func (h *handler) validate(ctx context.Context, req *pb.ValidateRequest) (*pb.ValidateResponse, error) {
+	res := &pb.ValidateResponse{
+		Id:       req.GetId(),
+		SchemaId: req.GetSchemaId(),
+	}
+
+	payload := make(map[string]interface{})
+
+	if err := jsonguard.Unmarshal(req.GetDocument(), &payload, h.limits); err != nil {
+		if jsonguard.Exceeded(err) {
+			return res, fmt.Errorf("%w: %v", exceptions.ErrJSONTooComplex, err)
+		}
+
+		return res, err
+	}
+
+	s, err := h.srv.ValidateSchema(ctx, req.GetSchemaId(), payload)
+	if s != nil {
+		res.Lifecycle = lifecycle(s.Lifecycle)
+	}
+
+	if err != nil {
+		return res, err
+	}
+
+	res.Valid = true
+
+	return res, nil
+}
+
+func (h *handler) responseError(ctx context.Context, action string, err error) error {
+	return toStatus(h.problem(ctx, action, err))
+}
+
+func (h *handler) problem(ctx context.Context, action string, err error) *exceptions.Problem {
+	p := exceptions.NewProblem(err)
+	p.Action = action
+
+	if p.Status >= http.StatusInternalServerError {
+		h.log.Error(ctx, err, "could not "+action)
+	}
+
+	return p
+}
+
+func info(s *schema.Schema) *pb.SchemaInfo {
+	return &pb.SchemaInfo{
+		SchemaId: s.SchemaID,
+		Digest:   s.Digest,
+		Revision: int32(s.Revision),
+		Metadata: &pb.Metadata{
+			Description: s.Description,
+			Owner:       s.Owner,
+			Labels:      s.Labels,
+		},
+		Lifecycle: lifecycle(s.Lifecycle),
+		CreatedAt: timestamp(&s.CreatedAt),
+		UpdatedAt: timestamp(&s.UpdatedAt),
+	}
+}
+
+func lifecycle(l schema.Lifecycle) *pb.Lifecycle {
+	return &pb.Lifecycle{
+		State:        l.State,
+		DeprecatedAt: timestamp(l.DeprecatedAt),
+		Sunset:       timestamp(l.Sunset),
+		Successor:    l.Successor,
+	}
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
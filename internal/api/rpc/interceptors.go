@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// NamespaceMetadata scopes the calls to a namespace, like the X-Namespace header of the HTTP API.
+const NamespaceMetadata = "x-namespace"
+
+// RequestIDMetadata carries the ID correlating the log lines of a call, like the X-Request-ID header.
+const RequestIDMetadata = "x-request-id"
+
+// groups maps the methods to the route groups of the HTTP API, authenticated alike.
+var groups = map[string]string{
+	"/validation.v1.ValidationService/Upload":         middleware.GroupSchemas,
+	"/validation.v1.ValidationService/Download":       middleware.GroupSchemas,
+	"/validation.v1.ValidationService/List":           middleware.GroupSchemas,
+	"/validation.v1.ValidationService/Validate":       middleware.GroupValidate,
+	"/validation.v1.ValidationService/ValidateStream": middleware.GroupValidate,
+}
+
+// interceptors are the gRPC counterparts of the HTTP middlewares.
+type interceptors struct {
+	log     logger.Logger
+	auth    *middleware.Authenticator
+	limiter *middleware.RateLimiter
+}
+
+func (i *interceptors) unary(
+	ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler,
+) (res interface{}, err error) {
+	defer i.recoverPanic(ctx, &err)
+
+	ctx, err = i.scope(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return next(ctx, req)
+}
+
+func (i *interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) (err error) {
+	ctx := ss.Context()
+
+	defer i.recoverPanic(ctx, &err)
+
+	ctx, err = i.scope(ctx, info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return next(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// recoverPanic handles any panic that may occur.
+func (i *interceptors) recoverPanic(ctx context.Context, err *error) {
+	if r := recover(); r != nil {
+		i.log.Error(ctx, fmt.Errorf("%w: %v", exceptions.ErrRecover, r), "interceptor recovering from panic error")
+		*err = toStatus(exceptions.NewProblem(exceptions.ErrInternalServerError))
+	}
+}
+
+func (i *interceptors) scope(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := middleware.NewRequestID(first(md, RequestIDMetadata))
+	ctx = middleware.WithRequestID(ctx, requestID)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, requestID))
+
+	group, ok := groups[method]
+	if !ok {
+		group = middleware.GroupAdmin
+	}
+
+	if _, err := i.rateLimit(ctx, group, i.limiter.TakeIP); err != nil {
+		return nil, err
+	}
+
+	ctx, err := i.auth.Authenticate(ctx, group, first(md, middleware.APIKeyHeader), first(md, "authorization"))
+	if err != nil {
+		p := exceptions.NewProblem(err)
+
+		if p.Status >= http.StatusInternalServerError {
+			i.log.Error(ctx, err, "could not authenticate call")
+		}
+
+		return nil, toStatus(p)
+	}
+
+	if ctx, err = namespace(ctx, md); err != nil {
+		return nil, err
+	}
+
+	q, err := i.rateLimit(ctx, group, i.limiter.Take)
+	if err != nil {
+		return nil, err
+	}
+
+	if q != nil {
+		_ = grpc.SetHeader(ctx, metadata.New(q.Headers()))
+	}
+
+	return ctx, nil
+}
+
+// rateLimit fails the calls of the clients out of tokens, like the RateLimit and RateLimitIP middlewares.
+func (i *interceptors) rateLimit(
+	ctx context.Context, group string, take func(ctx context.Context, group, ip string) (*middleware.Quota, error),
+) (*middleware.Quota, error) {
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = middleware.RemoteIP(p.Addr.String())
+	}
+
+	q, err := take(ctx, group, ip)
+	if err != nil {
+		// the calls are let through rather than failing along with the shared buckets.
+		i.log.Error(ctx, err, "could not rate limit call")
+	}
+
+	if q == nil || q.Allowed {
+		return q, nil
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.New(q.Headers()))
+
+	return nil, toStatus(exceptions.NewProblem(fmt.Errorf("%w: retry in %ds", exceptions.ErrRateLimited, q.RetryAfter())))
+}
+
+// namespace scopes the call to the namespace of its metadata, like the Namespace middleware.
+func namespace(ctx context.Context, md metadata.MD) (context.Context, error) {
+	values := md.Get(NamespaceMetadata)
+	if len(values) == 0 || values[0] == "" {
+		return ctx, nil
+	}
+
+	ctx, err := middleware.ScopeNamespace(ctx, values[0])
+	if err != nil {
+		return nil, toStatus(exceptions.NewProblem(err))
+	}
+
+	return ctx, nil
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// serverStream overrides the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
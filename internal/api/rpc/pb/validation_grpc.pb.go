@@ -0,0 +1,294 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: validation.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ValidationServiceClient is the client API for ValidationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ValidationServiceClient interface {
+	// Upload stores a new schema.
+	Upload(ctx context.Context, in *UploadRequest, opts ...grpc.CallOption) (*UploadResponse, error)
+	// Download returns a schema, referenced by its ID, ID@version or ID@tag.
+	Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (*DownloadResponse, error)
+	// Validate validates a document against a schema.
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// List describes the schemas, without their bodies.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// ValidateStream validates every document sent on the stream, answering each in order.
+	// Failed validations are reported in the responses and do not end the stream.
+	ValidateStream(ctx context.Context, opts ...grpc.CallOption) (ValidationService_ValidateStreamClient, error)
+}
+
+type validationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewValidationServiceClient(cc grpc.ClientConnInterface) ValidationServiceClient {
+	return &validationServiceClient{cc}
+}
+
+func (c *validationServiceClient) Upload(ctx context.Context, in *UploadRequest, opts ...grpc.CallOption) (*UploadResponse, error) {
+	out := new(UploadResponse)
+	err := c.cc.Invoke(ctx, "/validation.v1.ValidationService/Upload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationServiceClient) Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (*DownloadResponse, error) {
+	out := new(DownloadResponse)
+	err := c.cc.Invoke(ctx, "/validation.v1.ValidationService/Download", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationServiceClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, "/validation.v1.ValidationService/Validate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/validation.v1.ValidationService/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationServiceClient) ValidateStream(ctx context.Context, opts ...grpc.CallOption) (ValidationService_ValidateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ValidationService_ServiceDesc.Streams[0], "/validation.v1.ValidationService/ValidateStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &validationServiceValidateStreamClient{stream}
+	return x, nil
+}
+
+type ValidationService_ValidateStreamClient interface {
+	Send(*ValidateRequest) error
+	Recv() (*ValidateResponse, error)
+	grpc.ClientStream
+}
+
+type validationServiceValidateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *validationServiceValidateStreamClient) Send(m *ValidateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *validationServiceValidateStreamClient) Recv() (*ValidateResponse, error) {
+	m := new(ValidateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ValidationServiceServer is the server API for ValidationService service.
+// All implementations must embed UnimplementedValidationServiceServer
+// for forward compatibility
+type ValidationServiceServer interface {
+	// Upload stores a new schema.
+	Upload(context.Context, *UploadRequest) (*UploadResponse, error)
+	// Download returns a schema, referenced by its ID, ID@version or ID@tag.
+	Download(context.Context, *DownloadRequest) (*DownloadResponse, error)
+	// Validate validates a document against a schema.
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	// List describes the schemas, without their bodies.
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// ValidateStream validates every document sent on the stream, answering each in order.
+	// Failed validations are reported in the responses and do not end the stream.
+	ValidateStream(ValidationService_ValidateStreamServer) error
+	mustEmbedUnimplementedValidationServiceServer()
+}
+
+// UnimplementedValidationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedValidationServiceServer struct {
+}
+
+func (UnimplementedValidationServiceServer) Upload(context.Context, *UploadRequest) (*UploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedValidationServiceServer) Download(context.Context, *DownloadRequest) (*DownloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedValidationServiceServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedValidationServiceServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedValidationServiceServer) ValidateStream(ValidationService_ValidateStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ValidateStream not implemented")
+}
+func (UnimplementedValidationServiceServer) mustEmbedUnimplementedValidationServiceServer() {}
+
+// UnsafeValidationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ValidationServiceServer will
+// result in compilation errors.
+type UnsafeValidationServiceServer interface {
+	mustEmbedUnimplementedValidationServiceServer()
+}
+
+func RegisterValidationServiceServer(s grpc.ServiceRegistrar, srv ValidationServiceServer) {
+	s.RegisterService(&ValidationService_ServiceDesc, srv)
+}
+
+func _ValidationService_Upload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServiceServer).Upload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/validation.v1.ValidationService/Upload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServiceServer).Upload(ctx, req.(*UploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidationService_Download_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServiceServer).Download(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/validation.v1.ValidationService/Download",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServiceServer).Download(ctx, req.(*DownloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidationService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/validation.v1.ValidationService/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServiceServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidationService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/validation.v1.ValidationService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServiceServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidationService_ValidateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ValidationServiceServer).ValidateStream(&validationServiceValidateStreamServer{stream})
+}
+
+type ValidationService_ValidateStreamServer interface {
+	Send(*ValidateResponse) error
+	Recv() (*ValidateRequest, error)
+	grpc.ServerStream
+}
+
+type validationServiceValidateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *validationServiceValidateStreamServer) Send(m *ValidateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *validationServiceValidateStreamServer) Recv() (*ValidateRequest, error) {
+	m := new(ValidateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ValidationService_ServiceDesc is the grpc.ServiceDesc for ValidationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ValidationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "validation.v1.ValidationService",
+	HandlerType: (*ValidationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Upload",
+			Handler:    _ValidationService_Upload_Handler,
+		},
+		{
+			MethodName: "Download",
+			Handler:    _ValidationService_Download_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _ValidationService_Validate_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _ValidationService_List_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ValidateStream",
+			Handler:       _ValidationService_ValidateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "validation.proto",
+}
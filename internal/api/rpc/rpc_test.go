@@ -0,0 +1,548 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/datatypes"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/rpc"
+	"github.com/KarolosLykos/json-validation-service/internal/api/rpc/pb"
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/service"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestServer_Upload(t *testing.T) {
+	tc := []struct {
+		name        string
+		serviceStub func(srv *mock_service.MockService)
+		code        codes.Code
+		errorCode   string
+	}{
+		{
+			name: "uploaded",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UploadSchema(gomock.Any(), "config-schema", `{"type":"object"}`, schema.Metadata{
+						Owner:     "sales",
+						Labels:    schema.Labels{"env": "prod"},
+						Lifecycle: schema.Lifecycle{State: schema.StateDraft},
+					}).
+					Times(1).
+					Return(nil)
+			},
+			code: codes.OK,
+		},
+		{
+			name: "already exists",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(fmt.Errorf("%w: %q", exceptions.ErrAlreadyExists, "config-schema"))
+			},
+			code:      codes.AlreadyExists,
+			errorCode: "already_exists",
+		},
+		{
+			name: "internal error",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(fmt.Errorf("%w:%v", exceptions.ErrCreateSchema, errors.New("connection reset")))
+			},
+			code:      codes.Internal,
+			errorCode: "create_schema_failed",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			res, err := helperClient(t, srv).Upload(context.Background(), &pb.UploadRequest{
+				SchemaId: "config-schema",
+				Schema:   []byte(`{"type":"object"}`),
+				Metadata: &pb.Metadata{Owner: "sales", Labels: map[string]string{"env": "prod"}},
+				State:    schema.StateDraft,
+			})
+
+			helperAssertStatus(t, tt.code, tt.errorCode, err)
+
+			if tt.code == codes.OK {
+				assert.Equal(t, "config-schema", res.GetSchemaId())
+			}
+		})
+	}
+}
+
+func TestServer_Download(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+
+	s := helperSchema(`{"type":"object"}`)
+	s.Labels = schema.Labels{"env": "prod"}
+
+	srv.EXPECT().DownloadSchema(gomock.Any(), "config-schema").Times(1).Return(s, nil)
+	srv.EXPECT().DownloadSchema(gomock.Any(), "missing").Times(1).Return(nil, exceptions.ErrNotFound)
+
+	client := helperClient(t, srv)
+
+	res, err := client.Download(context.Background(), &pb.DownloadRequest{SchemaId: "config-schema"})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"type":"object"}`, string(res.GetSchema()))
+	assert.Equal(t, "config-schema", res.GetInfo().GetSchemaId())
+	assert.Equal(t, int32(1), res.GetInfo().GetRevision())
+	assert.Equal(t, map[string]string{"env": "prod"}, res.GetInfo().GetMetadata().GetLabels())
+	assert.Equal(t, schema.StateActive, res.GetInfo().GetLifecycle().GetState())
+
+	_, err = client.Download(context.Background(), &pb.DownloadRequest{SchemaId: "missing"})
+	helperAssertStatus(t, codes.NotFound, "not_found", err)
+}
+
+func TestServer_Validate(t *testing.T) {
+	tc := []struct {
+		name        string
+		document    string
+		serviceStub func(srv *mock_service.MockService)
+		code        codes.Code
+		errorCode   string
+	}{
+		{
+			name:     "valid",
+			document: `{"name":"config"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ValidateSchema(gomock.Any(), "config-schema", map[string]interface{}{"name": "config"}).
+					Times(1).
+					Return(helperSchema(`{"type":"object"}`), nil)
+			},
+			code: codes.OK,
+		},
+		{
+			name:     "invalid",
+			document: `{"name":1}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(helperSchema(`{"type":"object"}`), fmt.Errorf("%w: %v", exceptions.ErrValidation, "expected string"))
+			},
+			code:      codes.InvalidArgument,
+			errorCode: "validation_failed",
+		},
+		{
+			name:        "invalid json",
+			document:    `{"name":`,
+			serviceStub: func(srv *mock_service.MockService) {},
+			code:        codes.InvalidArgument,
+			errorCode:   "invalid_json",
+		},
+		{
+			name:        "document nested too deeply",
+			document:    `{"name":` + strings.Repeat("[", 64) + strings.Repeat("]", 64) + "}",
+			serviceStub: func(srv *mock_service.MockService) {},
+			code:        codes.InvalidArgument,
+			errorCode:   "json_too_complex",
+		},
+		{
+			name:     "retired",
+			document: `{}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, exceptions.ErrRetired)
+			},
+			code:      codes.FailedPrecondition,
+			errorCode: "schema_retired",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			res, err := helperClient(t, srv).Validate(context.Background(), &pb.ValidateRequest{
+				SchemaId: "config-schema",
+				Document: []byte(tt.document),
+			})
+
+			helperAssertStatus(t, tt.code, tt.errorCode, err)
+
+			if tt.code == codes.OK {
+				assert.True(t, res.GetValid())
+				assert.Equal(t, schema.StateActive, res.GetLifecycle().GetState())
+			}
+		})
+	}
+}
+
+func TestServer_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		ListSchemas(gomock.Any(), schema.Filter{Owner: "sales", Labels: schema.Labels{"env": "prod"}}).
+		Times(1).
+		Return([]*schema.Schema{helperSchema(`{}`)}, nil)
+
+	res, err := helperClient(t, srv).List(context.Background(), &pb.ListRequest{
+		Owner:  "sales",
+		Labels: map[string]string{"env": "prod"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, res.GetSchemas(), 1)
+	assert.Equal(t, "config-schema", res.GetSchemas()[0].GetSchemaId())
+}
+
+func TestServer_ValidateStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+
+	gomock.InOrder(
+		srv.EXPECT().
+			ValidateSchema(gomock.Any(), "config-schema", gomock.Any()).
+			Return(helperSchema(`{}`), nil),
+		srv.EXPECT().
+			ValidateSchema(gomock.Any(), "config-schema", gomock.Any()).
+			Return(helperSchema(`{}`), exceptions.ErrValidation),
+		srv.EXPECT().
+			ValidateSchema(gomock.Any(), "missing", gomock.Any()).
+			Return(nil, exceptions.ErrNotFound),
+	)
+
+	stream, err := helperClient(t, srv).ValidateStream(context.Background())
+	require.NoError(t, err)
+
+	requests := []*pb.ValidateRequest{
+		{Id: "1", SchemaId: "config-schema", Document: []byte(`{}`)},
+		{Id: "2", SchemaId: "config-schema", Document: []byte(`{}`)},
+		{Id: "3", SchemaId: "missing", Document: []byte(`{}`)},
+		{Id: "4", SchemaId: "config-schema", Document: []byte(`[`)},
+	}
+
+	for _, req := range requests {
+		require.NoError(t, stream.Send(req))
+	}
+
+	require.NoError(t, stream.CloseSend())
+
+	expected := []struct {
+		valid bool
+		code  string
+	}{
+		{valid: true},
+		{code: "validation_failed"},
+		{code: "not_found"},
+		{code: "invalid_json"},
+	}
+
+	for i, e := range expected {
+		res, err := stream.Recv()
+		require.NoError(t, err)
+
+		assert.Equal(t, requests[i].GetId(), res.GetId())
+		assert.Equal(t, e.valid, res.GetValid())
+		assert.Equal(t, e.code, res.GetError().GetCode())
+	}
+
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestServer_Namespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		DownloadSchema(gomock.Any(), "config-schema").
+		Times(1).
+		DoAndReturn(func(ctx context.Context, schemaID string) (*schema.Schema, error) {
+			assert.Equal(t, "team-a", contexts.Namespace(ctx))
+
+			return helperSchema(`{}`), nil
+		})
+
+	client := helperClient(t, srv)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), rpc.NamespaceMetadata, "team-a")
+
+	_, err := client.Download(ctx, &pb.DownloadRequest{SchemaId: "config-schema"})
+	require.NoError(t, err)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), rpc.NamespaceMetadata, "Team_A")
+
+	_, err = client.Download(ctx, &pb.DownloadRequest{SchemaId: "config-schema"})
+	helperAssertStatus(t, codes.InvalidArgument, "invalid_namespace", err)
+}
+
+func TestServer_RequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		DownloadSchema(gomock.Any(), "config-schema").
+		Times(1).
+		DoAndReturn(func(ctx context.Context, schemaID string) (*schema.Schema, error) {
+			assert.Equal(t, "req-1", contexts.RequestID(ctx))
+
+			return helperSchema(`{}`), nil
+		})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), rpc.RequestIDMetadata, "req-1")
+
+	var header metadata.MD
+
+	_, err := helperClient(t, srv).Download(ctx, &pb.DownloadRequest{SchemaId: "config-schema"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-1"}, header.Get(rpc.RequestIDMetadata))
+}
+
+func TestServer_RecoverPanic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		ListSchemas(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(context.Context, schema.Filter) ([]*schema.Schema, error) {
+			panic("boom")
+		})
+
+	_, err := helperClient(t, srv).List(context.Background(), &pb.ListRequest{})
+	helperAssertStatus(t, codes.Internal, "internal", err)
+}
+
+func TestServer_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		AuthenticateAPIKey(gomock.Any(), "jvs_key").
+		Times(3).
+		Return(&auth.Principal{Subject: "ci", Method: auth.MethodAPIKey, Namespace: "team-a"}, nil)
+	srv.EXPECT().
+		AuthenticateAPIKey(gomock.Any(), "jvs_unknown").
+		Times(1).
+		Return(nil, exceptions.ErrUnauthorized)
+	srv.EXPECT().
+		DownloadSchema(gomock.Any(), "config-schema").
+		Times(1).
+		DoAndReturn(func(ctx context.Context, schemaID string) (*schema.Schema, error) {
+			assert.Equal(t, "team-a", contexts.Namespace(ctx))
+			assert.Equal(t, "apikey:ci", contexts.Actor(ctx))
+
+			return helperSchema(`{}`), nil
+		})
+	srv.EXPECT().
+		ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(helperSchema(`{}`), nil)
+
+	client := helperClient(t, srv, map[string]string{
+		middleware.GroupSchemas:  "apikey",
+		middleware.GroupValidate: "apikey",
+	})
+
+	_, err := client.Download(context.Background(), &pb.DownloadRequest{SchemaId: "config-schema"})
+	helperAssertStatus(t, codes.Unauthenticated, "unauthorized", err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), middleware.APIKeyHeader, "jvs_unknown")
+
+	_, err = client.Download(ctx, &pb.DownloadRequest{SchemaId: "config-schema"})
+	helperAssertStatus(t, codes.Unauthenticated, "unauthorized", err)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer jvs_key")
+
+	_, err = client.Download(ctx, &pb.DownloadRequest{SchemaId: "config-schema"})
+	require.NoError(t, err)
+
+	stream, err := client.ValidateStream(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&pb.ValidateRequest{SchemaId: "config-schema", Document: []byte(`{}`)}))
+
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.True(t, res.GetValid())
+
+	require.NoError(t, stream.CloseSend())
+
+	// the key is bound to its namespace.
+	ctx = metadata.AppendToOutgoingContext(ctx, rpc.NamespaceMetadata, "team-b")
+
+	_, err = client.Download(ctx, &pb.DownloadRequest{SchemaId: "config-schema"})
+	helperAssertStatus(t, codes.PermissionDenied, "forbidden", err)
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		DownloadSchema(gomock.Any(), "config-schema").
+		Times(1).
+		Return(helperSchema(`{}`), nil)
+
+	cfg, _ := config.Load()
+	cfg.RateLimit = config.RateLimit{Enabled: true, Key: middleware.KeyIP, Rate: 0.001, Burst: 1, Retention: time.Hour}
+
+	client := helperServe(t, cfg, srv)
+
+	var header metadata.MD
+
+	_, err := client.Download(context.Background(), &pb.DownloadRequest{SchemaId: "config-schema"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0"}, header.Get("ratelimit-remaining"))
+
+	_, err = client.Download(context.Background(), &pb.DownloadRequest{SchemaId: "config-schema"}, grpc.Header(&header))
+	helperAssertStatus(t, codes.ResourceExhausted, "rate_limited", err)
+	assert.Equal(t, []string{"1000"}, header.Get("retry-after"))
+}
+
+func TestServer_RateLimitUnauthenticated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		AuthenticateAPIKey(gomock.Any(), "jvs_guess").
+		Times(1).
+		Return(nil, exceptions.ErrUnauthorized)
+
+	cfg, _ := config.Load()
+	cfg.Auth.Groups = map[string]string{middleware.GroupSchemas: "apikey"}
+	cfg.RateLimit = config.RateLimit{Enabled: true, Key: middleware.KeyPrincipal, Rate: 0.001, Burst: 1, Retention: time.Hour}
+
+	client := helperServe(t, cfg, srv)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), middleware.APIKeyHeader, "jvs_guess")
+
+	_, err := client.Download(ctx, &pb.DownloadRequest{SchemaId: "config-schema"})
+	helperAssertStatus(t, codes.Unauthenticated, "unauthorized", err)
+
+	// the calls failing to authenticate are bounded by their IP.
+	_, err = client.Download(ctx, &pb.DownloadRequest{SchemaId: "config-schema"})
+	helperAssertStatus(t, codes.ResourceExhausted, "rate_limited", err)
+}
+
+// helperClient serves the service over an in-memory connection, the route groups being
+// authenticated as given, if any.
+func helperClient(t *testing.T, srv service.Service, groups ...map[string]string) pb.ValidationServiceClient {
+	t.Helper()
+
+	cfg, _ := config.Load()
+
+	for _, g := range groups {
+		cfg.Auth.Groups = g
+	}
+
+	return helperServe(t, cfg, srv)
+}
+
+// helperServe serves the service over an in-memory connection, authenticated and rate limited as configured.
+func helperServe(t *testing.T, cfg *config.Config, srv service.Service) pb.ValidationServiceClient {
+	t.Helper()
+
+	authenticator, err := middleware.NewAuthenticator(cfg, srv)
+	require.NoError(t, err)
+
+	limiter, err := middleware.NewRateLimiter(cfg, srv)
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	s := rpc.NewServer(cfg, logruslog.DefaultLogger(cfg), srv, authenticator, limiter)
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return pb.NewValidationServiceClient(conn)
+}
+
+// helperAssertStatus asserts the gRPC code of the error, and the code of the Error detailing it.
+func helperAssertStatus(t *testing.T, code codes.Code, errorCode string, err error) {
+	t.Helper()
+
+	st, _ := status.FromError(err)
+	require.Equal(t, code, st.Code(), st.Message())
+
+	if code == codes.OK {
+		return
+	}
+
+	require.Len(t, st.Details(), 1)
+
+	detail, ok := st.Details()[0].(*pb.Error)
+	require.True(t, ok)
+	assert.Equal(t, errorCode, detail.GetCode())
+}
+
+func helperSchema(payload string) *schema.Schema {
+	s := datatypes.JSON(payload)
+
+	return &schema.Schema{
+		SchemaID: "config-schema",
+		Schema:   &s,
+		Revision: 1,
+		Metadata: schema.Metadata{Lifecycle: schema.Lifecycle{State: schema.StateActive}},
+	}
+}
@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api"
+	"github.com/KarolosLykos/json-validation-service/internal/api/rpc/pb"
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/service"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsonguard"
+)
+
+type server struct {
+	cfg    *config.Config
+	log    logger.Logger
+	server *grpc.Server
+}
+
+// New returns the gRPC counterpart of the HTTP server.
+func New(
+	ctx context.Context, cfg *config.Config, log logger.Logger, srv service.Service,
+	authenticator *middleware.Authenticator, limiter *middleware.RateLimiter,
+) api.API {
+	log.Debug(ctx, "create new grpc server")
+
+	return &server{
+		cfg:    cfg,
+		log:    log,
+		server: NewServer(cfg, log, srv, authenticator, limiter),
+	}
+}
+
+// NewServer returns a gRPC server serving the ValidationService, guarded like the routes of the HTTP API.
+func NewServer(
+	cfg *config.Config,
+	log logger.Logger,
+	srv service.Service,
+	authenticator *middleware.Authenticator,
+	limiter *middleware.RateLimiter,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	i := &interceptors{log: log, auth: authenticator, limiter: limiter}
+
+	opts = append(opts, grpc.ChainUnaryInterceptor(i.unary), grpc.ChainStreamInterceptor(i.stream))
+
+	if cfg.Limits.MaxBodyBytes > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(cfg.Limits.MaxBodyBytes)))
+	}
+
+	s := grpc.NewServer(opts...)
+	pb.RegisterValidationServiceServer(s, &handler{log: log, srv: srv, limits: jsonguard.FromConfig(cfg)})
+
+	return s
+}
+
+func (s *server) Start(ctx context.Context) {
+	s.log.Debug(ctx, "starting grpc server")
+
+	addr := fmt.Sprintf("%s:%s", s.cfg.GRPC.IP, s.cfg.GRPC.Port)
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		s.log.Panic(ctx, err, "could not initiate grpc server", s.cfg.GRPC.Port)
+	}
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil {
+			s.log.Error(ctx, err, "grpc server stopped")
+		}
+	}()
+
+	s.log.Info(ctx, "grpc server started on ", addr)
+}
+
+// Shutdown waits for the pending calls and streams to end, unless ctx is done first.
+func (s *server) Shutdown(ctx context.Context) {
+	s.log.Debug(ctx, "shutting down grpc server")
+
+	stopped := make(chan struct{})
+
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// APIKeyRequest names a new API key, optionally bound to a namespace, and grants it roles.
+type APIKeyRequest struct {
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Grants    auth.Grants `json:"grants,omitempty"`
+}
+
+func (h *Handler) CreateAPIKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req := &APIKeyRequest{}
+
+		if err := h.decode(r, req); err != nil {
+			h.responseError(ctx, w, "createAPIKey", "", invalidRequest(exceptions.ErrInvalidAPIKey, err))
+
+			return
+		}
+
+		key := &auth.APIKey{
+			Name:      req.Name,
+			Namespace: req.Namespace,
+			Grants:    req.Grants,
+		}
+
+		if err := h.srv.CreateAPIKey(ctx, key); err != nil {
+			h.responseError(ctx, w, "createAPIKey", "", err)
+
+			return
+		}
+
+		// the key is only ever returned here.
+		responseSuccess(w, http.StatusCreated, "createAPIKey", strconv.FormatUint(uint64(key.ID), 10), key)
+	}
+}
+
+func (h *Handler) ListAPIKeys() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		keys, err := h.srv.ListAPIKeys(ctx)
+		if err != nil {
+			h.responseError(ctx, w, "listAPIKeys", "", err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listAPIKeys", "", keys)
+	}
+}
+
+func (h *Handler) DeleteAPIKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		keyID := mux.Vars(r)["keyID"]
+
+		id, err := strconv.ParseUint(keyID, 10, 32)
+		if err != nil {
+			h.responseError(ctx, w, "deleteAPIKey", keyID, fmt.Errorf("%w: keyID: %q", exceptions.ErrInvalidQuery, keyID))
+
+			return
+		}
+
+		if err = h.srv.DeleteAPIKey(ctx, uint(id)); err != nil {
+			h.responseError(ctx, w, "deleteAPIKey", keyID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "deleteAPIKey", keyID, nil)
+	}
+}
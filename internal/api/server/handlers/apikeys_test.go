@@ -0,0 +1,161 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestHandler_CreateAPIKey(t *testing.T) {
+	grants := auth.Grants{{Role: auth.RolePublisher, Prefix: "orders-"}}
+
+	tc := []struct {
+		name        string
+		body        string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		res         *auth.APIKey
+	}{
+		{
+			name: "status created",
+			body: `{"name":"ci","namespace":"team-a","grants":[{"role":"publisher","prefix":"orders-"}]}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					CreateAPIKey(gomock.Any(), &auth.APIKey{Name: "ci", Namespace: "team-a", Grants: grants}).
+					Times(1).
+					DoAndReturn(func(_ interface{}, key *auth.APIKey) error {
+						key.ID, key.Key, key.Prefix, key.Hash = 1, "jvs_s3cr3t", "jvs_s3cr", "hash"
+
+						return nil
+					})
+			},
+			statusCode: http.StatusCreated,
+			res: &auth.APIKey{
+				ID:        1,
+				Name:      "ci",
+				Namespace: "team-a",
+				Grants:    grants,
+				Prefix:    "jvs_s3cr",
+				Key:       "jvs_s3cr3t",
+			},
+		},
+		{
+			name: "malformed body",
+			body: `{"name":`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid api key",
+			body: `{"name":""}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(exceptions.ErrInvalidAPIKey)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "internal server error",
+			body: `{"name":"ci"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(exceptions.ErrCreateAPIKey)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/apikeys", bytes.NewBufferString(tt.body))
+
+			h.CreateAPIKey()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			if tt.res != nil {
+				res := &struct {
+					Payload *auth.APIKey `json:"payload"`
+				}{}
+				require.NoError(t, json.NewDecoder(w.Body).Decode(res))
+
+				res.Payload.CreatedAt = tt.res.CreatedAt
+				assert.Equal(t, tt.res, res.Payload)
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteAPIKey(t *testing.T) {
+	tc := []struct {
+		name        string
+		keyID       string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name:  "deleted",
+			keyID: "1",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DeleteAPIKey(gomock.Any(), uint(1)).Times(1).Return(nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "not found",
+			keyID: "2",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DeleteAPIKey(gomock.Any(), uint(2)).Times(1).Return(exceptions.ErrNotFound)
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:  "invalid id",
+			keyID: "ci",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DeleteAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodDelete, "/apikeys/"+tt.keyID, nil)
+			r = mux.SetURLVars(r, map[string]string{"keyID": tt.keyID})
+
+			h.DeleteAPIKey()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
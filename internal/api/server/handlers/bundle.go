@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsonguard"
+)
+
+// Files of a schema in a tar.gz bundle, under a directory named after the schema.
+const (
+	bundleSchemaFile = "schema.json"
+	bundleMetaFile   = "meta.json"
+)
+
+// Bundle is the JSON form of an import or export of many schemas.
+type Bundle struct {
+	Schemas []*BundleEntry `json:"schemas"`
+}
+
+// BundleEntry is a schema along with its metadata, its digest, revision and timestamps ignored on import.
+type BundleEntry struct {
+	Name        string          `json:"name,omitempty"`
+	Schema      json.RawMessage `json:"schema,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Owner       string          `json:"owner,omitempty"`
+	Labels      schema.Labels   `json:"labels,omitempty"`
+	Digest      string          `json:"digest,omitempty"`
+	Revision    int             `json:"revision,omitempty"`
+	CreatedAt   *time.Time      `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time      `json:"updatedAt,omitempty"`
+}
+
+func isTarGz(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "application/gzip", "application/x-gzip", "application/x-tar+gzip":
+		return true
+	}
+
+	return false
+}
+
+// bundleDepth is the depth of the schemas within a JSON bundle.
+const bundleDepth = 3
+
+// readBundle reads the schemas of a tar.gz or JSON bundle, the tarball holding at most limit bytes once
+// uncompressed, 0 meaning unbounded.
+func readBundle(r *http.Request, limit int64, limits jsonguard.Limits) ([]*schema.Schema, error) {
+	var (
+		entries []*BundleEntry
+		err     error
+	)
+
+	if isTarGz(r.Header.Get("Content-Type")) {
+		entries, err = readTarBundle(r.Body, limit, limits)
+	} else {
+		if limits.MaxDepth > 0 {
+			limits.MaxDepth += bundleDepth
+		}
+
+		bundle := &Bundle{}
+		err = json.NewDecoder(jsonguard.NewReader(r.Body, limits)).Decode(bundle)
+		entries = bundle.Schemas
+	}
+
+	if errors.Is(err, exceptions.ErrBodyTooLarge) {
+		return nil, err
+	}
+
+	if jsonguard.Exceeded(err) {
+		return nil, fmt.Errorf("%w: %v", exceptions.ErrJSONTooComplex, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", exceptions.ErrInvalidBundle, err)
+	}
+
+	schemas := make([]*schema.Schema, 0, len(entries))
+
+	for _, e := range entries {
+		if len(e.Schema) == 0 {
+			return nil, fmt.Errorf("%w: missing schema of %q", exceptions.ErrInvalidBundle, e.Name)
+		}
+
+		body := datatypes.JSON(e.Schema)
+
+		schemas = append(schemas, &schema.Schema{
+			SchemaID: e.Name,
+			Schema:   &body,
+			Metadata: schema.Metadata{
+				Description: e.Description,
+				Owner:       e.Owner,
+				Labels:      e.Labels,
+			},
+		})
+	}
+
+	return schemas, nil
+}
+
+// readTarBundle reads a gzipped tarball holding a <name>/schema.json and an optional <name>/meta.json per schema.
+func readTarBundle(r io.Reader, limit int64, limits jsonguard.Limits) ([]*BundleEntry, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var uncompressed io.Reader = gz
+	if limit > 0 {
+		uncompressed = &limitedReader{r: gz, limit: limit, remaining: limit}
+	}
+
+	entries := make(map[string]*BundleEntry)
+	tr := tar.NewReader(uncompressed)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		dir, file := path.Split(strings.TrimPrefix(path.Clean(hdr.Name), "./"))
+		name := strings.TrimSuffix(dir, "/")
+
+		if name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("unexpected file %q", hdr.Name)
+		}
+
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		e, ok := entries[name]
+		if !ok {
+			e = &BundleEntry{Name: name}
+			entries[name] = e
+		}
+
+		switch file {
+		case bundleSchemaFile:
+			e.Schema = b
+		case bundleMetaFile:
+			meta := &BundleEntry{}
+			if err = jsonguard.Unmarshal(b, meta, limits); err != nil {
+				return nil, fmt.Errorf("%s: %w", hdr.Name, err)
+			}
+
+			e.Description, e.Owner, e.Labels = meta.Description, meta.Owner, meta.Labels
+		default:
+			return nil, fmt.Errorf("unexpected file %q", hdr.Name)
+		}
+	}
+
+	res := make([]*BundleEntry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res, nil
+}
+
+func bundleEntry(s *schema.Schema) *BundleEntry {
+	e := &BundleEntry{
+		Name:        s.SchemaID,
+		Description: s.Description,
+		Owner:       s.Owner,
+		Labels:      s.Labels,
+		Digest:      s.Digest,
+		Revision:    s.Revision,
+		CreatedAt:   &s.CreatedAt,
+		UpdatedAt:   &s.UpdatedAt,
+	}
+
+	if s.Schema != nil {
+		e.Schema = json.RawMessage(*s.Schema)
+	}
+
+	return e
+}
+
+// bundleWriter writes nothing until the first schema, so that the errors before it still get their status.
+type bundleWriter interface {
+	Write(s *schema.Schema) error
+	Close() error
+	Started() bool
+}
+
+func newBundleWriter(w http.ResponseWriter, tarGz bool) bundleWriter {
+	if tarGz {
+		return &tarBundleWriter{w: w}
+	}
+
+	return &jsonBundleWriter{w: w}
+}
+
+type jsonBundleWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func (b *jsonBundleWriter) start() error {
+	if b.started {
+		_, err := io.WriteString(b.w, ",")
+
+		return err
+	}
+
+	return b.header()
+}
+
+func (b *jsonBundleWriter) header() error {
+	b.started = true
+
+	b.w.Header().Set("Content-Type", "application/json")
+	b.w.Header().Set("Content-Disposition", `attachment; filename="schemas.json"`)
+	b.w.WriteHeader(http.StatusOK)
+
+	_, err := io.WriteString(b.w, `{"schemas":[`)
+
+	return err
+}
+
+func (b *jsonBundleWriter) Write(s *schema.Schema) error {
+	if err := b.start(); err != nil {
+		return err
+	}
+
+	return json.NewEncoder(b.w).Encode(bundleEntry(s))
+}
+
+func (b *jsonBundleWriter) Close() error {
+	if !b.started {
+		if err := b.header(); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(b.w, "]}")
+
+	return err
+}
+
+func (b *jsonBundleWriter) Started() bool {
+	return b.started
+}
+
+type tarBundleWriter struct {
+	w  http.ResponseWriter
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (b *tarBundleWriter) start() {
+	if b.tw != nil {
+		return
+	}
+
+	b.w.Header().Set("Content-Type", "application/gzip")
+	b.w.Header().Set("Content-Disposition", `attachment; filename="schemas.tar.gz"`)
+	b.w.WriteHeader(http.StatusOK)
+
+	b.gz = gzip.NewWriter(b.w)
+	b.tw = tar.NewWriter(b.gz)
+}
+
+func (b *tarBundleWriter) Write(s *schema.Schema) error {
+	b.start()
+
+	e := bundleEntry(s)
+	body := e.Schema
+	e.Name, e.Schema = "", nil
+
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err = b.writeFile(path.Join(s.SchemaID, bundleSchemaFile), body, s.UpdatedAt); err != nil {
+		return err
+	}
+
+	return b.writeFile(path.Join(s.SchemaID, bundleMetaFile), meta, s.UpdatedAt)
+}
+
+func (b *tarBundleWriter) writeFile(name string, content []byte, modTime time.Time) error {
+	if err := b.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(content)),
+		ModTime:  modTime,
+	}); err != nil {
+		return err
+	}
+
+	_, err := b.tw.Write(content)
+
+	return err
+}
+
+func (b *tarBundleWriter) Close() error {
+	b.start()
+
+	if err := b.tw.Close(); err != nil {
+		return err
+	}
+
+	return b.gz.Close()
+}
+
+func (b *tarBundleWriter) Started() bool {
+	return b.tw != nil
+}
+
+// limitedReader guards against the tarballs inflating far beyond the size of the request.
+type limitedReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+
+	if l.remaining < 0 {
+		return n, fmt.Errorf("%w: more than %d bytes uncompressed", exceptions.ErrBodyTooLarge, l.limit)
+	}
+
+	return n, err
+}
@@ -0,0 +1,354 @@
+package handlers_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/handlers"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestHandler_Import(t *testing.T) {
+	tc := []struct {
+		name        string
+		query       string
+		contentType string
+		body        []byte
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		code        string
+	}{
+		{
+			name: "json bundle",
+			body: []byte(`{"schemas":[{"name":"orders","schema":{"type":"object"},"owner":"sales","labels":{"env":"prod"}}]}`),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), schema.ImportFailOnConflict).
+					Times(1).
+					DoAndReturn(func(_ interface{}, schemas []*schema.Schema, _ schema.ImportMode) (*schema.ImportResult, error) {
+						require.Len(t, schemas, 1)
+						assert.Equal(t, "orders", schemas[0].SchemaID)
+						assert.JSONEq(t, `{"type":"object"}`, schemas[0].Schema.String())
+						assert.Equal(t, "sales", schemas[0].Owner)
+						assert.Equal(t, schema.Labels{"env": "prod"}, schemas[0].Labels)
+
+						return &schema.ImportResult{Created: []string{"orders"}}, nil
+					})
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "tar.gz bundle",
+			query:       "?mode=overwrite",
+			contentType: "application/gzip",
+			body: helperTarGz(t, map[string]string{
+				"orders/schema.json":  `{"type":"object"}`,
+				"orders/meta.json":    `{"owner":"sales"}`,
+				"invoice/schema.json": `{"type":"array"}`,
+			}),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), schema.ImportOverwrite).
+					Times(1).
+					DoAndReturn(func(_ interface{}, schemas []*schema.Schema, _ schema.ImportMode) (*schema.ImportResult, error) {
+						require.Len(t, schemas, 2)
+						assert.Equal(t, "invoice", schemas[0].SchemaID)
+						assert.Equal(t, "orders", schemas[1].SchemaID)
+						assert.Equal(t, "sales", schemas[1].Owner)
+
+						return &schema.ImportResult{Updated: []string{"invoice", "orders"}}, nil
+					})
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "tar.gz missing schema",
+			contentType: "application/gzip",
+			body:        helperTarGz(t, map[string]string{"orders/meta.json": `{"owner":"sales"}`}),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "tar.gz unexpected file",
+			contentType: "application/gzip",
+			body:        helperTarGz(t, map[string]string{"orders/schema.json": `{}`, "README": "hi"}),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "tar.gz inflating past the body limit",
+			contentType: "application/gzip",
+			body:        helperTarGz(t, map[string]string{"orders/schema.json": "{}" + strings.Repeat(" ", 5<<20)}),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "malformed bundle",
+			body: []byte(`{"schemas":`),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "json bundle nesting a schema as deep as allowed",
+			body: []byte(`{"schemas":[{"name":"orders","schema":` + strings.Repeat("[", 64) + strings.Repeat("]", 64) + `}]}`),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&schema.ImportResult{Created: []string{"orders"}}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "json bundle nesting a schema too deeply",
+			body: []byte(`{"schemas":[{"name":"orders","schema":` + strings.Repeat("[", 65) + strings.Repeat("]", 65) + `}]}`),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+			code:       "json_too_complex",
+		},
+		{
+			name:        "tar.gz meta nested too deeply",
+			contentType: "application/gzip",
+			body: helperTarGz(t, map[string]string{
+				"orders/schema.json": `{}`,
+				"orders/meta.json":   `{"labels":` + strings.Repeat("[", 64) + strings.Repeat("]", 64) + `}`,
+			}),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+			code:       "json_too_complex",
+		},
+		{
+			name:  "invalid mode",
+			query: "?mode=merge",
+			body:  []byte(`{"schemas":[]}`),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), schema.ImportMode("merge")).
+					Times(1).
+					Return(nil, exceptions.ErrInvalidQuery)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "conflict",
+			body: []byte(`{"schemas":[{"name":"orders","schema":{}}]}`),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, exceptions.ErrAlreadyExists)
+			},
+			statusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/schemas/import"+tt.query, bytes.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			h.Import()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			if tt.code != "" {
+				assert.Equal(t, tt.code, helperDecodeProblem(t, w).Code)
+			}
+		})
+	}
+}
+
+func TestHandler_Export(t *testing.T) {
+	orders := helperSchema(`{"type":"object"}`)
+	orders.SchemaID = "orders"
+	orders.Owner = "sales"
+
+	tc := []struct {
+		name        string
+		query       string
+		accept      string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		contentType string
+	}{
+		{
+			name:  "json bundle",
+			query: "?owner=sales",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ExportSchemas(gomock.Any(), schema.Filter{Owner: "sales", Labels: schema.Labels{}}, gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, _ schema.Filter, fn func(*schema.Schema) error) error {
+						return fn(orders)
+					})
+			},
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			name:   "tar.gz bundle",
+			accept: "application/gzip",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ExportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, _ schema.Filter, fn func(*schema.Schema) error) error {
+						return fn(orders)
+					})
+			},
+			statusCode:  http.StatusOK,
+			contentType: "application/gzip",
+		},
+		{
+			name: "internal server error",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ExportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(exceptions.ErrExportSchemas)
+			},
+			statusCode:  http.StatusInternalServerError,
+			contentType: exceptions.ProblemContentType,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/schemas/export"+tt.query, nil)
+			r.Header.Set("Accept", tt.accept)
+
+			h.Export()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			// an export must be importable as is.
+			imp := mock_service.NewMockService(ctrl)
+			imp.EXPECT().
+				ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ interface{}, schemas []*schema.Schema, _ schema.ImportMode) (*schema.ImportResult, error) {
+					require.Len(t, schemas, 1)
+					assert.Equal(t, "orders", schemas[0].SchemaID)
+					assert.Equal(t, "sales", schemas[0].Owner)
+					assert.JSONEq(t, `{"type":"object"}`, schemas[0].Schema.String())
+
+					return &schema.ImportResult{}, nil
+				})
+
+			iw := httptest.NewRecorder()
+			ir, _ := http.NewRequest(http.MethodPost, "/schemas/import", w.Body)
+			ir.Header.Set("Content-Type", tt.contentType)
+
+			helperNewHandler(t, imp).Import()(iw, ir)
+
+			require.Equal(t, http.StatusOK, iw.Code)
+		})
+	}
+}
+
+func TestHandler_ExportEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		ExportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/schemas/export", nil)
+
+	helperNewHandler(t, srv).Export()(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	bundle := &handlers.Bundle{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(bundle))
+	assert.Empty(t, bundle.Schemas)
+}
+
+func helperTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+		}))
+
+		_, err := io.WriteString(tw, content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// checkPrecondition returns the current schema when If-Match holds its ETag, raw or not.
+func (h *Handler) checkPrecondition(r *http.Request, schemaID string) (*schema.Schema, error) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return nil, exceptions.ErrPreconditionRequired
+	}
+
+	current, err := h.srv.DownloadSchema(r.Context(), schemaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !matchETag(ifMatch, current.ETag(), false) && !matchETag(ifMatch, current.RawETag(), false) {
+		return nil, exceptions.ErrPreconditionFailed
+	}
+
+	return current, nil
+}
+
+// matchETag compares weakly for If-None-Match and strongly for If-Match (RFC 7232).
+func matchETag(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// heartbeatInterval keeps idle event streams from being closed by proxies.
+const heartbeatInterval = 15 * time.Second
+
+func parseLastEventID(r *http.Request) (uint64, error) {
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: Last-Event-ID: %v", exceptions.ErrInvalidQuery, err)
+	}
+
+	return id, nil
+}
+
+func writeEvent(w io.Writer, e *event.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+
+	return err
+}
@@ -0,0 +1,140 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+)
+
+func TestHandler_Events(t *testing.T) {
+	tc := []struct {
+		name        string
+		lastEventID string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		body        string
+	}{
+		{
+			name:        "resumes after the last event",
+			lastEventID: "41",
+			serviceStub: func(srv *mock_service.MockService) {
+				events := make(chan *event.Event, 1)
+				events <- &event.Event{ID: 42, Type: event.TypeDeleted, Namespace: "default", SchemaID: "config-schema"}
+				close(events)
+
+				srv.EXPECT().
+					SubscribeEvents(gomock.Any(), uint64(41)).
+					Times(1).
+					Return((<-chan *event.Event)(events))
+			},
+			statusCode: http.StatusOK,
+			body: "id: 42\nevent: schema.deleted\n" +
+				`data: {"id":42,"type":"schema.deleted","namespace":"default","name":"config-schema","createdAt":"0001-01-01T00:00:00Z"}` + "\n\n",
+		},
+		{
+			name:        "new subscriber",
+			lastEventID: "",
+			serviceStub: func(srv *mock_service.MockService) {
+				events := make(chan *event.Event)
+				close(events)
+
+				srv.EXPECT().
+					SubscribeEvents(gomock.Any(), uint64(0)).
+					Times(1).
+					Return((<-chan *event.Event)(events))
+			},
+			statusCode: http.StatusOK,
+			body:       "",
+		},
+		{
+			name:        "malformed Last-Event-ID",
+			lastEventID: "latest",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					SubscribeEvents(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/events", nil)
+			r.Header.Set("Last-Event-ID", tt.lastEventID)
+
+			h.Events()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			if tt.statusCode == http.StatusOK {
+				assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_Events_WriteTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := make(chan *event.Event)
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		SubscribeEvents(gomock.Any(), uint64(0)).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ uint64) <-chan *event.Event {
+			go func() {
+				<-ctx.Done()
+				close(events)
+			}()
+
+			return events
+		})
+
+	h := helperNewHandler(t, srv)
+	m := middleware.New(logruslog.DefaultLogger(&config.Config{}), nil, nil)
+
+	ts := httptest.NewUnstartedServer(m.AccessLog(h.Events()))
+	ts.Config.WriteTimeout = 50 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// the event is sent once the write timeout of the server has passed.
+	time.Sleep(200 * time.Millisecond)
+	events <- &event.Event{ID: 42, Type: event.TypeDeleted, Namespace: "default", SchemaID: "config-schema"}
+
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "id: 42\n", line)
+}
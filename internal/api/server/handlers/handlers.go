@@ -1,25 +1,36 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/KarolosLykos/json-validation-service/internal/config"
 	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
 	"github.com/KarolosLykos/json-validation-service/internal/service"
 	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsonguard"
 )
 
 type Handler struct {
+	cfg *config.Config
 	log logger.Logger
 	srv service.Service
 }
 
-func New(log logger.Logger, srv service.Service) *Handler {
+func New(cfg *config.Config, log logger.Logger, srv service.Service) *Handler {
 	return &Handler{
+		cfg: cfg,
 		log: log,
 		srv: srv,
 	}
@@ -44,13 +55,20 @@ func (h *Handler) Upload() http.HandlerFunc {
 		defer r.Body.Close()
 
 		if err != nil {
-			responseError(w, "uploadSchema", schemaID, err)
+			h.responseError(ctx, w, "uploadSchema", schemaID, err)
 
 			return
 		}
 
-		if err = h.srv.UploadSchema(ctx, schemaID, string(body)); err != nil {
-			responseError(w, "uploadSchema", schemaID, err)
+		payload, meta, err := parseUpload(r, body, jsonguard.FromConfig(h.cfg))
+		if err != nil {
+			h.responseError(ctx, w, "uploadSchema", schemaID, err)
+
+			return
+		}
+
+		if err = h.srv.UploadSchema(ctx, schemaID, payload, meta); err != nil {
+			h.responseError(ctx, w, "uploadSchema", schemaID, err)
 
 			return
 		}
@@ -59,6 +77,10 @@ func (h *Handler) Upload() http.HandlerFunc {
 	}
 }
 
+// SchemaContentType is the media type of a raw JSON Schema document.
+const SchemaContentType = "application/schema+json"
+
+// Download serves the schema in the response envelope, or raw when the client accepts SchemaContentType.
 func (h *Handler) Download() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -68,74 +90,424 @@ func (h *Handler) Download() http.HandlerFunc {
 
 		s, err := h.srv.DownloadSchema(ctx, schemaID)
 		if err != nil {
-			responseError(w, "downloadSchema", schemaID, err)
+			h.responseError(ctx, w, "downloadSchema", schemaID, err)
+
+			return
+		}
+
+		raw := accepts(r.Header.Get("Accept"), SchemaContentType)
+
+		etag := s.ETag()
+		if raw {
+			etag = s.RawETag()
+		}
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Vary", "Accept")
+
+		setLifecycleHeaders(w, r, s)
+
+		if raw {
+			// the schema behind an ID changes, caches must revalidate it with its ETag.
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+
+		if matchETag(r.Header.Get("If-None-Match"), etag, true) {
+			w.WriteHeader(http.StatusNotModified)
 
 			return
 		}
 
-		responseSuccess(w, http.StatusOK, "downloadSchema", schemaID, s)
+		if raw {
+			w.Header().Set("Content-Type", SchemaContentType)
+			w.WriteHeader(http.StatusOK)
+
+			if _, err = w.Write(*s.Schema); err != nil {
+				h.log.Error(ctx, err, "could not write schema")
+			}
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "downloadSchema", schemaID, s.Schema.String())
 	}
 }
 
-func (h *Handler) Validate() http.HandlerFunc {
+// DownloadByDigest serves immutable bodies, cacheable forever.
+func (h *Handler) DownloadByDigest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		digest := vars["sha256"]
+
+		s, err := h.srv.DownloadSchemaByDigest(ctx, digest)
+		if err != nil {
+			h.responseError(ctx, w, "downloadSchemaByDigest", digest, err)
+
+			return
+		}
+
+		etag := strconv.Quote(digest)
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+
+		if matchETag(r.Header.Get("If-None-Match"), etag, true) {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "downloadSchemaByDigest", digest, s)
+	}
+}
+
+func (h *Handler) Meta() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
 		vars := mux.Vars(r)
 		schemaID := vars["schemaID"]
 
-		payload := make(map[string]interface{})
+		s, err := h.srv.DownloadSchema(ctx, schemaID)
+		if err != nil {
+			h.responseError(ctx, w, "schemaMeta", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "schemaMeta", schemaID, s.Info())
+	}
+}
+
+func (h *Handler) Stats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		schemaID := vars["schemaID"]
 
-		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-			responseError(w, "validateSchema", schemaID, err)
+		stats, err := h.srv.SchemaStats(ctx, schemaID)
+		if err != nil {
+			h.responseError(ctx, w, "schemaStats", schemaID, err)
 
 			return
 		}
 
-		if err := h.srv.ValidateSchema(ctx, schemaID, payload); err != nil {
-			responseError(w, "validateSchema", schemaID, err)
+		responseSuccess(w, http.StatusOK, "schemaStats", schemaID, stats)
+	}
+}
+
+func (h *Handler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		filter, err := parseFilter(r)
+		if err != nil {
+			h.responseError(ctx, w, "listSchemas", "", err)
 
 			return
 		}
 
-		responseSuccess(w, http.StatusOK, "validateSchema", schemaID, nil)
+		schemas, err := h.srv.ListSchemas(ctx, filter)
+		if err != nil {
+			h.responseError(ctx, w, "listSchemas", "", err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listSchemas", "", infos(schemas))
 	}
 }
 
-func responseError(w http.ResponseWriter, action, schemaID string, errMsg error) {
-	statusCode := http.StatusInternalServerError
+func (h *Handler) Import() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		mode := schema.ImportMode(r.URL.Query().Get("mode"))
+		if mode == "" {
+			mode = schema.ImportFailOnConflict
+		}
+
+		schemas, err := readBundle(r, h.cfg.Limits.MaxBodyBytes, jsonguard.FromConfig(h.cfg))
+		defer r.Body.Close()
+
+		if err != nil {
+			h.responseError(ctx, w, "importSchemas", "", err)
+
+			return
+		}
+
+		res, err := h.srv.ImportSchemas(ctx, schemas, mode)
+		if err != nil {
+			h.responseError(ctx, w, "importSchemas", "", err)
+
+			return
+		}
 
-	switch {
-	case oneOf(errMsg,
-		io.EOF,
-		exceptions.ErrInvalidJSON,
-		exceptions.ErrNotFound,
-		exceptions.ErrValidation,
-	):
-		statusCode = http.StatusBadRequest
-	case oneOf(errMsg, exceptions.ErrAlreadyExists):
-		statusCode = http.StatusConflict
+		responseSuccess(w, http.StatusOK, "importSchemas", "", res)
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+func (h *Handler) Export() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
 
-	res := &Response{
-		Action:  action,
-		ID:      schemaID,
-		Status:  "error",
-		Message: errMsg.Error(),
+		filter, err := parseFilter(r)
+		if err != nil {
+			h.responseError(ctx, w, "exportSchemas", "", err)
+
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		tarGz := format == "tar.gz" || (format == "" && isTarGz(r.Header.Get("Accept")))
+
+		bw := newBundleWriter(w, tarGz)
+
+		if err = h.srv.ExportSchemas(ctx, filter, bw.Write); err == nil {
+			err = bw.Close()
+		}
+
+		if err != nil {
+			// once streaming started the status is sent, a truncated bundle is all that tells the client.
+			if bw.Started() {
+				h.log.Error(ctx, err, "could not export schemas")
+
+				return
+			}
+
+			h.responseError(ctx, w, "exportSchemas", "", err)
+		}
 	}
+}
 
-	payload, err := json.Marshal(res)
-	if err != nil {
-		http.Error(w, exceptions.ErrInternalServerError.Error(), http.StatusInternalServerError)
+func (h *Handler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		schemaID := vars["schemaID"]
+
+		body, err := io.ReadAll(r.Body)
+		defer r.Body.Close()
+
+		if err != nil {
+			h.responseError(ctx, w, "updateSchema", schemaID, err)
+
+			return
+		}
+
+		current, err := h.checkPrecondition(r, schemaID)
+		if err != nil {
+			h.responseError(ctx, w, "updateSchema", schemaID, err)
+
+			return
+		}
+
+		s, err := h.srv.UpdateSchema(ctx, schemaID, string(body), current.Digest)
+		if err != nil {
+			h.responseError(ctx, w, "updateSchema", schemaID, err)
+
+			return
+		}
+
+		w.Header().Set("ETag", s.ETag())
+
+		responseSuccess(w, http.StatusOK, "updateSchema", schemaID, nil)
 	}
+}
 
-	_, err = w.Write(payload)
-	if err != nil {
-		http.Error(w, exceptions.ErrInternalServerError.Error(), http.StatusInternalServerError)
+func (h *Handler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		schemaID := vars["schemaID"]
+
+		current, err := h.checkPrecondition(r, schemaID)
+		if err != nil {
+			h.responseError(ctx, w, "deleteSchema", schemaID, err)
+
+			return
+		}
+
+		if err = h.srv.DeleteSchema(ctx, schemaID, current.Digest); err != nil {
+			h.responseError(ctx, w, "deleteSchema", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "deleteSchema", schemaID, nil)
+	}
+}
+
+func (h *Handler) Trash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		schemas, err := h.srv.ListTrash(ctx)
+		if err != nil {
+			h.responseError(ctx, w, "listTrash", "", err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listTrash", "", infos(schemas))
+	}
+}
+
+func (h *Handler) Restore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		schemaID := vars["schemaID"]
+
+		if err := h.srv.RestoreSchema(ctx, schemaID); err != nil {
+			h.responseError(ctx, w, "restoreSchema", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "restoreSchema", schemaID, nil)
+	}
+}
+
+func (h *Handler) Audit() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		filter, err := parseAuditFilter(r)
+		if err != nil {
+			h.responseError(ctx, w, "listAudit", "", err)
+
+			return
+		}
+
+		entries, err := h.srv.ListAudit(ctx, filter)
+		if err != nil {
+			h.responseError(ctx, w, "listAudit", filter.SchemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listAudit", filter.SchemaID, entries)
+	}
+}
+
+func (h *Handler) Events() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			h.responseError(ctx, w, "streamEvents", "", exceptions.ErrStreamingUnsupported)
+
+			return
+		}
+
+		lastEventID, err := parseLastEventID(r)
+		if err != nil {
+			h.responseError(ctx, w, "streamEvents", "", err)
+
+			return
+		}
+
+		// streams are not bound by HTTP_WRITE_TIMEOUT, writers without a deadline have none to clear.
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		events := h.srv.SubscribeEvents(ctx, lastEventID)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+
+				err = writeEvent(w, e)
+			case <-heartbeat.C:
+				_, err = io.WriteString(w, ": heartbeat\n\n")
+			}
+
+			if err != nil {
+				return
+			}
+
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *Handler) Validate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		schemaID := vars["schemaID"]
+
+		payload := make(map[string]interface{})
+
+		if err := h.decode(r, &payload); err != nil {
+			h.responseError(ctx, w, "validateSchema", schemaID, err)
+
+			return
+		}
+
+		s, err := h.srv.ValidateSchema(ctx, schemaID, payload)
+
+		setLifecycleHeaders(w, r, s)
+
+		if err != nil {
+			h.responseError(ctx, w, "validateSchema", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "validateSchema", schemaID, lifecyclePayload(s))
+	}
+}
+
+// decode decodes the JSON document of the request body, unless it exceeds the limits.
+func (h *Handler) decode(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(jsonguard.NewReader(r.Body, jsonguard.FromConfig(h.cfg))).Decode(v)
+	if jsonguard.Exceeded(err) {
+		return fmt.Errorf("%w: %v", exceptions.ErrJSONTooComplex, err)
+	}
+
+	return err
+}
+
+// invalidRequest reports the request failing to decode as invalid, unless it exceeds the limits.
+func invalidRequest(invalid, err error) error {
+	if errors.Is(err, exceptions.ErrJSONTooComplex) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %v", invalid, err)
+}
+
+// responseError reports the error as a problem (RFC 7807), logging the server errors.
+func (h *Handler) responseError(ctx context.Context, w http.ResponseWriter, action, schemaID string, err error) {
+	p := exceptions.NewProblem(err)
+	p.Action, p.ID = action, schemaID
+
+	if p.Status >= http.StatusInternalServerError {
+		h.log.Error(ctx, err, "could not "+action)
 	}
+
+	p.Write(w)
 }
 
 func responseSuccess(w http.ResponseWriter, statusCode int, action, schemaID string, payload interface{}) {
@@ -163,11 +535,28 @@ func responseSuccess(w http.ResponseWriter, statusCode int, action, schemaID str
 	}
 }
 
-func oneOf(err error, errs ...error) bool {
-	for _, errr := range errs {
-		if errors.Is(err, errr) {
-			return true
+func infos(schemas []*schema.Schema) []*schema.Info {
+	res := make([]*schema.Info, 0, len(schemas))
+	for _, s := range schemas {
+		res = append(res, s.Info())
+	}
+
+	return res
+}
+
+// accepts tells whether the Accept header explicitly lists the media type, wildcards falling back to the envelope.
+func accepts(accept, mediaType string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil || mt != mediaType {
+			continue
+		}
+
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			continue
 		}
+
+		return true
 	}
 
 	return false
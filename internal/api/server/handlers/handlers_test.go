@@ -7,16 +7,23 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
 
 	"github.com/KarolosLykos/json-validation-service/internal/api/server/handlers"
 	"github.com/KarolosLykos/json-validation-service/internal/config"
 	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
 	"github.com/KarolosLykos/json-validation-service/internal/service"
 	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
 	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
@@ -30,6 +37,7 @@ func TestHandler_Download(t *testing.T) {
 		payload     string
 		statusCode  int
 		res         *handlers.Response
+		problem     *exceptions.Problem
 	}{
 		{
 			name:     "status created",
@@ -37,7 +45,7 @@ func TestHandler_Download(t *testing.T) {
 			payload:  "",
 			serviceStub: func(srv *mock_service.MockService) {
 				srv.EXPECT().
-					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(nil)
 			},
@@ -54,17 +62,12 @@ func TestHandler_Download(t *testing.T) {
 			payload:  "",
 			serviceStub: func(srv *mock_service.MockService) {
 				srv.EXPECT().
-					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(exceptions.ErrAlreadyExists)
 			},
 			statusCode: http.StatusConflict,
-			res: &handlers.Response{
-				Action:  "uploadSchema",
-				ID:      "config-schema",
-				Status:  "error",
-				Message: exceptions.ErrAlreadyExists.Error(),
-			},
+			problem:    helperProblem("uploadSchema", "config-schema", exceptions.ErrAlreadyExists),
 		},
 		{
 			name:     "internal server error",
@@ -72,17 +75,12 @@ func TestHandler_Download(t *testing.T) {
 			payload:  "",
 			serviceStub: func(srv *mock_service.MockService) {
 				srv.EXPECT().
-					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(exceptions.ErrCreateSchema)
 			},
 			statusCode: http.StatusInternalServerError,
-			res: &handlers.Response{
-				Action:  "uploadSchema",
-				ID:      "config-schema",
-				Status:  "error",
-				Message: exceptions.ErrCreateSchema.Error(),
-			},
+			problem:    helperProblem("uploadSchema", "config-schema", exceptions.ErrCreateSchema),
 		},
 	}
 
@@ -104,6 +102,12 @@ func TestHandler_Download(t *testing.T) {
 
 			require.Equal(t, tt.statusCode, w.Code)
 
+			if tt.problem != nil {
+				helperAssertProblem(t, tt.problem, w)
+
+				return
+			}
+
 			res := &handlers.Response{}
 			err := json.NewDecoder(w.Body).Decode(res)
 			require.NoError(t, err)
@@ -119,8 +123,11 @@ func TestHandler_Upload(t *testing.T) {
 		schemaID    string
 		serviceStub func(srv *mock_service.MockService)
 		schema      string
+		ifNoneMatch string
 		statusCode  int
+		etag        string
 		res         *handlers.Response
+		problem     *exceptions.Problem
 	}{
 		{
 			name:     "status ok",
@@ -130,9 +137,10 @@ func TestHandler_Upload(t *testing.T) {
 				srv.EXPECT().
 					DownloadSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(`{"valid":"schema"}`, nil)
+					Return(helperSchema(`{"valid":"schema"}`), nil)
 			},
 			statusCode: http.StatusOK,
+			etag:       helperSchema(`{"valid":"schema"}`).ETag(),
 			res: &handlers.Response{
 				Action:  "downloadSchema",
 				ID:      "config-schema",
@@ -141,22 +149,52 @@ func TestHandler_Upload(t *testing.T) {
 			},
 		},
 		{
-			name:     "not found",
-			schemaID: "config-schema",
-			schema:   "",
+			name:        "not modified",
+			schemaID:    "config-schema",
+			schema:      `{"valid":"schema"}`,
+			ifNoneMatch: `"other", ` + helperSchema(`{"valid":"schema"}`).ETag(),
 			serviceStub: func(srv *mock_service.MockService) {
 				srv.EXPECT().
 					DownloadSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return("", exceptions.ErrNotFound)
+					Return(helperSchema(`{"valid":"schema"}`), nil)
 			},
-			statusCode: http.StatusBadRequest,
+			statusCode: http.StatusNotModified,
+			etag:       helperSchema(`{"valid":"schema"}`).ETag(),
+			res:        nil,
+		},
+		{
+			name:        "modified",
+			schemaID:    "config-schema",
+			schema:      `{"valid":"schema"}`,
+			ifNoneMatch: `"other"`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(helperSchema(`{"valid":"schema"}`), nil)
+			},
+			statusCode: http.StatusOK,
+			etag:       helperSchema(`{"valid":"schema"}`).ETag(),
 			res: &handlers.Response{
 				Action:  "downloadSchema",
 				ID:      "config-schema",
-				Status:  "error",
-				Message: exceptions.ErrNotFound.Error(),
+				Status:  "success",
+				Payload: `{"valid":"schema"}`,
+			},
+		},
+		{
+			name:     "not found",
+			schemaID: "config-schema",
+			schema:   "",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, exceptions.ErrNotFound)
 			},
+			statusCode: http.StatusNotFound,
+			problem:    helperProblem("downloadSchema", "config-schema", exceptions.ErrNotFound),
 		},
 		{
 			name:     "internal server error",
@@ -166,15 +204,10 @@ func TestHandler_Upload(t *testing.T) {
 				srv.EXPECT().
 					DownloadSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return("", exceptions.ErrDownloadSchema)
+					Return(nil, exceptions.ErrDownloadSchema)
 			},
 			statusCode: http.StatusInternalServerError,
-			res: &handlers.Response{
-				Action:  "downloadSchema",
-				ID:      "config-schema",
-				Status:  "error",
-				Message: exceptions.ErrDownloadSchema.Error(),
-			},
+			problem:    helperProblem("downloadSchema", "config-schema", exceptions.ErrDownloadSchema),
 		},
 	}
 
@@ -191,10 +224,24 @@ func TestHandler_Upload(t *testing.T) {
 			w := httptest.NewRecorder()
 			r, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/schema/schemaID:%s", tt.schemaID), nil)
 			r = mux.SetURLVars(r, map[string]string{"schemaID": tt.schemaID})
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
 
 			h.Download()(w, r)
 
 			require.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.etag, w.Header().Get("ETag"))
+
+			if tt.res == nil && tt.problem == nil {
+				assert.Zero(t, w.Body.Len())
+
+				return
+			}
+
+			if tt.problem != nil {
+				helperAssertProblem(t, tt.problem, w)
+
+				return
+			}
 
 			res := &handlers.Response{}
 			err := json.NewDecoder(w.Body).Decode(res)
@@ -213,6 +260,7 @@ func TestHandler_Validate(t *testing.T) {
 		payload     string
 		statusCode  int
 		res         *handlers.Response
+		problem     *exceptions.Problem
 	}{
 		{
 			name:     "status ok",
@@ -222,7 +270,7 @@ func TestHandler_Validate(t *testing.T) {
 				srv.EXPECT().
 					ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(nil)
+					Return(helperSchema(`{}`), nil)
 			},
 			statusCode: http.StatusOK,
 			res: &handlers.Response{
@@ -238,16 +286,10 @@ func TestHandler_Validate(t *testing.T) {
 			serviceStub: func(srv *mock_service.MockService) {
 				srv.EXPECT().
 					ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
-					Times(0).
-					Return(nil)
+					Times(0)
 			},
 			statusCode: http.StatusBadRequest,
-			res: &handlers.Response{
-				Action:  "validateSchema",
-				ID:      "config-schema",
-				Status:  "error",
-				Message: io.EOF.Error(),
-			},
+			problem:    helperProblem("validateSchema", "config-schema", io.EOF),
 		},
 		{
 			name:     "not found",
@@ -257,15 +299,10 @@ func TestHandler_Validate(t *testing.T) {
 				srv.EXPECT().
 					ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(exceptions.ErrNotFound)
-			},
-			statusCode: http.StatusBadRequest,
-			res: &handlers.Response{
-				Action:  "validateSchema",
-				ID:      "config-schema",
-				Status:  "error",
-				Message: exceptions.ErrNotFound.Error(),
+					Return(nil, exceptions.ErrNotFound)
 			},
+			statusCode: http.StatusNotFound,
+			problem:    helperProblem("validateSchema", "config-schema", exceptions.ErrNotFound),
 		},
 		{
 			name:     "not valid payload",
@@ -275,15 +312,23 @@ func TestHandler_Validate(t *testing.T) {
 				srv.EXPECT().
 					ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(exceptions.ErrValidation)
+					Return(nil, exceptions.ErrValidation)
 			},
 			statusCode: http.StatusBadRequest,
-			res: &handlers.Response{
-				Action:  "validateSchema",
-				ID:      "config-schema",
-				Status:  "error",
-				Message: exceptions.ErrValidation.Error(),
+			problem:    helperProblem("validateSchema", "config-schema", exceptions.ErrValidation),
+		},
+		{
+			name:     "payload nested too deeply",
+			schemaID: "config-schema",
+			payload:  `{"a":` + strings.Repeat("[", 64) + strings.Repeat("]", 64) + "}",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
 			},
+			statusCode: http.StatusBadRequest,
+			problem: helperProblem("validateSchema", "config-schema",
+				fmt.Errorf("%w: %v", exceptions.ErrJSONTooComplex, "json nested too deeply: more than 64 levels")),
 		},
 	}
 
@@ -305,6 +350,12 @@ func TestHandler_Validate(t *testing.T) {
 
 			require.Equal(t, tt.statusCode, w.Code)
 
+			if tt.problem != nil {
+				helperAssertProblem(t, tt.problem, w)
+
+				return
+			}
+
 			res := &handlers.Response{}
 			err := json.NewDecoder(w.Body).Decode(res)
 			require.NoError(t, err)
@@ -314,11 +365,1044 @@ func TestHandler_Validate(t *testing.T) {
 	}
 }
 
-func helperNewHandler(t *testing.T, srv service.Service) *handlers.Handler {
-	t.Helper()
-	cfg, _ := config.Load()
+func TestHandler_DownloadRaw(t *testing.T) {
+	tc := []struct {
+		name         string
+		accept       string
+		ifNoneMatch  string
+		statusCode   int
+		contentType  string
+		cacheControl string
+		etag         string
+		body         string
+	}{
+		{
+			name:         "raw document",
+			accept:       "application/schema+json",
+			statusCode:   http.StatusOK,
+			contentType:  handlers.SchemaContentType,
+			cacheControl: "no-cache",
+			etag:         helperSchema(`{"valid":"schema"}`).RawETag(),
+			body:         `{"valid":"schema"}`,
+		},
+		{
+			name:         "preferred among others",
+			accept:       "application/json;q=0.5, application/schema+json",
+			statusCode:   http.StatusOK,
+			contentType:  handlers.SchemaContentType,
+			cacheControl: "no-cache",
+			etag:         helperSchema(`{"valid":"schema"}`).RawETag(),
+			body:         `{"valid":"schema"}`,
+		},
+		{
+			name:         "not modified",
+			accept:       "application/schema+json",
+			ifNoneMatch:  helperSchema(`{"valid":"schema"}`).RawETag(),
+			statusCode:   http.StatusNotModified,
+			cacheControl: "no-cache",
+			etag:         helperSchema(`{"valid":"schema"}`).RawETag(),
+		},
+		{
+			name:         "modified representation",
+			accept:       "application/schema+json",
+			ifNoneMatch:  helperSchema(`{"valid":"schema"}`).ETag(),
+			statusCode:   http.StatusOK,
+			contentType:  handlers.SchemaContentType,
+			cacheControl: "no-cache",
+			etag:         helperSchema(`{"valid":"schema"}`).RawETag(),
+			body:         `{"valid":"schema"}`,
+		},
+		{
+			name:        "refused",
+			accept:      "application/schema+json;q=0",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			etag:        helperSchema(`{"valid":"schema"}`).ETag(),
+			body:        `{"action":"downloadSchema","id":"config-schema","status":"success","payload":"{\"valid\":\"schema\"}"}`,
+		},
+		{
+			name:        "wildcard",
+			accept:      "*/*",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			etag:        helperSchema(`{"valid":"schema"}`).ETag(),
+			body:        `{"action":"downloadSchema","id":"config-schema","status":"success","payload":"{\"valid\":\"schema\"}"}`,
+		},
+	}
 
-	log := logruslog.DefaultLogger(cfg)
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			srv.EXPECT().
+				DownloadSchema(gomock.Any(), "config-schema").
+				Times(1).
+				Return(helperSchema(`{"valid":"schema"}`), nil)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/schema/config-schema", nil)
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "config-schema"})
+			r.Header.Set("Accept", tt.accept)
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+
+			h.Download()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.etag, w.Header().Get("ETag"))
+			assert.Equal(t, "Accept", w.Header().Get("Vary"))
+			assert.Equal(t, tt.cacheControl, w.Header().Get("Cache-Control"))
+
+			if tt.statusCode == http.StatusOK {
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_DownloadByDigest(t *testing.T) {
+	digest := schema.Digest(`{"valid":"schema"}`)
+
+	tc := []struct {
+		name        string
+		ifNoneMatch string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		res         *handlers.Response
+		problem     *exceptions.Problem
+	}{
+		{
+			name: "status ok",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchemaByDigest(gomock.Any(), digest).
+					Times(1).
+					Return(`{"valid":"schema"}`, nil)
+			},
+			statusCode: http.StatusOK,
+			res: &handlers.Response{
+				Action:  "downloadSchemaByDigest",
+				ID:      digest,
+				Status:  "success",
+				Payload: `{"valid":"schema"}`,
+			},
+		},
+		{
+			name:        "not modified",
+			ifNoneMatch: `"` + digest + `"`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchemaByDigest(gomock.Any(), digest).
+					Times(1).
+					Return(`{"valid":"schema"}`, nil)
+			},
+			statusCode: http.StatusNotModified,
+		},
+		{
+			name: "not found",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchemaByDigest(gomock.Any(), digest).
+					Times(1).
+					Return("", exceptions.ErrNotFound)
+			},
+			statusCode: http.StatusNotFound,
+			problem:    helperProblem("downloadSchemaByDigest", digest, exceptions.ErrNotFound),
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/schema/by-digest/%s", digest), nil)
+			r = mux.SetURLVars(r, map[string]string{"sha256": digest})
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+
+			h.DownloadByDigest()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			if tt.statusCode < http.StatusBadRequest {
+				assert.Equal(t, `"`+digest+`"`, w.Header().Get("ETag"))
+				assert.Contains(t, w.Header().Get("Cache-Control"), "immutable")
+			}
+
+			if tt.res == nil && tt.problem == nil {
+				assert.Zero(t, w.Body.Len())
+
+				return
+			}
+
+			if tt.problem != nil {
+				helperAssertProblem(t, tt.problem, w)
+
+				return
+			}
+
+			res := &handlers.Response{}
+			err := json.NewDecoder(w.Body).Decode(res)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.res, res)
+		})
+	}
+}
+
+func TestHandler_Update(t *testing.T) {
+	current := helperSchema(`{"valid":"schema"}`)
+	updated := helperSchema(`{"updated":"schema"}`)
+
+	tc := []struct {
+		name        string
+		schemaID    string
+		ifMatch     string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		etag        string
+		res         *handlers.Response
+		problem     *exceptions.Problem
+	}{
+		{
+			name:     "status ok",
+			schemaID: "config-schema",
+			ifMatch:  current.ETag(),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+				srv.EXPECT().
+					UpdateSchema(gomock.Any(), "config-schema", `{"updated":"schema"}`, current.Digest).
+					Times(1).
+					Return(updated, nil)
+			},
+			statusCode: http.StatusOK,
+			etag:       updated.ETag(),
+			res: &handlers.Response{
+				Action: "updateSchema",
+				ID:     "config-schema",
+				Status: "success",
+			},
+		},
+		{
+			name:     "etag of the raw document",
+			schemaID: "config-schema",
+			ifMatch:  current.RawETag(),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+				srv.EXPECT().
+					UpdateSchema(gomock.Any(), "config-schema", `{"updated":"schema"}`, current.Digest).
+					Times(1).
+					Return(updated, nil)
+			},
+			statusCode: http.StatusOK,
+			etag:       updated.ETag(),
+			res: &handlers.Response{
+				Action: "updateSchema",
+				ID:     "config-schema",
+				Status: "success",
+			},
+		},
+		{
+			name:     "wildcard",
+			schemaID: "config-schema",
+			ifMatch:  "*",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+				srv.EXPECT().
+					UpdateSchema(gomock.Any(), "config-schema", `{"updated":"schema"}`, current.Digest).
+					Times(1).
+					Return(updated, nil)
+			},
+			statusCode: http.StatusOK,
+			etag:       updated.ETag(),
+			res: &handlers.Response{
+				Action: "updateSchema",
+				ID:     "config-schema",
+				Status: "success",
+			},
+		},
+		{
+			name:     "precondition required",
+			schemaID: "config-schema",
+			ifMatch:  "",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusPreconditionRequired,
+			problem:    helperProblem("updateSchema", "config-schema", exceptions.ErrPreconditionRequired),
+		},
+		{
+			name:     "precondition failed",
+			schemaID: "config-schema",
+			ifMatch:  updated.ETag(),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+				srv.EXPECT().
+					UpdateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusPreconditionFailed,
+			problem:    helperProblem("updateSchema", "config-schema", exceptions.ErrPreconditionFailed),
+		},
+		{
+			name:     "weak etag does not match",
+			schemaID: "config-schema",
+			ifMatch:  "W/" + current.ETag(),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+			},
+			statusCode: http.StatusPreconditionFailed,
+			problem:    helperProblem("updateSchema", "config-schema", exceptions.ErrPreconditionFailed),
+		},
+		{
+			name:     "modified concurrently",
+			schemaID: "config-schema",
+			ifMatch:  current.ETag(),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+				srv.EXPECT().
+					UpdateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, exceptions.ErrPreconditionFailed)
+			},
+			statusCode: http.StatusPreconditionFailed,
+			problem:    helperProblem("updateSchema", "config-schema", exceptions.ErrPreconditionFailed),
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/schema/%s", tt.schemaID), bytes.NewBuffer([]byte(`{"updated":"schema"}`)))
+			r = mux.SetURLVars(r, map[string]string{"schemaID": tt.schemaID})
+			r.Header.Set("If-Match", tt.ifMatch)
+
+			h.Update()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.etag, w.Header().Get("ETag"))
+
+			if tt.problem != nil {
+				helperAssertProblem(t, tt.problem, w)
+
+				return
+			}
+
+			res := &handlers.Response{}
+			err := json.NewDecoder(w.Body).Decode(res)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.res, res)
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	current := helperSchema(`{"valid":"schema"}`)
+
+	tc := []struct {
+		name        string
+		schemaID    string
+		ifMatch     string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		res         *handlers.Response
+		problem     *exceptions.Problem
+	}{
+		{
+			name:     "status ok",
+			schemaID: "config-schema",
+			ifMatch:  current.ETag(),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+				srv.EXPECT().
+					DeleteSchema(gomock.Any(), "config-schema", current.Digest).
+					Times(1).
+					Return(nil)
+			},
+			statusCode: http.StatusOK,
+			res: &handlers.Response{
+				Action: "deleteSchema",
+				ID:     "config-schema",
+				Status: "success",
+			},
+		},
+		{
+			name:     "precondition required",
+			schemaID: "config-schema",
+			ifMatch:  "",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DeleteSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusPreconditionRequired,
+			problem:    helperProblem("deleteSchema", "config-schema", exceptions.ErrPreconditionRequired),
+		},
+		{
+			name:     "precondition failed",
+			schemaID: "config-schema",
+			ifMatch:  `"stale"`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+			},
+			statusCode: http.StatusPreconditionFailed,
+			problem:    helperProblem("deleteSchema", "config-schema", exceptions.ErrPreconditionFailed),
+		},
+		{
+			name:     "internal server error",
+			schemaID: "config-schema",
+			ifMatch:  current.ETag(),
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					DownloadSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(current, nil)
+				srv.EXPECT().
+					DeleteSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(exceptions.ErrDeleteSchema)
+			},
+			statusCode: http.StatusInternalServerError,
+			problem:    helperProblem("deleteSchema", "config-schema", exceptions.ErrDeleteSchema),
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/schema/%s", tt.schemaID), nil)
+			r = mux.SetURLVars(r, map[string]string{"schemaID": tt.schemaID})
+			r.Header.Set("If-Match", tt.ifMatch)
+
+			h.Delete()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			if tt.problem != nil {
+				helperAssertProblem(t, tt.problem, w)
+
+				return
+			}
+
+			res := &handlers.Response{}
+			err := json.NewDecoder(w.Body).Decode(res)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.res, res)
+		})
+	}
+}
+
+func TestHandler_UploadMetadata(t *testing.T) {
+	tc := []struct {
+		name        string
+		contentType string
+		headers     map[string]string
+		body        string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name: "headers",
+			headers: map[string]string{
+				"X-Schema-Description": "service configuration",
+				"X-Schema-Owner":       "platform",
+				"X-Schema-Labels":      "env=prod, tier=1",
+			},
+			body: `{"type":"object"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UploadSchema(gomock.Any(), "config-schema", `{"type":"object"}`, schema.Metadata{
+						Description: "service configuration",
+						Owner:       "platform",
+						Labels:      schema.Labels{"env": "prod", "tier": "1"},
+					}).
+					Times(1).
+					Return(nil)
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:        "envelope",
+			contentType: handlers.EnvelopeContentType + "; charset=utf-8",
+			body:        `{"schema":{"type":"object"},"owner":"platform","labels":{"env":"prod"}}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UploadSchema(gomock.Any(), "config-schema", `{"type":"object"}`, schema.Metadata{
+						Owner:  "platform",
+						Labels: schema.Labels{"env": "prod"},
+					}).
+					Times(1).
+					Return(nil)
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:        "envelope without schema",
+			contentType: handlers.EnvelopeContentType,
+			body:        `{"owner":"platform"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "envelope nested too deeply",
+			contentType: handlers.EnvelopeContentType,
+			body:        `{"schema":{},"labels":` + strings.Repeat("[", 64) + strings.Repeat("]", 64) + `}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "malformed labels",
+			headers: map[string]string{"X-Schema-Labels": "env"},
+			body:    `{"type":"object"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/schema/config-schema", bytes.NewBuffer([]byte(tt.body)))
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "config-schema"})
+			r.Header.Set("Content-Type", tt.contentType)
+
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			h.Upload()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_Meta(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := helperSchema(`{"valid":"schema"}`)
+	s.Owner = "platform"
+	s.Labels = schema.Labels{"env": "prod"}
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		DownloadSchema(gomock.Any(), "config-schema").
+		Times(1).
+		Return(s, nil)
+
+	h := helperNewHandler(t, srv)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/schema/config-schema/meta", nil)
+	r = mux.SetURLVars(r, map[string]string{"schemaID": "config-schema"})
+
+	h.Meta()(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	res := &struct {
+		Payload *schema.Info `json:"payload"`
+	}{}
+	err := json.NewDecoder(w.Body).Decode(res)
+	require.NoError(t, err)
+
+	assert.Equal(t, s.Info(), res.Payload)
+	assert.NotContains(t, w.Body.String(), "valid")
+}
+
+func TestHandler_Stats(t *testing.T) {
+	tc := []struct {
+		name        string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		res         *validation.Stats
+	}{
+		{
+			name: "status ok",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					SchemaStats(gomock.Any(), "config-schema").
+					Times(1).
+					Return(&validation.Stats{
+						SchemaID:   "config-schema",
+						SampleRate: 1,
+						Windows: []*validation.Window{
+							{Window: "1h0m0s", Total: 4, Failed: 1, FailureRate: 0.25, AvgLatency: 120},
+						},
+						TopFailingPaths: []*validation.Path{
+							{InstancePath: "#/source", Keyword: "type", Count: 1},
+						},
+					}, nil)
+			},
+			statusCode: http.StatusOK,
+			res: &validation.Stats{
+				SchemaID:   "config-schema",
+				SampleRate: 1,
+				Windows: []*validation.Window{
+					{Window: "1h0m0s", Total: 4, Failed: 1, FailureRate: 0.25, AvgLatency: 120},
+				},
+				TopFailingPaths: []*validation.Path{
+					{InstancePath: "#/source", Keyword: "type", Count: 1},
+				},
+			},
+		},
+		{
+			name: "not found",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					SchemaStats(gomock.Any(), "config-schema").
+					Times(1).
+					Return(nil, exceptions.ErrNotFound)
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "internal server error",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					SchemaStats(gomock.Any(), "config-schema").
+					Times(1).
+					Return(nil, exceptions.ErrSchemaStats)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/schema/config-schema/stats", nil)
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "config-schema"})
+
+			h.Stats()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			if tt.res != nil {
+				res := &struct {
+					Payload *validation.Stats `json:"payload"`
+				}{}
+				require.NoError(t, json.NewDecoder(w.Body).Decode(res))
+
+				assert.Equal(t, tt.res, res.Payload)
+			}
+		})
+	}
+}
+
+func TestHandler_List(t *testing.T) {
+	tc := []struct {
+		name        string
+		query       string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		count       int
+	}{
+		{
+			name:  "filtered",
+			query: "?owner=platform&label=env=prod&label=tier=1",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListSchemas(gomock.Any(), schema.Filter{
+						Owner:  "platform",
+						Labels: schema.Labels{"env": "prod", "tier": "1"},
+					}).
+					Times(1).
+					Return([]*schema.Schema{helperSchema(`{"valid":"schema"}`)}, nil)
+			},
+			statusCode: http.StatusOK,
+			count:      1,
+		},
+		{
+			name:  "malformed label",
+			query: "?label=env",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListSchemas(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "internal server error",
+			query: "",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListSchemas(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, exceptions.ErrListSchemas)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/schemas"+tt.query, nil)
+
+			h.List()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			res := &struct {
+				Payload []*schema.Info `json:"payload"`
+			}{}
+			err := json.NewDecoder(w.Body).Decode(res)
+			require.NoError(t, err)
+
+			assert.Len(t, res.Payload, tt.count)
+		})
+	}
+}
+
+func TestHandler_Trash(t *testing.T) {
+	deleted := helperSchema(`{"valid":"schema"}`)
+	deleted.DeletedAt = gorm.DeletedAt{Time: time.Date(2022, 11, 30, 12, 0, 0, 0, time.UTC), Valid: true}
+
+	tc := []struct {
+		name        string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		count       int
+	}{
+		{
+			name: "status ok",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListTrash(gomock.Any()).
+					Times(1).
+					Return([]*schema.Schema{deleted}, nil)
+			},
+			statusCode: http.StatusOK,
+			count:      1,
+		},
+		{
+			name: "internal server error",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListTrash(gomock.Any()).
+					Times(1).
+					Return(nil, exceptions.ErrListTrash)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/trash", nil)
+
+			h.Trash()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			res := &struct {
+				Payload []*schema.Info `json:"payload"`
+			}{}
+			err := json.NewDecoder(w.Body).Decode(res)
+			require.NoError(t, err)
+
+			require.Len(t, res.Payload, tt.count)
+
+			for _, info := range res.Payload {
+				require.NotNil(t, info.DeletedAt)
+				assert.True(t, deleted.DeletedAt.Time.Equal(*info.DeletedAt))
+			}
+		})
+	}
+}
+
+func TestHandler_Restore(t *testing.T) {
+	tc := []struct {
+		name        string
+		schemaID    string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		res         *handlers.Response
+		problem     *exceptions.Problem
+	}{
+		{
+			name:     "status ok",
+			schemaID: "config-schema",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					RestoreSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(nil)
+			},
+			statusCode: http.StatusOK,
+			res: &handlers.Response{
+				Action: "restoreSchema",
+				ID:     "config-schema",
+				Status: "success",
+			},
+		},
+		{
+			name:     "not in trash",
+			schemaID: "config-schema",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					RestoreSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(exceptions.ErrNotFound)
+			},
+			statusCode: http.StatusNotFound,
+			problem:    helperProblem("restoreSchema", "config-schema", exceptions.ErrNotFound),
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/schema/%s/restore", tt.schemaID), nil)
+			r = mux.SetURLVars(r, map[string]string{"schemaID": tt.schemaID})
+
+			h.Restore()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			if tt.problem != nil {
+				helperAssertProblem(t, tt.problem, w)
+
+				return
+			}
+
+			res := &handlers.Response{}
+			err := json.NewDecoder(w.Body).Decode(res)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.res, res)
+		})
+	}
+}
+
+func TestHandler_Audit(t *testing.T) {
+	tc := []struct {
+		name        string
+		query       string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		count       int
+	}{
+		{
+			name:  "filtered",
+			query: "?schemaID=config-schema&since=2022-11-30T12:00:00Z&limit=5000",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListAudit(gomock.Any(), audit.Filter{
+						SchemaID: "config-schema",
+						Since:    time.Date(2022, 11, 30, 12, 0, 0, 0, time.UTC),
+						Limit:    1000,
+					}).
+					Times(1).
+					Return([]*audit.Entry{
+						{SchemaID: "config-schema", Action: audit.ActionCreate, Actor: "anonymous"},
+						{SchemaID: "config-schema", Action: audit.ActionUpdate, Actor: "anonymous"},
+					}, nil)
+			},
+			statusCode: http.StatusOK,
+			count:      2,
+		},
+		{
+			name:  "default limit",
+			query: "",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListAudit(gomock.Any(), audit.Filter{Limit: 100}).
+					Times(1).
+					Return([]*audit.Entry{}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "invalid since",
+			query: "?since=yesterday",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListAudit(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid limit",
+			query: "?limit=-1",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListAudit(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/audit"+tt.query, nil)
+
+			h.Audit()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			res := &struct {
+				Payload []*audit.Entry `json:"payload"`
+			}{}
+			err := json.NewDecoder(w.Body).Decode(res)
+			require.NoError(t, err)
+
+			assert.Len(t, res.Payload, tt.count)
+		})
+	}
+}
+
+func helperNewHandler(t *testing.T, srv service.Service) *handlers.Handler {
+	t.Helper()
+	cfg, _ := config.Load()
+
+	log := logruslog.DefaultLogger(cfg)
+
+	return handlers.New(cfg, log, srv)
+}
+
+func helperSchema(payload string) *schema.Schema {
+	s := datatypes.JSON(payload)
+
+	return &schema.Schema{
+		SchemaID: "config-schema",
+		Schema:   &s,
+		Digest:   schema.Digest(payload),
+		Revision: 1,
+	}
+}
+
+func helperProblem(action, id string, err error) *exceptions.Problem {
+	p := exceptions.NewProblem(err)
+	p.Action, p.ID = action, id
+
+	return p
+}
+
+func helperAssertProblem(t *testing.T, expected *exceptions.Problem, w *httptest.ResponseRecorder) {
+	t.Helper()
 
-	return handlers.New(log, srv)
+	assert.Equal(t, expected.Status, w.Code)
+	assert.Equal(t, expected, helperDecodeProblem(t, w))
 }
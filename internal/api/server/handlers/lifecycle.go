@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// LifecycleRequest moves a schema to another lifecycle state.
+type LifecycleRequest struct {
+	State     string     `json:"state"`
+	Sunset    *time.Time `json:"sunset,omitempty"`
+	Successor string     `json:"successor,omitempty"`
+}
+
+func (h *Handler) UpdateLifecycle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		schemaID := mux.Vars(r)["schemaID"]
+
+		req := &LifecycleRequest{}
+
+		if err := h.decode(r, req); err != nil {
+			h.responseError(ctx, w, "updateLifecycle", schemaID, invalidRequest(exceptions.ErrInvalidLifecycle, err))
+
+			return
+		}
+
+		s, err := h.srv.UpdateLifecycle(ctx, schemaID, schema.Lifecycle{
+			State:     req.State,
+			Sunset:    req.Sunset,
+			Successor: req.Successor,
+		})
+		if err != nil {
+			h.responseError(ctx, w, "updateLifecycle", schemaID, err)
+
+			return
+		}
+
+		setLifecycleHeaders(w, r, s)
+
+		responseSuccess(w, http.StatusOK, "updateLifecycle", schemaID, s.Info())
+	}
+}
+
+// setLifecycleHeaders sets the Deprecation, Sunset (RFC 8594) and successor Link headers of a deprecated schema.
+func setLifecycleHeaders(w http.ResponseWriter, r *http.Request, s *schema.Schema) {
+	if s == nil || !s.Deprecated() {
+		return
+	}
+
+	deprecation := "true"
+	if s.DeprecatedAt != nil {
+		deprecation = "@" + strconv.FormatInt(s.DeprecatedAt.Unix(), 10)
+	}
+
+	w.Header().Set("Deprecation", deprecation)
+
+	if s.Sunset != nil {
+		w.Header().Set("Sunset", s.Sunset.UTC().Format(http.TimeFormat))
+	}
+
+	if s.Successor != "" {
+		link := "/v1/ns/" + contexts.Namespace(r.Context()) + "/schema/" + url.PathEscape(s.Successor)
+		w.Header().Add("Link", "<"+link+`>; rel="successor-version"`)
+	}
+}
+
+func lifecyclePayload(s *schema.Schema) interface{} {
+	if s == nil || !s.Deprecated() {
+		return nil
+	}
+
+	return &s.Lifecycle
+}
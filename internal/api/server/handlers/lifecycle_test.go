@@ -0,0 +1,177 @@
+package handlers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestHandler_UpdateLifecycle(t *testing.T) {
+	tc := []struct {
+		name        string
+		body        string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name: "status ok",
+			body: `{"state":"deprecated","successor":"orders-v2"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UpdateLifecycle(gomock.Any(), "orders", schema.Lifecycle{State: schema.StateDeprecated, Successor: "orders-v2"}).
+					Times(1).
+					Return(helperDeprecatedSchema(), nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "malformed body",
+			body: `{"state":`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UpdateLifecycle(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid transition",
+			body: `{"state":"draft"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UpdateLifecycle(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, exceptions.ErrInvalidLifecycle)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "concurrent transition",
+			body: `{"state":"retired"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UpdateLifecycle(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, exceptions.ErrPreconditionFailed)
+			},
+			statusCode: http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPut, "/schema/orders/lifecycle", bytes.NewBufferString(tt.body))
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "orders"})
+
+			h.UpdateLifecycle()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_LifecycleHeaders(t *testing.T) {
+	tc := []struct {
+		name        string
+		schema      *schema.Schema
+		err         error
+		statusCode  int
+		deprecation string
+	}{
+		{
+			name:        "deprecated",
+			schema:      helperDeprecatedSchema(),
+			statusCode:  http.StatusOK,
+			deprecation: "@1893456000",
+		},
+		{
+			name:       "active",
+			schema:     helperSchema(`{}`),
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "retired",
+			schema: func() *schema.Schema {
+				s := helperDeprecatedSchema()
+				s.State = schema.StateRetired
+
+				return s
+			}(),
+			err:         exceptions.ErrRetired,
+			statusCode:  http.StatusGone,
+			deprecation: "@1893456000",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			srv.EXPECT().ValidateSchema(gomock.Any(), "orders", gomock.Any()).Times(1).Return(tt.schema, tt.err)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/validate/orders", bytes.NewBufferString(`{}`))
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "orders"})
+
+			h.Validate()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.deprecation, w.Header().Get("Deprecation"))
+
+			if tt.deprecation != "" {
+				assert.Equal(t, "Wed, 01 Jan 2031 00:00:00 GMT", w.Header().Get("Sunset"))
+				assert.Equal(t, `</v1/ns/default/schema/orders-v2>; rel="successor-version"`, w.Header().Get("Link"))
+			}
+		})
+	}
+}
+
+func TestHandler_DownloadDeprecated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().DownloadSchema(gomock.Any(), "orders").Times(1).Return(helperDeprecatedSchema(), nil)
+
+	h := helperNewHandler(t, srv)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/schema/orders", nil)
+	r = mux.SetURLVars(r, map[string]string{"schemaID": "orders"})
+
+	h.Download()(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1893456000", w.Header().Get("Deprecation"))
+}
+
+func helperDeprecatedSchema() *schema.Schema {
+	deprecatedAt := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sunset := deprecatedAt.AddDate(1, 0, 0)
+
+	s := helperSchema(`{}`)
+	s.Lifecycle = schema.Lifecycle{
+		State:        schema.StateDeprecated,
+		DeprecatedAt: &deprecatedAt,
+		Sunset:       &sunset,
+		Successor:    "orders-v2",
+	}
+
+	return s
+}
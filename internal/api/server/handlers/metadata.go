@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsonguard"
+)
+
+// EnvelopeContentType wraps the schema of an upload along with its metadata.
+const EnvelopeContentType = "application/vnd.schema-envelope+json"
+
+type Envelope struct {
+	Schema      json.RawMessage `json:"schema"`
+	Description string          `json:"description"`
+	Owner       string          `json:"owner"`
+	Labels      schema.Labels   `json:"labels"`
+	State       string          `json:"state"`
+}
+
+// parseUpload reads the metadata from the envelope, or else from the X-Schema-* headers.
+func parseUpload(r *http.Request, body []byte, limits jsonguard.Limits) (string, schema.Metadata, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	if mediaType == EnvelopeContentType {
+		// the schema sits one level down the envelope.
+		if limits.MaxDepth > 0 {
+			limits.MaxDepth++
+		}
+
+		env := &Envelope{}
+
+		err := jsonguard.Unmarshal(body, env, limits)
+		if jsonguard.Exceeded(err) {
+			return "", schema.Metadata{}, fmt.Errorf("%w: %v", exceptions.ErrJSONTooComplex, err)
+		}
+
+		if err != nil || len(env.Schema) == 0 {
+			return "", schema.Metadata{}, exceptions.ErrInvalidJSON
+		}
+
+		return string(env.Schema), schema.Metadata{
+			Description: env.Description,
+			Owner:       env.Owner,
+			Labels:      env.Labels,
+			Lifecycle:   schema.Lifecycle{State: env.State},
+		}, nil
+	}
+
+	labels, err := schema.ParseLabels(r.Header.Get("X-Schema-Labels"))
+	if err != nil {
+		return "", schema.Metadata{}, fmt.Errorf("%w: %v", exceptions.ErrInvalidMetadata, err)
+	}
+
+	return string(body), schema.Metadata{
+		Description: r.Header.Get("X-Schema-Description"),
+		Owner:       r.Header.Get("X-Schema-Owner"),
+		Labels:      labels,
+		Lifecycle:   schema.Lifecycle{State: r.Header.Get("X-Schema-State")},
+	}, nil
+}
+
+// parseFilter reads the labels from repeated label=key=value parameters.
+func parseFilter(r *http.Request) (schema.Filter, error) {
+	q := r.URL.Query()
+
+	filter := schema.Filter{
+		Owner:  q.Get("owner"),
+		Labels: schema.Labels{},
+	}
+
+	for _, l := range q["label"] {
+		labels, err := schema.ParseLabels(l)
+		if err != nil {
+			return schema.Filter{}, fmt.Errorf("%w: %v", exceptions.ErrInvalidMetadata, err)
+		}
+
+		for k, v := range labels {
+			filter.Labels[k] = v
+		}
+	}
+
+	return filter, nil
+}
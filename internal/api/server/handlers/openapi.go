@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPI is the OpenAPI 3.1 description of the API.
+//
+//go:embed openapi.json
+var openAPI []byte
+
+func (h *Handler) OpenAPI() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, err := w.Write(openAPI); err != nil {
+			h.log.Error(r.Context(), err, "could not write the openapi document")
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestHandler_ProblemCatalogue(t *testing.T) {
+	for _, e := range exceptions.Catalogue() {
+		e := e
+
+		t.Run(e.Code, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			srv.EXPECT().
+				ValidateSchema(gomock.Any(), "config-schema", gomock.Any()).
+				Times(1).
+				Return(nil, fmt.Errorf("%w: pq: relation \"schemas\" does not exist", e))
+
+			w := helperServeValidate(t, srv, `{}`)
+
+			require.Equal(t, e.Status, w.Code)
+
+			problem := helperDecodeProblem(t, w)
+			assert.Equal(t, e.Code, problem.Code)
+			assert.Equal(t, e.Message, problem.Title)
+			assert.Equal(t, e.Status, problem.Status)
+			assert.Contains(t, problem.Type, "#"+e.Code)
+			assert.Equal(t, "validateSchema", problem.Action)
+			assert.Equal(t, "config-schema", problem.ID)
+
+			if e.Status >= http.StatusInternalServerError {
+				assert.Empty(t, problem.Detail)
+				assert.Equal(t, e.Message, problem.Message)
+			} else {
+				assert.Contains(t, problem.Detail, e.Message)
+				assert.Equal(t, problem.Detail, problem.Message)
+			}
+		})
+	}
+}
+
+func TestHandler_ProblemUnknownError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, errors.New("pq: password authentication failed for user \"postgres\""))
+
+	w := helperServeValidate(t, srv, `{}`)
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "postgres")
+
+	problem := helperDecodeProblem(t, w)
+	assert.Equal(t, exceptions.ErrInternalServerError.Code, problem.Code)
+	assert.Empty(t, problem.Detail)
+}
+
+func TestHandler_ProblemInvalidJSON(t *testing.T) {
+	tc := []struct {
+		name string
+		body string
+	}{
+		{name: "empty body", body: ""},
+		{name: "syntax error", body: `{"valid":}`},
+		{name: "unexpected type", body: `["valid"]`},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			srv.EXPECT().ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			w := helperServeValidate(t, srv, tt.body)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, exceptions.ErrInvalidJSON.Code, helperDecodeProblem(t, w).Code)
+		})
+	}
+}
+
+func helperServeValidate(t *testing.T, srv *mock_service.MockService, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	h := helperNewHandler(t, srv)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodPost, "/validate/config-schema", bytes.NewBufferString(body))
+	r = mux.SetURLVars(r, map[string]string{"schemaID": "config-schema"})
+
+	h.Validate()(w, r)
+
+	return w
+}
+
+func helperDecodeProblem(t *testing.T, w *httptest.ResponseRecorder) *exceptions.Problem {
+	t.Helper()
+
+	assert.Equal(t, exceptions.ProblemContentType, w.Header().Get("Content-Type"))
+
+	problem := &exceptions.Problem{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(problem))
+
+	return problem
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// QuarantineSettingsRequest opts a schema in or out of the quarantine of its failed payloads.
+type QuarantineSettingsRequest struct {
+	Enabled bool                `json:"enabled"`
+	Redact  quarantine.Pointers `json:"redact,omitempty"`
+}
+
+func (h *Handler) QuarantineSettings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		schemaID := mux.Vars(r)["schemaID"]
+
+		settings, err := h.srv.GetQuarantineSettings(ctx, schemaID)
+		if err != nil {
+			h.responseError(ctx, w, "quarantineSettings", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "quarantineSettings", schemaID, settings)
+	}
+}
+
+func (h *Handler) UpdateQuarantineSettings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		schemaID := mux.Vars(r)["schemaID"]
+
+		req := &QuarantineSettingsRequest{}
+
+		if err := h.decode(r, req); err != nil {
+			h.responseError(ctx, w, "updateQuarantineSettings", schemaID, invalidRequest(exceptions.ErrInvalidSettings, err))
+
+			return
+		}
+
+		settings := &quarantine.Settings{
+			SchemaID: schemaID,
+			Enabled:  req.Enabled,
+			Redact:   req.Redact,
+		}
+
+		if err := h.srv.UpdateQuarantineSettings(ctx, settings); err != nil {
+			h.responseError(ctx, w, "updateQuarantineSettings", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "updateQuarantineSettings", schemaID, settings)
+	}
+}
+
+func (h *Handler) Quarantine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		schemaID := mux.Vars(r)["schemaID"]
+
+		limit, err := parseLimit(r)
+		if err != nil {
+			h.responseError(ctx, w, "listQuarantine", schemaID, err)
+
+			return
+		}
+
+		entries, err := h.srv.ListQuarantine(ctx, schemaID, limit)
+		if err != nil {
+			h.responseError(ctx, w, "listQuarantine", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listQuarantine", schemaID, entries)
+	}
+}
+
+// Replay validates the quarantined payloads against the version given in the query, the current one by default.
+func (h *Handler) Replay() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		schemaID := mux.Vars(r)["schemaID"]
+
+		version := 0
+
+		if v := r.URL.Query().Get("version"); v != "" {
+			var err error
+
+			version, err = strconv.Atoi(v)
+			if err != nil || version <= 0 {
+				h.responseError(ctx, w, "replayQuarantine", schemaID, fmt.Errorf("%w: version: %q", exceptions.ErrInvalidQuery, v))
+
+				return
+			}
+		}
+
+		replay, err := h.srv.ReplayQuarantine(ctx, schemaID, version)
+		if err != nil {
+			h.responseError(ctx, w, "replayQuarantine", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "replayQuarantine", schemaID, replay)
+	}
+}
@@ -0,0 +1,201 @@
+package handlers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestHandler_UpdateQuarantineSettings(t *testing.T) {
+	tc := []struct {
+		name        string
+		body        string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name: "status ok",
+			body: `{"enabled":true,"redact":["/user/password"]}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					UpdateQuarantineSettings(gomock.Any(), &quarantine.Settings{
+						SchemaID: "config-schema",
+						Enabled:  true,
+						Redact:   quarantine.Pointers{"/user/password"},
+					}).
+					Times(1).
+					Return(nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "malformed body",
+			body: `{"enabled":`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UpdateQuarantineSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid settings",
+			body: `{"enabled":true,"redact":["password"]}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UpdateQuarantineSettings(gomock.Any(), gomock.Any()).Times(1).Return(exceptions.ErrInvalidSettings)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "internal server error",
+			body: `{"enabled":false}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UpdateQuarantineSettings(gomock.Any(), gomock.Any()).Times(1).Return(exceptions.ErrQuarantineSettings)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPut, "/schema/config-schema/quarantine/settings", bytes.NewBufferString(tt.body))
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "config-schema"})
+
+			h.UpdateQuarantineSettings()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_Quarantine(t *testing.T) {
+	tc := []struct {
+		name        string
+		query       string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name:  "status ok",
+			query: "?limit=10",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListQuarantine(gomock.Any(), "config-schema", 10).
+					Times(1).
+					Return([]*quarantine.Entry{{ID: 1, SchemaID: "config-schema"}}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "invalid limit",
+			query: "?limit=none",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ListQuarantine(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/schema/config-schema/quarantine"+tt.query, nil)
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "config-schema"})
+
+			h.Quarantine()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_Replay(t *testing.T) {
+	tc := []struct {
+		name        string
+		query       string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name: "current version",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ReplayQuarantine(gomock.Any(), "config-schema", 0).
+					Times(1).
+					Return(&quarantine.Replay{SchemaID: "config-schema", Version: 3}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "given version",
+			query: "?version=2",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ReplayQuarantine(gomock.Any(), "config-schema", 2).
+					Times(1).
+					Return(&quarantine.Replay{SchemaID: "config-schema", Version: 2}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "invalid version",
+			query: "?version=0",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ReplayQuarantine(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "version not found",
+			query: "?version=9",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ReplayQuarantine(gomock.Any(), "config-schema", 9).Times(1).Return(nil, exceptions.ErrNotFound)
+			},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/schema/config-schema/quarantine/replay"+tt.query, nil)
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "config-schema"})
+
+			h.Replay()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// parseAuditFilter reads the schemaID, since (RFC 3339) and limit parameters.
+func parseAuditFilter(r *http.Request) (audit.Filter, error) {
+	q := r.URL.Query()
+
+	filter := audit.Filter{
+		SchemaID: q.Get("schemaID"),
+	}
+
+	if since := q.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return audit.Filter{}, fmt.Errorf("%w: since: %v", exceptions.ErrInvalidQuery, err)
+		}
+
+		filter.Since = t
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		return audit.Filter{}, err
+	}
+
+	filter.Limit = limit
+
+	return filter, nil
+}
+
+func parseLimit(r *http.Request) (int, error) {
+	l := r.URL.Query().Get("limit")
+	if l == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("%w: limit: %q", exceptions.ErrInvalidQuery, l)
+	}
+
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// TagRequest points a tag at a version of a schema, by default the current one of the schema it is under.
+type TagRequest struct {
+	Target  string `json:"target,omitempty"`
+	Version int    `json:"version,omitempty"`
+}
+
+func (h *Handler) ListTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		schemaID := mux.Vars(r)["schemaID"]
+
+		tags, err := h.srv.ListTags(ctx, schemaID)
+		if err != nil {
+			h.responseError(ctx, w, "listTags", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listTags", schemaID, tags)
+	}
+}
+
+func (h *Handler) PutTag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		schemaID := vars["schemaID"]
+
+		req := &TagRequest{}
+
+		// an empty body points the tag at the current version of the schema.
+		if err := h.decode(r, req); err != nil && !errors.Is(err, io.EOF) {
+			h.responseError(ctx, w, "putTag", schemaID, invalidRequest(exceptions.ErrInvalidTag, err))
+
+			return
+		}
+
+		t := &tag.Tag{
+			SchemaID: schemaID,
+			Tag:      vars["tag"],
+			Target:   req.Target,
+			Version:  req.Version,
+		}
+
+		if err := h.srv.PutTag(ctx, t); err != nil {
+			h.responseError(ctx, w, "putTag", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "putTag", schemaID, t)
+	}
+}
+
+func (h *Handler) DeleteTag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		schemaID := vars["schemaID"]
+
+		if err := h.srv.DeleteTag(ctx, schemaID, vars["tag"]); err != nil {
+			h.responseError(ctx, w, "deleteTag", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "deleteTag", schemaID, nil)
+	}
+}
+
+func (h *Handler) TagHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		vars := mux.Vars(r)
+		schemaID := vars["schemaID"]
+
+		limit, err := parseLimit(r)
+		if err != nil {
+			h.responseError(ctx, w, "tagHistory", schemaID, err)
+
+			return
+		}
+
+		moves, err := h.srv.TagHistory(ctx, schemaID, vars["tag"], limit)
+		if err != nil {
+			h.responseError(ctx, w, "tagHistory", schemaID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "tagHistory", schemaID, moves)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestHandler_PutTag(t *testing.T) {
+	tc := []struct {
+		name        string
+		body        string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name: "status ok",
+			body: `{"version":2}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					PutTag(gomock.Any(), &tag.Tag{SchemaID: "orders", Tag: "stable", Version: 2}).
+					Times(1).
+					Return(nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "empty body",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					PutTag(gomock.Any(), &tag.Tag{SchemaID: "orders", Tag: "stable"}).
+					Times(1).
+					Return(nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "malformed body",
+			body: `{"version":`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().PutTag(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid tag",
+			body: `{"version":9}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().PutTag(gomock.Any(), gomock.Any()).Times(1).Return(exceptions.ErrInvalidTag)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "internal server error",
+			body: `{}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().PutTag(gomock.Any(), gomock.Any()).Times(1).Return(exceptions.ErrPutTag)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPut, "/schema/orders/tags/stable", bytes.NewBufferString(tt.body))
+			r = mux.SetURLVars(r, map[string]string{"schemaID": "orders", "tag": "stable"})
+
+			h.PutTag()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_TagHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		TagHistory(gomock.Any(), "orders", "stable", 5).
+		Times(1).
+		Return([]*tag.Move{{ID: 2, SchemaID: "orders", Tag: "stable", Target: "orders", Version: 3, PreviousTarget: "orders", PreviousVersion: 2}}, nil)
+
+	h := helperNewHandler(t, srv)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/schema/orders/tags/stable/history?limit=5", nil)
+	r = mux.SetURLVars(r, map[string]string{"schemaID": "orders", "tag": "stable"})
+
+	h.TagHistory()(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
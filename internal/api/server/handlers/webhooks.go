@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// WebhookRequest subscribes a URL to some types of events, all of them if none is given.
+type WebhookRequest struct {
+	URL    string         `json:"url"`
+	Secret string         `json:"secret,omitempty"`
+	Events webhook.Events `json:"events,omitempty"`
+}
+
+func (h *Handler) CreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req := &WebhookRequest{}
+
+		if err := h.decode(r, req); err != nil {
+			h.responseError(ctx, w, "createWebhook", "", invalidRequest(exceptions.ErrInvalidWebhook, err))
+
+			return
+		}
+
+		wh := &webhook.Webhook{
+			URL:    req.URL,
+			Secret: req.Secret,
+			Events: req.Events,
+		}
+
+		if err := h.srv.CreateWebhook(ctx, wh); err != nil {
+			h.responseError(ctx, w, "createWebhook", "", err)
+
+			return
+		}
+
+		// the secret is only ever returned here.
+		responseSuccess(w, http.StatusCreated, "createWebhook", strconv.FormatUint(uint64(wh.ID), 10), wh)
+	}
+}
+
+func (h *Handler) ListWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		webhooks, err := h.srv.ListWebhooks(ctx)
+		if err != nil {
+			h.responseError(ctx, w, "listWebhooks", "", err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listWebhooks", "", webhooks)
+	}
+}
+
+func (h *Handler) GetWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, err := parseWebhookID(r)
+		if err != nil {
+			h.responseError(ctx, w, "getWebhook", mux.Vars(r)["webhookID"], err)
+
+			return
+		}
+
+		wh, err := h.srv.GetWebhook(ctx, id)
+		if err != nil {
+			h.responseError(ctx, w, "getWebhook", mux.Vars(r)["webhookID"], err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "getWebhook", mux.Vars(r)["webhookID"], wh)
+	}
+}
+
+func (h *Handler) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, err := parseWebhookID(r)
+		if err != nil {
+			h.responseError(ctx, w, "deleteWebhook", mux.Vars(r)["webhookID"], err)
+
+			return
+		}
+
+		if err = h.srv.DeleteWebhook(ctx, id); err != nil {
+			h.responseError(ctx, w, "deleteWebhook", mux.Vars(r)["webhookID"], err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "deleteWebhook", mux.Vars(r)["webhookID"], nil)
+	}
+}
+
+// Deliveries lists the delivery history of a webhook, most recent first.
+func (h *Handler) Deliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, err := parseWebhookID(r)
+		if err != nil {
+			h.responseError(ctx, w, "listDeliveries", mux.Vars(r)["webhookID"], err)
+
+			return
+		}
+
+		limit, err := parseLimit(r)
+		if err != nil {
+			h.responseError(ctx, w, "listDeliveries", mux.Vars(r)["webhookID"], err)
+
+			return
+		}
+
+		deliveries, err := h.srv.ListDeliveries(ctx, webhook.DeliveryFilter{
+			WebhookID: id,
+			Status:    r.URL.Query().Get("status"),
+			Limit:     limit,
+		})
+		if err != nil {
+			h.responseError(ctx, w, "listDeliveries", mux.Vars(r)["webhookID"], err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listDeliveries", mux.Vars(r)["webhookID"], deliveries)
+	}
+}
+
+// DeadLetters lists the deliveries of all webhooks that ran out of attempts.
+func (h *Handler) DeadLetters() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		limit, err := parseLimit(r)
+		if err != nil {
+			h.responseError(ctx, w, "listDeadLetters", "", err)
+
+			return
+		}
+
+		deliveries, err := h.srv.ListDeliveries(ctx, webhook.DeliveryFilter{
+			Status: webhook.StatusDead,
+			Limit:  limit,
+		})
+		if err != nil {
+			h.responseError(ctx, w, "listDeadLetters", "", err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusOK, "listDeadLetters", "", deliveries)
+	}
+}
+
+func (h *Handler) Redeliver() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		deliveryID := mux.Vars(r)["deliveryID"]
+
+		id, err := strconv.ParseUint(deliveryID, 10, 64)
+		if err != nil {
+			h.responseError(ctx, w, "redeliver", deliveryID, fmt.Errorf("%w: deliveryID: %q", exceptions.ErrInvalidQuery, deliveryID))
+
+			return
+		}
+
+		if err = h.srv.RedeliverDelivery(ctx, id); err != nil {
+			h.responseError(ctx, w, "redeliver", deliveryID, err)
+
+			return
+		}
+
+		responseSuccess(w, http.StatusAccepted, "redeliver", deliveryID, nil)
+	}
+}
+
+func parseWebhookID(r *http.Request) (uint, error) {
+	webhookID := mux.Vars(r)["webhookID"]
+
+	id, err := strconv.ParseUint(webhookID, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: webhookID: %q", exceptions.ErrInvalidQuery, webhookID)
+	}
+
+	return uint(id), nil
+}
@@ -0,0 +1,255 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestHandler_CreateWebhook(t *testing.T) {
+	tc := []struct {
+		name        string
+		body        string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		res         *webhook.Webhook
+		problem     *exceptions.Problem
+	}{
+		{
+			name: "status created",
+			body: `{"url":"https://ci.example.com/hook","events":["schema.updated"]}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					CreateWebhook(gomock.Any(), &webhook.Webhook{
+						URL:    "https://ci.example.com/hook",
+						Events: webhook.Events{"schema.updated"},
+					}).
+					Times(1).
+					DoAndReturn(func(_ interface{}, wh *webhook.Webhook) error {
+						wh.ID, wh.Secret = 1, "s3cr3t"
+
+						return nil
+					})
+			},
+			statusCode: http.StatusCreated,
+			res: &webhook.Webhook{
+				ID:     1,
+				URL:    "https://ci.example.com/hook",
+				Secret: "s3cr3t",
+				Events: webhook.Events{"schema.updated"},
+			},
+		},
+		{
+			name: "malformed body",
+			body: `{"url":`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "body nested too deeply",
+			body: `{"url":"https://ci.example.com/hook","events":` + strings.Repeat("[", 64) + strings.Repeat("]", 64) + `}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+			problem: helperProblem("createWebhook", "",
+				fmt.Errorf("%w: %v", exceptions.ErrJSONTooComplex, "json nested too deeply: more than 64 levels")),
+		},
+		{
+			name: "invalid webhook",
+			body: `{"url":"/hook"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(1).Return(exceptions.ErrInvalidWebhook)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "internal server error",
+			body: `{"url":"https://ci.example.com/hook"}`,
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(1).Return(exceptions.ErrCreateWebhook)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.body))
+
+			h.CreateWebhook()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+
+			if tt.problem != nil {
+				helperAssertProblem(t, tt.problem, w)
+			}
+
+			if tt.res != nil {
+				res := &struct {
+					Payload *webhook.Webhook `json:"payload"`
+				}{}
+				require.NoError(t, json.NewDecoder(w.Body).Decode(res))
+
+				res.Payload.CreatedAt = tt.res.CreatedAt
+				assert.Equal(t, tt.res, res.Payload)
+			}
+		})
+	}
+}
+
+func TestHandler_Deliveries(t *testing.T) {
+	tc := []struct {
+		name        string
+		webhookID   string
+		query       string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name:      "history",
+			webhookID: "1",
+			query:     "?status=dead&limit=10",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					ListDeliveries(gomock.Any(), webhook.DeliveryFilter{WebhookID: 1, Status: webhook.StatusDead, Limit: 10}).
+					Times(1).
+					Return([]*webhook.Delivery{{ID: 7, WebhookID: 1}}, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:      "malformed webhook id",
+			webhookID: "first",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ListDeliveries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:      "unknown status",
+			webhookID: "1",
+			query:     "?status=lost",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ListDeliveries(gomock.Any(), gomock.Any()).Times(1).Return(nil, exceptions.ErrInvalidQuery)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/webhooks/"+tt.webhookID+"/deliveries"+tt.query, nil)
+			r = mux.SetURLVars(r, map[string]string{"webhookID": tt.webhookID})
+
+			h.Deliveries()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_DeadLetters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := mock_service.NewMockService(ctrl)
+	srv.EXPECT().
+		ListDeliveries(gomock.Any(), webhook.DeliveryFilter{Status: webhook.StatusDead, Limit: 100}).
+		Times(1).
+		Return([]*webhook.Delivery{{ID: 7, Status: webhook.StatusDead}}, nil)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/webhooks/dead-letters", nil)
+
+	helperNewHandler(t, srv).DeadLetters()(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHandler_Redeliver(t *testing.T) {
+	tc := []struct {
+		name        string
+		deliveryID  string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+	}{
+		{
+			name:       "accepted",
+			deliveryID: "7",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().RedeliverDelivery(gomock.Any(), uint64(7)).Times(1).Return(nil)
+			},
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "malformed delivery id",
+			deliveryID: "-1",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().RedeliverDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "internal server error",
+			deliveryID: "7",
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().RedeliverDelivery(gomock.Any(), uint64(7)).Times(1).Return(exceptions.ErrRedeliver)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			tt.serviceStub(srv)
+
+			h := helperNewHandler(t, srv)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/webhooks/deliveries/"+tt.deliveryID+"/redeliver", nil)
+			r = mux.SetURLVars(r, map[string]string{"deliveryID": tt.deliveryID})
+
+			h.Redeliver()(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+)
+
+// AccessLog writes a line per request. It runs after the RequestID middleware.
+func (m *Middleware) AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &recorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		m.log.Info(r.Context(), logger.Fields{
+			"method":   r.Method,
+			"route":    Route(r),
+			"status":   rec.Status(),
+			"bytes":    rec.bytes,
+			"latency":  time.Since(start).Seconds(),
+			"schemaID": mux.Vars(r)["schemaID"],
+			"client":   RemoteIP(r.RemoteAddr),
+		}, "access")
+	})
+}
+
+// Route returns the template of the route matching the request, e.g. /v1/schema/{schemaID}.
+func Route(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+
+	template, _ := route.GetPathTemplate()
+
+	return template
+}
+
+// recorder records the status and the size of a response.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+
+	return n, err
+}
+
+// Flush lets the events be streamed through the recorder.
+func (rec *recorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets the handlers reach the writer of the connection, e.g. to clear its deadline.
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *recorder) Status() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+
+	return rec.status
+}
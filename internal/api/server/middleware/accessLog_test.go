@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+)
+
+func TestMiddleware_AccessLog(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	m := middleware.New(logruslog.New(log), nil, nil)
+
+	router := mux.NewRouter()
+	router.Use(m.RequestID, m.AccessLog)
+	router.HandleFunc("/v1/schema/{schemaID}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}).Methods(http.MethodPost)
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/schema/config-schema", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set(middleware.RequestIDHeader, "req-1")
+
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.Len(t, hook.Entries, 1)
+
+	e := hook.LastEntry()
+	assert.Equal(t, logrus.InfoLevel, e.Level)
+	assert.Equal(t, "access", e.Message)
+	assert.Equal(t, "req-1", e.Data[string(logruslog.Settings.CtxKey)])
+	assert.Equal(t, http.MethodPost, e.Data["method"])
+	assert.Equal(t, "/v1/schema/{schemaID}", e.Data["route"])
+	assert.Equal(t, http.StatusCreated, e.Data["status"])
+	assert.Equal(t, len(`{"status":"success"}`), e.Data["bytes"])
+	assert.Equal(t, "config-schema", e.Data["schemaID"])
+	assert.Equal(t, "10.0.0.1", e.Data["client"])
+	assert.IsType(t, float64(0), e.Data["latency"])
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/service"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jwks"
+)
+
+// Route groups, each authenticated as configured, see config.Auth.
+const (
+	GroupPublic   = "public"
+	GroupSchemas  = "schemas"
+	GroupValidate = "validate"
+	GroupAdmin    = "admin"
+)
+
+// APIKeyHeader carries an API key, which can be sent as a bearer token as well.
+const APIKeyHeader = "X-API-Key"
+
+// challenge is sent along with the requests failing to authenticate.
+const challenge = `Bearer realm="json-validation-service"`
+
+var groups = []string{GroupPublic, GroupSchemas, GroupValidate, GroupAdmin}
+
+// Authenticator identifies the callers of the route groups by their API key or JWT bearer token.
+type Authenticator struct {
+	srv       service.Service
+	verifier  *jwks.Verifier
+	bootstrap string
+	methods   map[string][]string
+}
+
+// NewAuthenticator reads the methods of every route group, and the JWKS verifying the tokens if any.
+func NewAuthenticator(cfg *config.Config, srv service.Service) (*Authenticator, error) {
+	a := &Authenticator{
+		srv:       srv,
+		bootstrap: strings.ToLower(cfg.Auth.BootstrapKeyHash),
+		methods:   make(map[string][]string, len(groups)),
+	}
+
+	defaults := []string{auth.MethodAPIKey}
+
+	if cfg.Auth.JWKSFile != "" {
+		verifier, err := jwks.Load(cfg.Auth.JWKSFile, cfg.Auth.Issuer, cfg.Auth.Audience)
+		if err != nil {
+			return nil, fmt.Errorf("could not load jwks: %w", err)
+		}
+
+		a.verifier = verifier
+		defaults = append(defaults, auth.MethodJWT)
+	}
+
+	for _, group := range groups {
+		a.methods[group] = defaults
+	}
+
+	for group, methods := range cfg.Auth.Groups {
+		if _, ok := a.methods[group]; !ok {
+			return nil, fmt.Errorf("unknown route group %q", group)
+		}
+
+		parsed, err := parseMethods(methods)
+		if err != nil {
+			return nil, fmt.Errorf("route group %q: %w", group, err)
+		}
+
+		if a.verifier == nil && accepts(parsed, auth.MethodJWT) {
+			return nil, fmt.Errorf("route group %q accepts jwt without a jwks file", group)
+		}
+
+		a.methods[group] = parsed
+	}
+
+	return a, nil
+}
+
+// Authenticate identifies the caller of a request of the route group, by its API key, else its
+// Authorization header, returning a context carrying its principal.
+func (a *Authenticator) Authenticate(ctx context.Context, group, apiKey, authorization string) (context.Context, error) {
+	methods := a.methods[group]
+	if len(methods) == 0 {
+		return contexts.WithPrincipal(ctx, auth.Anonymous()), nil
+	}
+
+	token := apiKey
+	if token == "" {
+		fields := strings.Fields(authorization)
+		if len(fields) != 2 || !strings.EqualFold(fields[0], "Bearer") {
+			return nil, fmt.Errorf("%w: missing credentials", exceptions.ErrUnauthorized)
+		}
+
+		token = fields[1]
+	}
+
+	method := auth.MethodJWT
+	if apiKey != "" || strings.HasPrefix(token, auth.KeyPrefix) {
+		method = auth.MethodAPIKey
+	}
+
+	if !accepts(methods, method) {
+		return nil, fmt.Errorf("%w: %s credentials are not accepted", exceptions.ErrUnauthorized, method)
+	}
+
+	p, err := a.principal(ctx, method, token)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = contexts.WithActor(contexts.WithPrincipal(ctx, p), p.String())
+
+	if p.Namespace != "" {
+		ctx = contexts.WithNamespace(ctx, p.Namespace)
+	}
+
+	return ctx, nil
+}
+
+func (a *Authenticator) principal(ctx context.Context, method, token string) (*auth.Principal, error) {
+	if method == auth.MethodAPIKey {
+		if a.bootstrap != "" && subtle.ConstantTimeCompare([]byte(auth.HashKey(token)), []byte(a.bootstrap)) == 1 {
+			return &auth.Principal{
+				Subject: "bootstrap",
+				Method:  auth.MethodAPIKey,
+				Grants:  auth.Grants{{Role: auth.RoleAdmin, Namespace: auth.AnyNamespace}},
+			}, nil
+		}
+
+		return a.srv.AuthenticateAPIKey(ctx, token)
+	}
+
+	claims, err := a.verifier.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", exceptions.ErrUnauthorized, err)
+	}
+
+	if claims.Namespace != "" && !contexts.ValidNamespace(claims.Namespace) {
+		return nil, fmt.Errorf("%w: invalid namespace %q", exceptions.ErrUnauthorized, claims.Namespace)
+	}
+
+	return &auth.Principal{
+		Subject:   claims.Subject,
+		Method:    auth.MethodJWT,
+		Namespace: claims.Namespace,
+		Grants:    claims.Grants,
+	}, nil
+}
+
+// Authenticate rejects the requests of the route group whose caller could not be identified.
+func (m *Middleware) Authenticate(group string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, err := m.auth.Authenticate(r.Context(), group, r.Header.Get(APIKeyHeader), r.Header.Get("Authorization"))
+			if err != nil {
+				p := exceptions.NewProblem(err)
+
+				switch {
+				case p.Status == http.StatusUnauthorized:
+					w.Header().Set("WWW-Authenticate", challenge)
+				case p.Status >= http.StatusInternalServerError:
+					m.log.Error(r.Context(), err, "could not authenticate request")
+				}
+
+				p.Write(w)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// parseMethods reads the methods of a route group, e.g. "apikey|jwt", none meaning anyone is let in.
+func parseMethods(s string) ([]string, error) {
+	var methods []string
+
+	for _, method := range strings.Split(s, "|") {
+		switch method = strings.TrimSpace(method); method {
+		case auth.MethodAPIKey, auth.MethodJWT:
+			methods = append(methods, method)
+		case auth.MethodNone:
+			if strings.TrimSpace(s) != auth.MethodNone {
+				return nil, fmt.Errorf("%q cannot be combined with other methods", auth.MethodNone)
+			}
+		default:
+			return nil, fmt.Errorf("unknown method %q", method)
+		}
+	}
+
+	return methods, nil
+}
+
+func accepts(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
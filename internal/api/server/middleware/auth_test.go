@@ -0,0 +1,265 @@
+package middleware_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jwks"
+)
+
+func TestMiddleware_Authenticate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksFile := helperJWKSFile(t, key)
+
+	tc := []struct {
+		name        string
+		group       string
+		header      map[string]string
+		serviceStub func(srv *mock_service.MockService)
+		statusCode  int
+		principal   *auth.Principal
+		namespace   string
+	}{
+		{
+			name:       "open group",
+			group:      middleware.GroupPublic,
+			statusCode: http.StatusOK,
+			principal:  auth.Anonymous(),
+			namespace:  contexts.DefaultNamespace,
+		},
+		{
+			name:       "no credentials",
+			group:      middleware.GroupSchemas,
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "api key",
+			group:  middleware.GroupSchemas,
+			header: map[string]string{middleware.APIKeyHeader: "jvs_key"},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					AuthenticateAPIKey(gomock.Any(), "jvs_key").
+					Times(1).
+					Return(&auth.Principal{Subject: "ci", Method: auth.MethodAPIKey, Namespace: "team-a"}, nil)
+			},
+			statusCode: http.StatusOK,
+			principal:  &auth.Principal{Subject: "ci", Method: auth.MethodAPIKey, Namespace: "team-a"},
+			namespace:  "team-a",
+		},
+		{
+			name:   "api key as bearer token",
+			group:  middleware.GroupSchemas,
+			header: map[string]string{"Authorization": "Bearer jvs_key"},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					AuthenticateAPIKey(gomock.Any(), "jvs_key").
+					Times(1).
+					Return(&auth.Principal{Subject: "ci", Method: auth.MethodAPIKey}, nil)
+			},
+			statusCode: http.StatusOK,
+			principal:  &auth.Principal{Subject: "ci", Method: auth.MethodAPIKey},
+			namespace:  contexts.DefaultNamespace,
+		},
+		{
+			name:   "unknown api key",
+			group:  middleware.GroupSchemas,
+			header: map[string]string{middleware.APIKeyHeader: "jvs_unknown"},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().AuthenticateAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(nil, exceptions.ErrUnauthorized)
+			},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "api key lookup failure",
+			group:  middleware.GroupSchemas,
+			header: map[string]string{middleware.APIKeyHeader: "jvs_key"},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().AuthenticateAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(nil, exceptions.ErrAuthenticate)
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "bootstrap key",
+			group:      middleware.GroupAdmin,
+			header:     map[string]string{middleware.APIKeyHeader: "bootstrap-key"},
+			statusCode: http.StatusOK,
+			principal: &auth.Principal{
+				Subject: "bootstrap",
+				Method:  auth.MethodAPIKey,
+				Grants:  auth.Grants{{Role: auth.RoleAdmin, Namespace: auth.AnyNamespace}},
+			},
+			namespace: contexts.DefaultNamespace,
+		},
+		{
+			name:       "jwt",
+			group:      middleware.GroupSchemas,
+			header:     map[string]string{"Authorization": "Bearer " + helperToken(t, key, "alice", time.Hour)},
+			statusCode: http.StatusOK,
+			principal: &auth.Principal{
+				Subject:   "alice",
+				Method:    auth.MethodJWT,
+				Namespace: "team-b",
+				Grants:    auth.Grants{{Role: auth.RoleValidator, Prefix: "orders-"}},
+			},
+			namespace: "team-b",
+		},
+		{
+			name:       "expired jwt",
+			group:      middleware.GroupSchemas,
+			header:     map[string]string{"Authorization": "Bearer " + helperToken(t, key, "alice", -time.Minute)},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "jwt not accepted",
+			group:      middleware.GroupAdmin,
+			header:     map[string]string{"Authorization": "Bearer " + helperToken(t, key, "alice", time.Hour)},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "other scheme",
+			group:      middleware.GroupSchemas,
+			header:     map[string]string{"Authorization": "Basic YWxpY2U6czNjcjN0"},
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			if tt.serviceStub != nil {
+				tt.serviceStub(srv)
+			}
+
+			cfg, _ := config.Load()
+			cfg.Auth.JWKSFile = jwksFile
+			cfg.Auth.BootstrapKeyHash = auth.HashKey("bootstrap-key")
+			cfg.Auth.Groups = map[string]string{
+				middleware.GroupPublic:  "none",
+				middleware.GroupSchemas: "apikey|jwt",
+				middleware.GroupAdmin:   "apikey",
+			}
+
+			authenticator, err := middleware.NewAuthenticator(cfg, srv)
+			require.NoError(t, err)
+
+			m := middleware.New(logruslog.DefaultLogger(cfg), authenticator, nil)
+
+			var (
+				principal *auth.Principal
+				namespace string
+			)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal = contexts.Principal(r.Context())
+				namespace = contexts.Namespace(r.Context())
+			})
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/schemas", nil)
+
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			m.Authenticate(tt.group)(m.Namespace(next)).ServeHTTP(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.principal, principal)
+			assert.Equal(t, tt.namespace, namespace)
+
+			if tt.statusCode == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+				assert.Equal(t, exceptions.ProblemContentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	tc := []struct {
+		name     string
+		groups   map[string]string
+		jwksFile string
+	}{
+		{name: "unknown group", groups: map[string]string{"everything": "none"}},
+		{name: "unknown method", groups: map[string]string{middleware.GroupAdmin: "basic"}},
+		{name: "none combined", groups: map[string]string{middleware.GroupAdmin: "none|apikey"}},
+		{name: "jwt without jwks", groups: map[string]string{middleware.GroupAdmin: "jwt"}},
+		{name: "missing jwks", groups: map[string]string{}, jwksFile: filepath.Join(t.TempDir(), "jwks.json")},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := config.Load()
+			cfg.Auth.Groups = tt.groups
+			cfg.Auth.JWKSFile = tt.jwksFile
+
+			_, err := middleware.NewAuthenticator(cfg, nil)
+			require.Error(t, err)
+		})
+	}
+}
+
+func helperJWKSFile(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func helperToken(t *testing.T, key *rsa.PrivateKey, subject string, ttl time.Duration) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, &jwks.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+		Namespace: "team-b",
+		Grants:    auth.Grants{{Role: auth.RoleValidator, Prefix: "orders-"}},
+	})
+	token.Header["kid"] = "test"
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
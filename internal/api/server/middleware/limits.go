@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// LimitBody rejects the bodies larger than limit bytes, 0 meaning unbounded.
+func (m *Middleware) LimitBody(limit int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if r.ContentLength > limit {
+				exceptions.NewProblem(fmt.Errorf("%w: more than %d bytes", exceptions.ErrBodyTooLarge, limit)).Write(w)
+
+				return
+			}
+
+			r.Body = &limitedBody{ReadCloser: r.Body, limit: limit, remaining: limit}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitedBody fails once more than limit bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, b.err()
+	}
+
+	// one byte more than remaining is read, to tell a body of exactly limit bytes from a larger one.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	if b.remaining < 0 {
+		return n + int(b.remaining), b.err()
+	}
+
+	return n, err
+}
+
+func (b *limitedBody) err() error {
+	return fmt.Errorf("%w: more than %d bytes", exceptions.ErrBodyTooLarge, b.limit)
+}
@@ -0,0 +1,93 @@
+package middleware_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestMiddleware_LimitBody(t *testing.T) {
+	tc := []struct {
+		name       string
+		limit      int64
+		body       string
+		chunked    bool
+		statusCode int
+	}{
+		{
+			name:       "within the limit",
+			limit:      8,
+			body:       `{"a":1}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "exactly the limit",
+			limit:      7,
+			body:       `{"a":1}`,
+			chunked:    true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "announced past the limit",
+			limit:      4,
+			body:       `{"a":1}`,
+			statusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "chunked past the limit",
+			limit:      4,
+			body:       `{"a":1}`,
+			chunked:    true,
+			statusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "unbounded",
+			body:       strings.Repeat(" ", 1<<20),
+			chunked:    true,
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			m := middleware.New(logruslog.DefaultLogger(&config.Config{}), nil, nil)
+
+			var read []byte
+
+			h := m.LimitBody(tt.limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var err error
+				if read, err = io.ReadAll(r.Body); err != nil {
+					assert.True(t, errors.Is(err, exceptions.ErrBodyTooLarge))
+					exceptions.NewProblem(err).Write(w)
+				}
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/validate/orders", bytes.NewBufferString(tt.body))
+			if tt.chunked {
+				r.ContentLength = -1
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+
+			if tt.statusCode == http.StatusOK {
+				assert.Equal(t, tt.body, string(read))
+			} else {
+				assert.Equal(t, exceptions.ProblemContentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/metrics"
+)
+
+// unmatched labels the requests matching no route, so that unknown paths do not inflate the series.
+const unmatched = "unmatched"
+
+// Metrics counts the requests and observes their latency.
+func (m *Middleware) Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &recorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		route := Route(r)
+		if route == "" {
+			route = unmatched
+		}
+
+		status := strconv.Itoa(rec.Status())
+
+		metrics.HTTPRequests.WithLabelValues(route, r.Method, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(route, r.Method, status).Observe(time.Since(start).Seconds())
+	})
+}
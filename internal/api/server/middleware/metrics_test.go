@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/metrics"
+)
+
+func TestMiddleware_Metrics(t *testing.T) {
+	m := middleware.New(logruslog.DefaultLogger(&config.Config{}), nil, nil)
+
+	router := mux.NewRouter()
+	router.Use(m.Metrics)
+	router.NotFoundHandler = m.Metrics(http.NotFoundHandler())
+	router.HandleFunc("/metrics-test/{schemaID}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).Methods(http.MethodPost)
+
+	tc := []struct {
+		method string
+		path   string
+		route  string
+		status string
+	}{
+		{method: http.MethodPost, path: "/metrics-test/a", route: "/metrics-test/{schemaID}", status: "201"},
+		{method: http.MethodPost, path: "/metrics-test/b", route: "/metrics-test/{schemaID}", status: "201"},
+		{method: http.MethodGet, path: "/metrics-test-unknown", route: "unmatched", status: "404"},
+	}
+
+	before := make(map[string]float64, len(tc))
+
+	for _, tt := range tc {
+		before[tt.route] = testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(tt.route, tt.method, tt.status))
+	}
+
+	for _, tt := range tc {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+	}
+
+	assert.Equal(t, before["/metrics-test/{schemaID}"]+2,
+		testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("/metrics-test/{schemaID}", http.MethodPost, "201")))
+	assert.Equal(t, before["unmatched"]+1,
+		testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("unmatched", http.MethodGet, "404")))
+	assert.Equal(t, testutil.CollectAndCount(metrics.HTTPRequests), testutil.CollectAndCount(metrics.HTTPDuration))
+}
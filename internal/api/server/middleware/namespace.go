@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// NamespaceHeader scopes the requests made outside of /ns/{namespace} to a namespace.
+const NamespaceHeader = "X-Namespace"
+
+// Namespace scopes the request to the namespace of its path, else of its header, else the default one.
+func (m *Middleware) Namespace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace := mux.Vars(r)["namespace"]
+		if namespace == "" {
+			namespace = r.Header.Get(NamespaceHeader)
+		}
+
+		if namespace == "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		ctx, err := ScopeNamespace(r.Context(), namespace)
+		if err != nil {
+			exceptions.NewProblem(err).Write(w)
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ScopeNamespace scopes ctx to the namespace, which must be the one the caller is bound to, if any.
+func ScopeNamespace(ctx context.Context, namespace string) (context.Context, error) {
+	if !contexts.ValidNamespace(namespace) {
+		return nil, fmt.Errorf("%w: %q", exceptions.ErrInvalidNamespace, namespace)
+	}
+
+	if p := contexts.Principal(ctx); p != nil && p.Namespace != "" && p.Namespace != namespace {
+		return nil, fmt.Errorf("%w: bound to namespace %q", exceptions.ErrForbidden, p.Namespace)
+	}
+
+	return contexts.WithNamespace(ctx, namespace), nil
+}
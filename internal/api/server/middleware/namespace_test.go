@@ -0,0 +1,122 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+func TestMiddleware_Namespace(t *testing.T) {
+	tc := []struct {
+		name       string
+		vars       map[string]string
+		header     string
+		ctx        context.Context
+		statusCode int
+		namespace  string
+	}{
+		{
+			name:       "default",
+			ctx:        context.Background(),
+			statusCode: http.StatusOK,
+			namespace:  contexts.DefaultNamespace,
+		},
+		{
+			name:       "path",
+			vars:       map[string]string{"namespace": "team-a"},
+			header:     "team-b",
+			ctx:        context.Background(),
+			statusCode: http.StatusOK,
+			namespace:  "team-a",
+		},
+		{
+			name:       "header",
+			header:     "team-b",
+			ctx:        context.Background(),
+			statusCode: http.StatusOK,
+			namespace:  "team-b",
+		},
+		{
+			name:       "context",
+			ctx:        contexts.WithNamespace(context.Background(), "team-c"),
+			statusCode: http.StatusOK,
+			namespace:  "team-c",
+		},
+		{
+			name:       "principal namespace",
+			vars:       map[string]string{"namespace": "team-a"},
+			ctx:        helperBoundContext("team-a"),
+			statusCode: http.StatusOK,
+			namespace:  "team-a",
+		},
+		{
+			name:       "other namespace than the principal's",
+			vars:       map[string]string{"namespace": "team-b"},
+			ctx:        helperBoundContext("team-a"),
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "other header than the principal's",
+			header:     "team-b",
+			ctx:        helperBoundContext("team-a"),
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "principal of any namespace",
+			header:     "team-b",
+			ctx:        contexts.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin"}),
+			statusCode: http.StatusOK,
+			namespace:  "team-b",
+		},
+		{
+			name:       "invalid",
+			header:     "Team_A",
+			ctx:        context.Background(),
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := config.Load()
+			m := middleware.New(logruslog.DefaultLogger(cfg), nil, nil)
+
+			var namespace string
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				namespace = contexts.Namespace(r.Context())
+			})
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequestWithContext(tt.ctx, http.MethodGet, "/schemas", nil)
+			r = mux.SetURLVars(r, tt.vars)
+
+			if tt.header != "" {
+				r.Header.Set(middleware.NamespaceHeader, tt.header)
+			}
+
+			m.Namespace(next).ServeHTTP(w, r)
+
+			require.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.namespace, namespace)
+		})
+	}
+}
+
+// helperBoundContext returns the context of a request authenticated by a caller bound to the namespace.
+func helperBoundContext(namespace string) context.Context {
+	ctx := contexts.WithPrincipal(context.Background(), &auth.Principal{Subject: "ci", Namespace: namespace})
+
+	return contexts.WithNamespace(ctx, namespace)
+}
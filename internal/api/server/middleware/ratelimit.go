@@ -0,0 +1,325 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/service"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// Keys telling the clients apart, see config.RateLimit.
+const (
+	KeyPrincipal = "principal"
+	KeyIP        = "ip"
+	KeyNamespace = "namespace"
+)
+
+// Limit refills a token bucket with Rate tokens per second, up to Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Quota is the state of the bucket of a client once a request took a token from it, or failed to.
+type Quota struct {
+	Limit
+	Tokens  float64
+	Allowed bool
+}
+
+// Headers returns the RateLimit headers of the quota, along with Retry-After once denied.
+func (q *Quota) Headers() map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(q.Burst),
+		"RateLimit-Remaining": strconv.Itoa(int(math.Max(0, math.Floor(q.Tokens)))),
+		"RateLimit-Reset":     strconv.Itoa(seconds((float64(q.Burst) - q.Tokens) / q.Rate)),
+	}
+
+	if !q.Allowed {
+		headers["Retry-After"] = strconv.Itoa(q.RetryAfter())
+	}
+
+	return headers
+}
+
+func (q *Quota) RetryAfter() int {
+	if s := seconds((1 - q.Tokens) / q.Rate); s > 1 {
+		return s
+	}
+
+	return 1
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RateLimiter bounds the requests of the clients of every route group with token buckets.
+type RateLimiter struct {
+	srv       service.Service
+	key       string
+	shared    bool
+	limits    map[string]*Limit
+	ipLimits  map[string]*Limit
+	retention time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// NewRateLimiter reads the limits of every route group, returning nil when the rate limits are disabled.
+func NewRateLimiter(cfg *config.Config, srv service.Service) (*RateLimiter, error) {
+	if !cfg.RateLimit.Enabled {
+		return nil, nil
+	}
+
+	switch cfg.RateLimit.Key {
+	case KeyPrincipal, KeyIP, KeyNamespace:
+	default:
+		return nil, fmt.Errorf("unknown rate limit key %q", cfg.RateLimit.Key)
+	}
+
+	if cfg.RateLimit.Retention <= 0 {
+		return nil, fmt.Errorf("invalid rate limit retention %v", cfg.RateLimit.Retention)
+	}
+
+	l := &RateLimiter{
+		srv:       srv,
+		key:       cfg.RateLimit.Key,
+		shared:    cfg.RateLimit.Shared,
+		limits:    make(map[string]*Limit, len(groups)),
+		ipLimits:  make(map[string]*Limit, len(groups)),
+		retention: cfg.RateLimit.Retention,
+		buckets:   make(map[string]*bucket),
+	}
+
+	defaults, err := newLimit(cfg.RateLimit.Rate, cfg.RateLimit.Burst)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		l.limits[group] = defaults
+	}
+
+	for group, limit := range cfg.RateLimit.Routes {
+		if _, ok := l.limits[group]; !ok {
+			return nil, fmt.Errorf("unknown route group %q", group)
+		}
+
+		parsed, parseErr := parseLimit(limit, cfg.RateLimit.Burst)
+		if parseErr != nil {
+			return nil, fmt.Errorf("route group %q: %w", group, parseErr)
+		}
+
+		l.limits[group] = parsed
+	}
+
+	ip, err := parseLimit(cfg.RateLimit.IP, cfg.RateLimit.Burst)
+	if cfg.RateLimit.IP != "" && err != nil {
+		return nil, fmt.Errorf("ip: %w", err)
+	}
+
+	for group, limit := range l.limits {
+		if limit != nil && cfg.RateLimit.IP != "" {
+			limit = ip
+		}
+
+		l.ipLimits[group] = limit
+	}
+
+	return l, nil
+}
+
+// Take takes a token from the bucket of the client of the route group, nil when the group is not bounded.
+func (l *RateLimiter) Take(ctx context.Context, group, ip string) (*Quota, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	return l.takeFrom(ctx, group+"|"+l.client(ctx, ip), l.limits[group])
+}
+
+// TakeIP takes a token from the bucket of the ip, so that the requests failing to authenticate are bounded too.
+func (l *RateLimiter) TakeIP(ctx context.Context, group, ip string) (*Quota, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	return l.takeFrom(ctx, group+"|unauthenticated|"+KeyIP+":"+ip, l.ipLimits[group])
+}
+
+func (l *RateLimiter) takeFrom(ctx context.Context, key string, limit *Limit) (*Quota, error) {
+	if limit == nil {
+		return nil, nil
+	}
+
+	if !l.shared {
+		return l.take(key, limit, time.Now()), nil
+	}
+
+	b, err := l.srv.TakeToken(ctx, key, limit.Rate, limit.Burst)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Quota{Limit: *limit, Tokens: b.Tokens, Allowed: b.Allowed}, nil
+}
+
+// client identifies the client by the configured key. Only the namespace a principal is bound to counts, not
+// the one asked for, which anyone could change to get a fresh bucket.
+func (l *RateLimiter) client(ctx context.Context, ip string) string {
+	p := contexts.Principal(ctx)
+	if l.key == KeyIP || p == nil || p.Method == auth.MethodNone {
+		return KeyIP + ":" + ip
+	}
+
+	if l.key == KeyNamespace && p.Namespace != "" {
+		return KeyNamespace + ":" + p.Namespace
+	}
+
+	return KeyPrincipal + ":" + p.String()
+}
+
+// take takes a token from the bucket kept in memory, a new one starting full.
+func (l *RateLimiter) take(key string, limit *Limit, now time.Time) *Quota {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst)}
+		l.buckets[key] = b
+	} else {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	}
+
+	b.updated = now
+
+	q := &Quota{Limit: *limit}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		q.Allowed = true
+	}
+
+	q.Tokens = b.tokens
+
+	return q
+}
+
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < l.retention {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) > l.retention {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.swept = now
+}
+
+// RateLimit rejects the requests of the clients out of tokens. It runs after the Authenticate middleware.
+func (m *Middleware) RateLimit(group string) mux.MiddlewareFunc {
+	return m.rateLimit(group, m.limiter.Take)
+}
+
+// RateLimitIP rejects the requests of the IPs out of tokens, before the Authenticate middleware so that
+// credentials cannot be guessed at will.
+func (m *Middleware) RateLimitIP(group string) mux.MiddlewareFunc {
+	return m.rateLimit(group, m.limiter.TakeIP)
+}
+
+func (m *Middleware) rateLimit(group string, take func(ctx context.Context, group, ip string) (*Quota, error)) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q, err := take(r.Context(), group, RemoteIP(r.RemoteAddr))
+			if err != nil {
+				// the requests are let through rather than failing along with the shared buckets.
+				m.log.Error(r.Context(), err, "could not rate limit request")
+			}
+
+			if q == nil {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			for name, value := range q.Headers() {
+				w.Header().Set(name, value)
+			}
+
+			if !q.Allowed {
+				exceptions.NewProblem(fmt.Errorf("%w: retry in %ds", exceptions.ErrRateLimited, q.RetryAfter())).Write(w)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RemoteIP returns the IP of the remote address, without its port.
+func RemoteIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+// parseLimit reads the limit of a route group, e.g. "100/200" or "100" with the default burst.
+func parseLimit(s string, burst int) (*Limit, error) {
+	parts := strings.SplitN(s, "/", 2)
+
+	rate, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed rate limit %q", s)
+	}
+
+	if len(parts) == 2 {
+		if burst, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+			return nil, fmt.Errorf("malformed rate limit %q", s)
+		}
+	}
+
+	return newLimit(rate, burst)
+}
+
+func newLimit(rate float64, burst int) (*Limit, error) {
+	switch {
+	case rate == 0:
+		return nil, nil
+	case rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate):
+		return nil, fmt.Errorf("invalid rate %v", rate)
+	case burst < 1:
+		return nil, fmt.Errorf("invalid burst %d", burst)
+	}
+
+	return &Limit{Rate: rate, Burst: burst}, nil
+}
+
+func seconds(s float64) int {
+	return int(math.Ceil(s))
+}
@@ -0,0 +1,323 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/models/ratelimit"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// client is the caller of a request, told apart by its address, principal or the namespace its principal is
+// bound to. The request is scoped to the namespace as well.
+type client struct {
+	addr      string
+	principal string
+	namespace string
+}
+
+func TestMiddleware_RateLimit(t *testing.T) {
+	// the buckets barely refill during the tests.
+	limits := config.RateLimit{Enabled: true, Key: middleware.KeyIP, Rate: 0.001, Burst: 2, Retention: time.Hour}
+
+	tc := []struct {
+		name        string
+		key         string
+		routes      map[string]string
+		shared      bool
+		clients     []client
+		serviceStub func(srv *mock_service.MockService)
+		statusCodes []int
+		headers     map[string]string
+	}{
+		{
+			name:        "burst exhausted",
+			key:         middleware.KeyIP,
+			clients:     []client{{addr: "10.0.0.1:1234"}, {addr: "10.0.0.1:1235"}, {addr: "10.0.0.1:1236"}},
+			statusCodes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			headers: map[string]string{
+				"RateLimit-Limit":     "2",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "2000",
+				"Retry-After":         "1000",
+			},
+		},
+		{
+			name:        "clients told apart by ip",
+			key:         middleware.KeyIP,
+			clients:     []client{{addr: "10.0.0.1:1234"}, {addr: "10.0.0.2:1234"}, {addr: "10.0.0.3:1234"}},
+			statusCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+			headers:     map[string]string{"RateLimit-Remaining": "1", "RateLimit-Reset": "1000"},
+		},
+		{
+			name: "clients told apart by principal",
+			key:  middleware.KeyPrincipal,
+			clients: []client{
+				{addr: "10.0.0.1:1234", principal: "ci"},
+				{addr: "10.0.0.1:1234", principal: "ci"},
+				{addr: "10.0.0.1:1234", principal: "cd"},
+			},
+			statusCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name: "anonymous clients told apart by ip",
+			key:  middleware.KeyPrincipal,
+			clients: []client{
+				{addr: "10.0.0.1:1234"},
+				{addr: "10.0.0.1:1234"},
+				{addr: "10.0.0.1:1234", principal: "ci"},
+				{addr: "10.0.0.1:1234"},
+			},
+			statusCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "clients told apart by namespace",
+			key:  middleware.KeyNamespace,
+			clients: []client{
+				{addr: "10.0.0.1:1234", principal: "ci", namespace: "team-a"},
+				{addr: "10.0.0.2:1234", principal: "cd", namespace: "team-a"},
+				{addr: "10.0.0.3:1234", principal: "ci", namespace: "team-b"},
+				{addr: "10.0.0.4:1234", principal: "qa", namespace: "team-a"},
+			},
+			statusCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "anonymous clients not told apart by the namespace they ask for",
+			key:  middleware.KeyNamespace,
+			clients: []client{
+				{addr: "10.0.0.1:1234", namespace: "team-a"},
+				{addr: "10.0.0.1:1234", namespace: "team-b"},
+				{addr: "10.0.0.1:1234", namespace: "team-c"},
+			},
+			statusCodes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:        "route group limit",
+			key:         middleware.KeyIP,
+			routes:      map[string]string{middleware.GroupSchemas: "0.001/1"},
+			clients:     []client{{addr: "10.0.0.1:1234"}, {addr: "10.0.0.1:1234"}},
+			statusCodes: []int{http.StatusOK, http.StatusTooManyRequests},
+			headers:     map[string]string{"RateLimit-Limit": "1"},
+		},
+		{
+			name:        "unbounded route group",
+			key:         middleware.KeyIP,
+			routes:      map[string]string{middleware.GroupSchemas: "0"},
+			clients:     []client{{addr: "10.0.0.1:1234"}, {addr: "10.0.0.1:1234"}, {addr: "10.0.0.1:1234"}},
+			statusCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+			headers:     map[string]string{"RateLimit-Limit": ""},
+		},
+		{
+			name:    "shared bucket",
+			key:     middleware.KeyIP,
+			shared:  true,
+			clients: []client{{addr: "10.0.0.1:1234"}},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().
+					TakeToken(gomock.Any(), "schemas|ip:10.0.0.1", 0.001, 2).
+					Times(1).
+					Return(&ratelimit.Bucket{Tokens: 0.5, Allowed: false}, nil)
+			},
+			statusCodes: []int{http.StatusTooManyRequests},
+			headers:     map[string]string{"RateLimit-Remaining": "0", "Retry-After": "500"},
+		},
+		{
+			name:    "shared bucket failure",
+			key:     middleware.KeyIP,
+			shared:  true,
+			clients: []client{{addr: "10.0.0.1:1234"}},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().TakeToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, exceptions.ErrTakeToken)
+			},
+			statusCodes: []int{http.StatusOK},
+			headers:     map[string]string{"RateLimit-Limit": ""},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := mock_service.NewMockService(ctrl)
+			if tt.serviceStub != nil {
+				tt.serviceStub(srv)
+			}
+
+			cfg, _ := config.Load()
+			cfg.RateLimit = limits
+			cfg.RateLimit.Key = tt.key
+			cfg.RateLimit.Routes = tt.routes
+			cfg.RateLimit.Shared = tt.shared
+
+			limiter, err := middleware.NewRateLimiter(cfg, srv)
+			require.NoError(t, err)
+
+			m := middleware.New(logruslog.DefaultLogger(cfg), nil, limiter)
+			h := m.RateLimit(middleware.GroupSchemas)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+			var w *httptest.ResponseRecorder
+
+			for i, c := range tt.clients {
+				w = httptest.NewRecorder()
+				h.ServeHTTP(w, helperClientRequest(c))
+
+				assert.Equal(t, tt.statusCodes[i], w.Code, "request %d", i)
+			}
+
+			for name, value := range tt.headers {
+				assert.Equal(t, value, w.Header().Get(name), name)
+			}
+
+			if w.Code == http.StatusTooManyRequests {
+				assert.Equal(t, exceptions.ProblemContentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestMiddleware_RateLimitIP(t *testing.T) {
+	tc := []struct {
+		name        string
+		ip          string
+		statusCodes []int
+	}{
+		{
+			name:        "bound of the route group",
+			statusCodes: []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests},
+		},
+		{
+			name:        "bound of the ips",
+			ip:          "0.001/1",
+			statusCodes: []int{http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusTooManyRequests},
+		},
+		{
+			name:        "unbounded ips",
+			ip:          "0",
+			statusCodes: []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := config.Load()
+			cfg.RateLimit = config.RateLimit{Enabled: true, Key: middleware.KeyPrincipal, Rate: 0.001, Burst: 2, IP: tt.ip, Retention: time.Hour}
+
+			limiter, err := middleware.NewRateLimiter(cfg, nil)
+			require.NoError(t, err)
+
+			m := middleware.New(logruslog.DefaultLogger(cfg), nil, limiter)
+
+			// the requests fail to authenticate, e.g. guessing API keys.
+			h := m.RateLimitIP(middleware.GroupSchemas)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+
+			for i, want := range tt.statusCodes {
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, helperClientRequest(client{addr: "10.0.0.1:1234"}))
+
+				assert.Equal(t, want, w.Code, "request %d", i)
+			}
+		})
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	tc := []struct {
+		name      string
+		rateLimit config.RateLimit
+		disabled  bool
+		err       bool
+	}{
+		{
+			name:      "disabled",
+			rateLimit: config.RateLimit{Key: "unknown"},
+			disabled:  true,
+		},
+		{
+			name: "enabled",
+			rateLimit: config.RateLimit{Enabled: true, Key: middleware.KeyPrincipal, Rate: 10, Burst: 20, Retention: time.Hour, Routes: map[string]string{
+				"validate": "100/200",
+			}},
+		},
+		{
+			name:      "unknown key",
+			rateLimit: config.RateLimit{Enabled: true, Key: "apikey", Rate: 10, Burst: 20, Retention: time.Hour},
+			err:       true,
+		},
+		{
+			name:      "unknown route group",
+			rateLimit: config.RateLimit{Enabled: true, Key: middleware.KeyIP, Rate: 10, Burst: 20, Retention: time.Hour, Routes: map[string]string{"webhooks": "1"}},
+			err:       true,
+		},
+		{
+			name:      "malformed limit",
+			rateLimit: config.RateLimit{Enabled: true, Key: middleware.KeyIP, Rate: 10, Burst: 20, Retention: time.Hour, Routes: map[string]string{"validate": "100/x"}},
+			err:       true,
+		},
+		{
+			name:      "malformed ip limit",
+			rateLimit: config.RateLimit{Enabled: true, Key: middleware.KeyIP, Rate: 10, Burst: 20, Retention: time.Hour, IP: "x"},
+			err:       true,
+		},
+		{
+			name:      "negative rate",
+			rateLimit: config.RateLimit{Enabled: true, Key: middleware.KeyIP, Rate: -1, Burst: 20, Retention: time.Hour},
+			err:       true,
+		},
+		{
+			name:      "no burst",
+			rateLimit: config.RateLimit{Enabled: true, Key: middleware.KeyIP, Rate: 10, Retention: time.Hour},
+			err:       true,
+		},
+		{
+			name:      "no retention",
+			rateLimit: config.RateLimit{Enabled: true, Key: middleware.KeyIP, Rate: 10, Burst: 20},
+			err:       true,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := middleware.NewRateLimiter(&config.Config{RateLimit: tt.rateLimit}, nil)
+			if tt.err {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.disabled, limiter == nil)
+		})
+	}
+}
+
+func helperClientRequest(c client) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/schemas", nil)
+	r.RemoteAddr = c.addr
+
+	ctx := r.Context()
+
+	if c.principal != "" {
+		ctx = contexts.WithPrincipal(ctx, &auth.Principal{Subject: c.principal, Method: auth.MethodAPIKey, Namespace: c.namespace})
+	}
+
+	if c.namespace != "" {
+		ctx = contexts.WithNamespace(ctx, c.namespace)
+	}
+
+	return r.WithContext(ctx)
+}
@@ -9,12 +9,16 @@ import (
 )
 
 type Middleware struct {
-	log logger.Logger
+	log     logger.Logger
+	auth    *Authenticator
+	limiter *RateLimiter
 }
 
-func New(log logger.Logger) *Middleware {
+func New(log logger.Logger, authenticator *Authenticator, limiter *RateLimiter) *Middleware {
 	return &Middleware{
-		log: log,
+		log:     log,
+		auth:    authenticator,
+		limiter: limiter,
 	}
 }
 
@@ -26,7 +30,7 @@ func (m *Middleware) RecoverPanic(next http.Handler) http.Handler {
 		defer func() {
 			if err := recover(); err != nil {
 				m.log.Error(ctx, fmt.Errorf("%w: %v", exceptions.ErrRecover, err), "middleware recovering from panic error")
-				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				exceptions.NewProblem(exceptions.ErrInternalServerError).Write(w)
 			}
 		}()
 
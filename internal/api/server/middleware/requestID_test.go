@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+func TestMiddleware_RequestID(t *testing.T) {
+	tc := []struct {
+		name      string
+		requestID string
+		accepted  bool
+	}{
+		{
+			name:      "given",
+			requestID: "3f2a9c1e-7d4b-4c59-9a1e-0b6f2d8c4e71",
+			accepted:  true,
+		},
+		{
+			name: "generated",
+		},
+		{
+			name:      "malformed",
+			requestID: "<script>",
+		},
+		{
+			name:      "too long",
+			requestID: strings.Repeat("a", 129),
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			m := middleware.New(logruslog.DefaultLogger(&config.Config{}), nil, nil)
+
+			var requestID, logged interface{}
+
+			h := m.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestID = contexts.RequestID(r.Context())
+				logged = r.Context().Value(logruslog.Settings.CtxKey)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/schemas", nil)
+			if tt.requestID != "" {
+				r.Header.Set(middleware.RequestIDHeader, tt.requestID)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			echoed := w.Header().Get(middleware.RequestIDHeader)
+
+			assert.NotEmpty(t, echoed)
+			assert.Equal(t, echoed, requestID)
+			assert.Equal(t, echoed, logged)
+
+			if tt.accepted {
+				assert.Equal(t, tt.requestID, echoed)
+			} else {
+				assert.Len(t, echoed, 32)
+			}
+		})
+	}
+}
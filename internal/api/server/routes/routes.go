@@ -8,24 +8,103 @@ import (
 
 	"github.com/KarolosLykos/json-validation-service/internal/api/server/handlers"
 	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
 	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/metrics"
 	"github.com/KarolosLykos/json-validation-service/internal/service"
 )
 
-func SetupRoutes(ctx context.Context, log logger.Logger, srv service.Service) http.Handler {
+func SetupRoutes(
+	ctx context.Context,
+	cfg *config.Config,
+	log logger.Logger,
+	srv service.Service,
+	authenticator *middleware.Authenticator,
+	limiter *middleware.RateLimiter,
+) http.Handler {
 	log.Debug(ctx, "setting up routes")
 
 	router := mux.NewRouter().StrictSlash(true)
 
-	m := middleware.New(log)
+	m := middleware.New(log, authenticator, limiter)
 
-	router.Use(m.RecoverPanic)
+	router.Use(m.RequestID, m.AccessLog, m.Metrics, m.RecoverPanic, m.LimitBody(cfg.Limits.MaxBodyBytes))
 
-	h := handlers.New(log, srv)
+	// the middlewares only run for the matching routes, the others are identified, logged and measured alike.
+	router.NotFoundHandler = m.RequestID(m.AccessLog(m.Metrics(http.NotFoundHandler())))
+	router.MethodNotAllowedHandler = m.RequestID(m.AccessLog(m.Metrics(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))))
 
-	router.HandleFunc("/schema/{schemaID}", h.Upload()).Methods(http.MethodPost)
-	router.HandleFunc("/schema/{schemaID}", h.Download()).Methods(http.MethodGet)
-	router.HandleFunc("/validate/{schemaID}", h.Validate()).Methods(http.MethodPost)
+	h := handlers.New(cfg, log, srv)
+
+	api(router.PathPrefix("/v1").Subrouter(), h, m)
+
+	// the unversioned routes predate /v1, they are kept for the existing clients.
+	api(router, h, m)
+
+	// the metrics are scraped outside of the versioned API, authenticated like the admin routes.
+	if cfg.Metrics.Enabled {
+		group(router, m, middleware.GroupAdmin).Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+	}
 
 	return router
 }
+
+// api registers the routes of a version of the API.
+func api(router *mux.Router, h *handlers.Handler, m *middleware.Middleware) {
+	// the schemas live in the namespace of the path, or of the X-Namespace header, the default one otherwise.
+	for _, r := range []*mux.Router{router, router.PathPrefix("/ns/{namespace}").Subrouter()} {
+		namespaced(group(r, m, middleware.GroupSchemas), h)
+		group(r, m, middleware.GroupSchemas).HandleFunc("/schema/by-digest/{sha256}", h.DownloadByDigest()).Methods(http.MethodGet)
+		group(r, m, middleware.GroupValidate).HandleFunc("/validate/{schemaID}", h.Validate()).Methods(http.MethodPost)
+	}
+
+	group(router, m, middleware.GroupPublic).HandleFunc("/openapi.json", h.OpenAPI()).Methods(http.MethodGet)
+
+	admin := group(router, m, middleware.GroupAdmin)
+	admin.HandleFunc("/apikeys", h.CreateAPIKey()).Methods(http.MethodPost)
+	admin.HandleFunc("/apikeys", h.ListAPIKeys()).Methods(http.MethodGet)
+	admin.HandleFunc("/apikeys/{keyID}", h.DeleteAPIKey()).Methods(http.MethodDelete)
+	admin.HandleFunc("/webhooks", h.CreateWebhook()).Methods(http.MethodPost)
+	admin.HandleFunc("/webhooks", h.ListWebhooks()).Methods(http.MethodGet)
+	admin.HandleFunc("/webhooks/dead-letters", h.DeadLetters()).Methods(http.MethodGet)
+	admin.HandleFunc("/webhooks/deliveries/{deliveryID}/redeliver", h.Redeliver()).Methods(http.MethodPost)
+	admin.HandleFunc("/webhooks/{webhookID}", h.GetWebhook()).Methods(http.MethodGet)
+	admin.HandleFunc("/webhooks/{webhookID}", h.DeleteWebhook()).Methods(http.MethodDelete)
+	admin.HandleFunc("/webhooks/{webhookID}/deliveries", h.Deliveries()).Methods(http.MethodGet)
+}
+
+// group returns a router for the routes of the group, authenticated and rate limited as configured.
+func group(router *mux.Router, m *middleware.Middleware, name string) *mux.Router {
+	r := router.NewRoute().Subrouter()
+	r.Use(m.RateLimitIP(name), m.Authenticate(name), m.Namespace, m.RateLimit(name))
+
+	return r
+}
+
+// namespaced registers the routes scoped to a namespace.
+func namespaced(router *mux.Router, h *handlers.Handler) {
+	router.HandleFunc("/schema/{schemaID}", h.Upload()).Methods(http.MethodPost)
+	router.HandleFunc("/schema/{schemaID}", h.Download()).Methods(http.MethodGet)
+	router.HandleFunc("/schema/{schemaID}", h.Update()).Methods(http.MethodPut)
+	router.HandleFunc("/schema/{schemaID}", h.Delete()).Methods(http.MethodDelete)
+	router.HandleFunc("/schema/{schemaID}/meta", h.Meta()).Methods(http.MethodGet)
+	router.HandleFunc("/schema/{schemaID}/lifecycle", h.UpdateLifecycle()).Methods(http.MethodPut)
+	router.HandleFunc("/schema/{schemaID}/restore", h.Restore()).Methods(http.MethodPost)
+	router.HandleFunc("/schema/{schemaID}/stats", h.Stats()).Methods(http.MethodGet)
+	router.HandleFunc("/schema/{schemaID}/quarantine", h.Quarantine()).Methods(http.MethodGet)
+	router.HandleFunc("/schema/{schemaID}/quarantine/settings", h.QuarantineSettings()).Methods(http.MethodGet)
+	router.HandleFunc("/schema/{schemaID}/quarantine/settings", h.UpdateQuarantineSettings()).Methods(http.MethodPut)
+	router.HandleFunc("/schema/{schemaID}/quarantine/replay", h.Replay()).Methods(http.MethodPost)
+	router.HandleFunc("/schema/{schemaID}/tags", h.ListTags()).Methods(http.MethodGet)
+	router.HandleFunc("/schema/{schemaID}/tags/{tag}", h.PutTag()).Methods(http.MethodPut)
+	router.HandleFunc("/schema/{schemaID}/tags/{tag}", h.DeleteTag()).Methods(http.MethodDelete)
+	router.HandleFunc("/schema/{schemaID}/tags/{tag}/history", h.TagHistory()).Methods(http.MethodGet)
+	router.HandleFunc("/schemas", h.List()).Methods(http.MethodGet)
+	router.HandleFunc("/schemas/import", h.Import()).Methods(http.MethodPost)
+	router.HandleFunc("/schemas/export", h.Export()).Methods(http.MethodGet)
+	router.HandleFunc("/trash", h.Trash()).Methods(http.MethodGet)
+	router.HandleFunc("/audit", h.Audit()).Methods(http.MethodGet)
+	router.HandleFunc("/events", h.Events()).Methods(http.MethodGet)
+}
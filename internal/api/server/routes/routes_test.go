@@ -0,0 +1,193 @@
+package routes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/routes"
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+)
+
+const version = "/v1"
+
+// TestSetupRoutes_OpenAPI checks that every route under /v1 is documented, and that every documented route is registered.
+func TestSetupRoutes_OpenAPI(t *testing.T) {
+	router := helperRouter(t)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, version+"/openapi.json", nil)
+
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	spec := &struct {
+		OpenAPI string `json:"openapi"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(spec))
+
+	require.Equal(t, "3.1.0", spec.OpenAPI)
+	require.Len(t, spec.Servers, 1)
+	require.Equal(t, version, spec.Servers[0].URL)
+
+	var documented []string
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	var registered []string
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(path, version+"/") {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			// the subrouters have no methods.
+			return nil
+		}
+
+		for _, method := range methods {
+			registered = append(registered, method+" "+strings.TrimPrefix(path, version))
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, registered)
+
+	sort.Strings(documented)
+	sort.Strings(registered)
+
+	assert.Equal(t, registered, documented)
+}
+
+func TestSetupRoutes_Unversioned(t *testing.T) {
+	router := helperRouter(t)
+
+	for _, path := range []string{"/openapi.json", version + "/openapi.json"} {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+}
+
+// TestSetupRoutes_Groups checks that every route group is authenticated as configured.
+func TestSetupRoutes_Groups(t *testing.T) {
+	router := helperRouter(t, map[string]string{
+		middleware.GroupPublic:   "none",
+		middleware.GroupSchemas:  "apikey",
+		middleware.GroupValidate: "apikey",
+		middleware.GroupAdmin:    "apikey",
+	})
+
+	tc := []struct {
+		method     string
+		path       string
+		statusCode int
+	}{
+		{method: http.MethodGet, path: "/v1/openapi.json", statusCode: http.StatusOK},
+		{method: http.MethodGet, path: "/v1/schema/config-schema", statusCode: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/v1/ns/team-a/schema/config-schema", statusCode: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/v1/schema/by-digest/abc", statusCode: http.StatusUnauthorized},
+		{method: http.MethodPost, path: "/v1/validate/config-schema", statusCode: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/v1/apikeys", statusCode: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/v1/webhooks", statusCode: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/schemas", statusCode: http.StatusUnauthorized},
+		{method: http.MethodPatch, path: "/v1/schema/config-schema", statusCode: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/v1/unknown", statusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tc {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(tt.method, tt.path, nil)
+
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, tt.statusCode, w.Code, tt.method+" "+tt.path)
+	}
+}
+
+// TestSetupRoutes_RequestID checks that every request is identified, matching a route or not.
+func TestSetupRoutes_RequestID(t *testing.T) {
+	router := helperRouter(t)
+
+	for _, r := range []struct{ method, path string }{
+		{method: http.MethodGet, path: "/v1/openapi.json"},
+		{method: http.MethodPatch, path: "/v1/schema/config-schema"},
+		{method: http.MethodGet, path: "/v1/unknown"},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(r.method, r.path, nil)
+		req.Header.Set(middleware.RequestIDHeader, "req-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "req-1", w.Header().Get(middleware.RequestIDHeader), r.method+" "+r.path)
+	}
+}
+
+func TestSetupRoutes_Metrics(t *testing.T) {
+	router := helperRouter(t)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, version+"/openapi.json", nil))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, w.Body.String(), `json_validation_http_requests_total{method="GET",route="/v1/openapi.json",status="200"}`)
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
+
+func helperRouter(t *testing.T, groups ...map[string]string) *mux.Router {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg, _ := config.Load()
+	log := logruslog.DefaultLogger(cfg)
+
+	for _, g := range groups {
+		cfg.Auth.Groups = g
+	}
+
+	srv := mock_service.NewMockService(ctrl)
+
+	authenticator, err := middleware.NewAuthenticator(cfg, srv)
+	require.NoError(t, err)
+
+	router, ok := routes.SetupRoutes(context.Background(), cfg, log, srv, authenticator, nil).(*mux.Router)
+	require.True(t, ok)
+
+	return router
+}
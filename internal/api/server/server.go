@@ -5,11 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/gorilla/handlers"
 
 	"github.com/KarolosLykos/json-validation-service/internal/api"
+	"github.com/KarolosLykos/json-validation-service/internal/api/server/middleware"
 	"github.com/KarolosLykos/json-validation-service/internal/api/server/routes"
 	"github.com/KarolosLykos/json-validation-service/internal/config"
 	"github.com/KarolosLykos/json-validation-service/internal/logger"
@@ -23,15 +23,27 @@ type server struct {
 	handler http.Handler
 }
 
-func New(ctx context.Context, cfg *config.Config, log logger.Logger, srv service.Service) api.API {
+func New(
+	ctx context.Context, cfg *config.Config, log logger.Logger, srv service.Service,
+	authenticator *middleware.Authenticator, limiter *middleware.RateLimiter,
+) api.API {
 	log.Debug(ctx, "create new server")
 
 	corsOptions := []handlers.CORSOption{
-		handlers.AllowedMethods([]string{http.MethodPost, http.MethodGet}),
-		handlers.AllowedHeaders([]string{"content-type"}),
+		handlers.AllowedMethods([]string{http.MethodPost, http.MethodGet, http.MethodPut, http.MethodDelete}),
+		handlers.AllowedHeaders([]string{
+			"content-type", "if-match", "if-none-match",
+			"x-schema-description", "x-schema-owner", "x-schema-labels", "x-schema-state", "last-event-id", "x-namespace",
+			"authorization", "x-api-key", "x-request-id",
+		}),
+		handlers.ExposedHeaders([]string{
+			"etag", "www-authenticate", "x-request-id",
+			"ratelimit-limit", "ratelimit-remaining", "ratelimit-reset", "retry-after",
+			"deprecation", "sunset", "link",
+		}),
 	}
 
-	router := routes.SetupRoutes(ctx, log, srv)
+	router := routes.SetupRoutes(ctx, cfg, log, srv, authenticator, limiter)
 
 	handler := handlers.CORS(corsOptions...)(router)
 
@@ -50,9 +62,9 @@ func (s *server) Start(ctx context.Context) {
 	s.server = &http.Server{
 		Addr:         addr,
 		Handler:      s.handler,
-		WriteTimeout: time.Second * 15,
-		ReadTimeout:  time.Second * 15,
-		IdleTimeout:  time.Second * 15,
+		WriteTimeout: s.cfg.HTTP.WriteTimeout,
+		ReadTimeout:  s.cfg.HTTP.ReadTimeout,
+		IdleTimeout:  s.cfg.HTTP.IdleTimeout,
 	}
 
 	go func() {
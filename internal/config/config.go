@@ -1,13 +1,27 @@
 package config
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	Logger  Logger
-	HTTP    HTTP
-	Storage Storage
+	Logger     Logger
+	HTTP       HTTP
+	GRPC       GRPC
+	Auth       Auth
+	RateLimit  RateLimit
+	Limits     Limits
+	Storage    Storage
+	Cache      Cache
+	Trash      Trash
+	Events     Events
+	Webhook    Webhook
+	Stats      Stats
+	Quarantine Quarantine
+	Namespace  Namespace
+	Metrics    Metrics
 }
 
 type Logger struct {
@@ -18,6 +32,45 @@ type Logger struct {
 type HTTP struct {
 	IP   string `envconfig:"HTTP_IP" default:"0.0.0.0"`
 	Port string `envconfig:"HTTP_PORT" default:"8082"`
+
+	ReadTimeout  time.Duration `envconfig:"HTTP_READ_TIMEOUT" default:"15s"`
+	WriteTimeout time.Duration `envconfig:"HTTP_WRITE_TIMEOUT" default:"15s"`
+	IdleTimeout  time.Duration `envconfig:"HTTP_IDLE_TIMEOUT" default:"15s"`
+}
+
+type GRPC struct {
+	IP   string `envconfig:"GRPC_IP" default:"0.0.0.0"`
+	Port string `envconfig:"GRPC_PORT" default:"9092"`
+}
+
+type Auth struct {
+	Groups           map[string]string `envconfig:"AUTH_GROUPS" default:"public:none,schemas:none,validate:none,admin:none"`
+	JWKSFile         string            `envconfig:"AUTH_JWKS_FILE"`
+	Issuer           string            `envconfig:"AUTH_JWT_ISSUER"`
+	Audience         string            `envconfig:"AUTH_JWT_AUDIENCE"`
+	BootstrapKeyHash string            `envconfig:"AUTH_BOOTSTRAP_KEY_HASH"`
+}
+
+type Metrics struct {
+	Enabled bool `envconfig:"METRICS_ENABLED" default:"true"`
+}
+
+type Limits struct {
+	MaxBodyBytes   int64 `envconfig:"LIMITS_MAX_BODY_BYTES" default:"4194304"`
+	MaxDepth       int   `envconfig:"LIMITS_MAX_JSON_DEPTH" default:"64"`
+	MaxArrayLength int   `envconfig:"LIMITS_MAX_JSON_ARRAY_LENGTH" default:"10000"`
+	MaxObjectKeys  int   `envconfig:"LIMITS_MAX_JSON_OBJECT_KEYS" default:"1000"`
+}
+
+type RateLimit struct {
+	Enabled   bool              `envconfig:"RATE_LIMIT_ENABLED" default:"false"`
+	Key       string            `envconfig:"RATE_LIMIT_KEY" default:"principal"`
+	Rate      float64           `envconfig:"RATE_LIMIT_RATE" default:"50"`
+	Burst     int               `envconfig:"RATE_LIMIT_BURST" default:"100"`
+	Routes    map[string]string `envconfig:"RATE_LIMIT_ROUTES"`
+	IP        string            `envconfig:"RATE_LIMIT_IP"`
+	Shared    bool              `envconfig:"RATE_LIMIT_SHARED" default:"false"`
+	Retention time.Duration     `envconfig:"RATE_LIMIT_RETENTION" default:"1h"`
 }
 
 type Storage struct {
@@ -26,6 +79,65 @@ type Storage struct {
 	User     string `envconfig:"DB_USER" default:"postgres"`
 	Name     string `envconfig:"DB_NAME" default:"json-validation-service"`
 	Password string `envconfig:"DB_PASSWORD" default:"mysecretpassword"`
+
+	MaxOpenConns     int           `envconfig:"DB_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns     int           `envconfig:"DB_MAX_IDLE_CONNS" default:"25"`
+	ConnMaxLifetime  time.Duration `envconfig:"DB_CONN_MAX_LIFETIME" default:"5m"`
+	StatementTimeout time.Duration `envconfig:"DB_STATEMENT_TIMEOUT" default:"30s"`
+	QueryTimeout     time.Duration `envconfig:"DB_QUERY_TIMEOUT" default:"10s"`
+
+	ConnectRetries    int           `envconfig:"DB_CONNECT_RETRIES" default:"10"`
+	ConnectBackoff    time.Duration `envconfig:"DB_CONNECT_BACKOFF" default:"500ms"`
+	ConnectMaxBackoff time.Duration `envconfig:"DB_CONNECT_MAX_BACKOFF" default:"30s"`
+}
+
+type Cache struct {
+	Enabled     bool          `envconfig:"CACHE_ENABLED" default:"true"`
+	TTL         time.Duration `envconfig:"CACHE_TTL" default:"1m"`
+	NegativeTTL time.Duration `envconfig:"CACHE_NEGATIVE_TTL" default:"5s"`
+	MaxEntries  int           `envconfig:"CACHE_MAX_ENTRIES" default:"1000"`
+}
+
+type Trash struct {
+	RetentionDays int           `envconfig:"TRASH_RETENTION_DAYS" default:"30"`
+	PurgeInterval time.Duration `envconfig:"TRASH_PURGE_INTERVAL" default:"1h"`
+}
+
+type Events struct {
+	Notify       bool          `envconfig:"EVENTS_NOTIFY" default:"false"`
+	PollInterval time.Duration `envconfig:"EVENTS_POLL_INTERVAL" default:"5s"`
+	Buffer       int           `envconfig:"EVENTS_BUFFER" default:"64"`
+}
+
+type Webhook struct {
+	PollInterval   time.Duration `envconfig:"WEBHOOK_POLL_INTERVAL" default:"1s"`
+	BatchSize      int           `envconfig:"WEBHOOK_BATCH_SIZE" default:"10"`
+	Timeout        time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"10s"`
+	MaxAttempts    int           `envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"8"`
+	InitialBackoff time.Duration `envconfig:"WEBHOOK_INITIAL_BACKOFF" default:"10s"`
+	MaxBackoff     time.Duration `envconfig:"WEBHOOK_MAX_BACKOFF" default:"1h"`
+
+	FailureSpikeThreshold int           `envconfig:"WEBHOOK_FAILURE_SPIKE_THRESHOLD" default:"100"`
+	FailureSpikeWindow    time.Duration `envconfig:"WEBHOOK_FAILURE_SPIKE_WINDOW" default:"1m"`
+}
+
+type Stats struct {
+	Enabled       bool          `envconfig:"STATS_ENABLED" default:"true"`
+	SampleRate    float64       `envconfig:"STATS_SAMPLE_RATE" default:"1"`
+	Buffer        int           `envconfig:"STATS_BUFFER" default:"10000"`
+	FlushInterval time.Duration `envconfig:"STATS_FLUSH_INTERVAL" default:"1s"`
+	Retention     time.Duration `envconfig:"STATS_RETENTION" default:"168h"`
+}
+
+type Quarantine struct {
+	MaxPayloadBytes int           `envconfig:"QUARANTINE_MAX_PAYLOAD_BYTES" default:"65536"`
+	MaxEntries      int           `envconfig:"QUARANTINE_MAX_ENTRIES" default:"1000"`
+	TTL             time.Duration `envconfig:"QUARANTINE_TTL" default:"72h"`
+}
+
+type Namespace struct {
+	MaxSchemas int            `envconfig:"NAMESPACE_MAX_SCHEMAS" default:"0"`
+	Quotas     map[string]int `envconfig:"NAMESPACE_QUOTAS"`
 }
 
 func Load() (*Config, error) {
@@ -0,0 +1,92 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/storage"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// Purger periodically removes the expired trash, quarantined payloads and rate limit buckets.
+type Purger struct {
+	cfg *config.Config
+	log logger.Logger
+	db  storage.Storage
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(cfg *config.Config, log logger.Logger, db storage.Storage) *Purger {
+	return &Purger{
+		cfg: cfg,
+		log: log,
+		db:  db,
+	}
+}
+
+func (p *Purger) Start(ctx context.Context) {
+	p.log.Debug(ctx, "starting trash purger")
+
+	ctx, p.cancel = context.WithCancel(contexts.WithActor(ctx, contexts.System))
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.cfg.Trash.PurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			p.Purge(ctx, time.Now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *Purger) Shutdown(ctx context.Context) {
+	p.log.Debug(ctx, "shutting down trash purger")
+
+	p.cancel()
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+	}
+}
+
+func (p *Purger) Purge(ctx context.Context, now time.Time) {
+	retention := time.Duration(p.cfg.Trash.RetentionDays) * 24 * time.Hour
+
+	n, err := p.db.PurgeSchemas(ctx, now.Add(-retention))
+	if err != nil {
+		p.log.Error(ctx, fmt.Errorf("%w:%v", exceptions.ErrPurgeSchemas, err), "could not purge trash")
+	} else if n > 0 {
+		p.log.Info(ctx, fmt.Sprintf("purged %d schemas from the trash", n))
+	}
+
+	n, err = p.db.PurgeQuarantine(ctx, now)
+	if err != nil {
+		p.log.Error(ctx, fmt.Errorf("%w:%v", exceptions.ErrPurgeQuarantine, err), "could not purge quarantine")
+	} else if n > 0 {
+		p.log.Info(ctx, fmt.Sprintf("purged %d payloads from the quarantine", n))
+	}
+
+	if !p.cfg.RateLimit.Enabled || !p.cfg.RateLimit.Shared {
+		return
+	}
+
+	if _, err = p.db.PurgeRateLimits(ctx, now.Add(-p.cfg.RateLimit.Retention)); err != nil {
+		p.log.Error(ctx, fmt.Errorf("%w:%v", exceptions.ErrPurgeRateLimits, err), "could not purge rate limits")
+	}
+}
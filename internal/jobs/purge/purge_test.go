@@ -0,0 +1,166 @@
+package purge_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/jobs/purge"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+)
+
+func TestPurger_Purge(t *testing.T) {
+	now := time.Date(2022, 11, 30, 12, 0, 0, 0, time.UTC)
+
+	tc := []struct {
+		name      string
+		retention int
+		storeStub func(store *mock_storage.MockStorage)
+	}{
+		{
+			name:      "purges schemas deleted before the retention",
+			retention: 30,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					PurgeSchemas(gomock.Any(), time.Date(2022, 10, 31, 12, 0, 0, 0, time.UTC)).
+					Times(1).
+					Return(int64(2), nil)
+				store.EXPECT().
+					PurgeQuarantine(gomock.Any(), now).
+					Times(1).
+					Return(int64(0), nil)
+			},
+		},
+		{
+			name:      "zero retention purges everything deleted until now",
+			retention: 0,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					PurgeSchemas(gomock.Any(), now).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					PurgeQuarantine(gomock.Any(), now).
+					Times(1).
+					Return(int64(0), nil)
+			},
+		},
+		{
+			name:      "store error is logged and the quarantine still purged",
+			retention: 30,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					PurgeSchemas(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), errors.New("error"))
+				store.EXPECT().
+					PurgeQuarantine(gomock.Any(), now).
+					Times(1).
+					Return(int64(3), nil)
+			},
+		},
+		{
+			name:      "quarantine error is logged",
+			retention: 30,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					PurgeSchemas(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					PurgeQuarantine(gomock.Any(), now).
+					Times(1).
+					Return(int64(0), errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			cfg := helperConfig(t)
+			cfg.Trash.RetentionDays = tt.retention
+
+			p := purge.New(cfg, logruslog.DefaultLogger(cfg), store)
+			p.Purge(context.TODO(), now)
+		})
+	}
+}
+
+func TestPurger_PurgeRateLimits(t *testing.T) {
+	now := time.Date(2022, 11, 30, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().PurgeSchemas(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+	store.EXPECT().PurgeQuarantine(gomock.Any(), now).Times(1).Return(int64(0), nil)
+	store.EXPECT().
+		PurgeRateLimits(gomock.Any(), now.Add(-time.Hour)).
+		Times(1).
+		Return(int64(5), nil)
+
+	cfg := helperConfig(t)
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.Shared = true
+	cfg.RateLimit.Retention = time.Hour
+
+	p := purge.New(cfg, logruslog.DefaultLogger(cfg), store)
+	p.Purge(context.TODO(), now)
+}
+
+func TestPurger_StartShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	purged := make(chan struct{}, 1)
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		PurgeSchemas(gomock.Any(), gomock.Any()).
+		MinTimes(1).
+		DoAndReturn(func(context.Context, time.Time) (int64, error) {
+			select {
+			case purged <- struct{}{}:
+			default:
+			}
+
+			return 0, nil
+		})
+	store.EXPECT().
+		PurgeQuarantine(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(int64(0), nil)
+
+	cfg := helperConfig(t)
+	cfg.Trash.PurgeInterval = time.Hour
+
+	p := purge.New(cfg, logruslog.DefaultLogger(cfg), store)
+	p.Start(context.TODO())
+
+	<-purged
+
+	p.Shutdown(context.TODO())
+}
+
+func helperConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg
+}
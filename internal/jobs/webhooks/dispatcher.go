@@ -0,0 +1,199 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
+	"github.com/KarolosLykos/json-validation-service/internal/storage"
+)
+
+// Headers of a delivery.
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const maxErrorLength = 512
+
+// Dispatcher posts the queued deliveries, retrying the failed ones with backoff until they run out of attempts.
+type Dispatcher struct {
+	cfg    *config.Config
+	log    logger.Logger
+	db     storage.Storage
+	client *http.Client
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(cfg *config.Config, log logger.Logger, db storage.Storage) *Dispatcher {
+	return &Dispatcher{
+		cfg:    cfg,
+		log:    log,
+		db:     db,
+		client: &http.Client{Timeout: cfg.Webhook.Timeout},
+	}
+}
+
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.log.Debug(ctx, "starting webhook dispatcher")
+
+	ctx, d.cancel = context.WithCancel(ctx)
+	d.done = make(chan struct{})
+
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.cfg.Webhook.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			d.Dispatch(ctx, time.Now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (d *Dispatcher) Shutdown(ctx context.Context) {
+	d.log.Debug(ctx, "shutting down webhook dispatcher")
+
+	d.cancel()
+
+	select {
+	case <-d.done:
+	case <-ctx.Done():
+	}
+}
+
+// Dispatch attempts the deliveries due at now.
+func (d *Dispatcher) Dispatch(ctx context.Context, now time.Time) {
+	// a delivery not completed by then, e.g. because this replica stopped, is attempted again. The batch is
+	// attempted one delivery after the other, each bounded by the timeout, with one more to record them.
+	leaseUntil := now.Add(time.Duration(d.cfg.Webhook.BatchSize+1) * d.cfg.Webhook.Timeout)
+
+	deliveries, err := d.db.ClaimDeliveries(ctx, now, d.cfg.Webhook.BatchSize, leaseUntil)
+	if err != nil {
+		d.log.Error(ctx, err, "could not claim webhook deliveries")
+
+		return
+	}
+
+	for _, delivery := range deliveries {
+		d.attempt(ctx, delivery, time.Now())
+
+		if err = d.db.UpdateDelivery(ctx, delivery); err != nil {
+			d.log.Error(ctx, err, fmt.Sprintf("could not record webhook delivery %d", delivery.ID))
+		}
+	}
+}
+
+// attempt posts the delivery, signed at now, and schedules its next attempt from now when it fails.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *webhook.Delivery, now time.Time) {
+	delivery.Attempts++
+
+	status, err := d.post(ctx, delivery, now)
+
+	delivery.ResponseStatus = status
+
+	if err == nil {
+		delivery.Status = webhook.StatusSucceeded
+		delivery.LastError = ""
+
+		return
+	}
+
+	delivery.LastError = truncate(err.Error(), maxErrorLength)
+
+	if delivery.Attempts >= d.cfg.Webhook.MaxAttempts {
+		d.log.Error(ctx, err, fmt.Sprintf("webhook delivery %d is dead after %d attempts", delivery.ID, delivery.Attempts))
+
+		delivery.Status = webhook.StatusDead
+
+		return
+	}
+
+	delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+}
+
+func (d *Dispatcher) post(ctx context.Context, delivery *webhook.Delivery, now time.Time) (int, error) {
+	if delivery.Webhook == nil {
+		return 0, fmt.Errorf("webhook %d not found", delivery.WebhookID)
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(delivery.Webhook.Secret, timestamp, delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	return res.StatusCode, nil
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.cfg.Webhook.InitialBackoff
+
+	for i := 1; i < attempts && backoff < d.cfg.Webhook.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > d.cfg.Webhook.MaxBackoff {
+		backoff = d.cfg.Webhook.MaxBackoff
+	}
+
+	return backoff
+}
+
+// Sign returns the HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return s[:n]
+}
@@ -0,0 +1,217 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/jobs/webhooks"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+)
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	now := time.Now()
+
+	tc := []struct {
+		name       string
+		status     int
+		attempts   int
+		expected   string
+		nextAt     time.Time
+		hasErr     bool
+		respStatus int
+	}{
+		{
+			name:       "delivered",
+			status:     http.StatusNoContent,
+			attempts:   0,
+			expected:   webhook.StatusSucceeded,
+			nextAt:     now,
+			respStatus: http.StatusNoContent,
+		},
+		{
+			name:       "retried with backoff",
+			status:     http.StatusServiceUnavailable,
+			attempts:   2,
+			expected:   webhook.StatusPending,
+			nextAt:     now.Add(40 * time.Second),
+			hasErr:     true,
+			respStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "backoff is bounded",
+			status:     http.StatusInternalServerError,
+			attempts:   6,
+			expected:   webhook.StatusPending,
+			nextAt:     now.Add(5 * time.Minute),
+			hasErr:     true,
+			respStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "dead after the last attempt",
+			status:     http.StatusInternalServerError,
+			attempts:   7,
+			expected:   webhook.StatusDead,
+			nextAt:     now,
+			hasErr:     true,
+			respStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			payload := []byte(`{"id":42,"type":"schema.updated","name":"config-schema"}`)
+
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, payload, body)
+				assert.Equal(t, "7", r.Header.Get(webhooks.HeaderID))
+				assert.Equal(t, "schema.updated", r.Header.Get(webhooks.HeaderEvent))
+				timestamp, err := strconv.ParseInt(r.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+				require.NoError(t, err)
+
+				assert.WithinDuration(t, now, time.Unix(timestamp, 0), 2*time.Second)
+				assert.Equal(t,
+					webhooks.Sign("s3cr3t", r.Header.Get(webhooks.HeaderTimestamp), body),
+					r.Header.Get(webhooks.HeaderSignature),
+				)
+
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			delivery := &webhook.Delivery{
+				ID:            7,
+				WebhookID:     1,
+				Webhook:       &webhook.Webhook{ID: 1, URL: receiver.URL, Secret: "s3cr3t"},
+				EventType:     "schema.updated",
+				Payload:       payload,
+				Status:        webhook.StatusPending,
+				Attempts:      tt.attempts,
+				NextAttemptAt: now,
+			}
+
+			store := mock_storage.NewMockStorage(ctrl)
+			store.EXPECT().
+				ClaimDeliveries(gomock.Any(), now, 10, now.Add(110*time.Second)).
+				Times(1).
+				Return([]*webhook.Delivery{delivery}, nil)
+			store.EXPECT().
+				UpdateDelivery(gomock.Any(), delivery).
+				Times(1).
+				Return(nil)
+
+			helperNewDispatcher(t, store).Dispatch(context.Background(), now)
+
+			assert.Equal(t, tt.expected, delivery.Status)
+			assert.Equal(t, tt.attempts+1, delivery.Attempts)
+			assert.WithinDuration(t, tt.nextAt, delivery.NextAttemptAt, time.Second)
+			assert.Equal(t, tt.respStatus, delivery.ResponseStatus)
+			assert.Equal(t, tt.hasErr, delivery.LastError != "")
+		})
+	}
+}
+
+func TestDispatcher_DispatchUnreachable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiver.Close()
+
+	delivery := &webhook.Delivery{
+		ID:      7,
+		Webhook: &webhook.Webhook{URL: receiver.URL},
+		Payload: []byte(`{}`),
+		Status:  webhook.StatusPending,
+	}
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().ClaimDeliveries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*webhook.Delivery{delivery}, nil)
+	store.EXPECT().UpdateDelivery(gomock.Any(), delivery).Return(nil)
+
+	helperNewDispatcher(t, store).Dispatch(context.Background(), now)
+
+	assert.Equal(t, webhook.StatusPending, delivery.Status)
+	assert.Equal(t, 0, delivery.ResponseStatus)
+	assert.NotEmpty(t, delivery.LastError)
+	assert.WithinDuration(t, now.Add(10*time.Second), delivery.NextAttemptAt, time.Second)
+}
+
+func TestDispatcher_DispatchBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+
+	var timestamps []string
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamps = append(timestamps, r.Header.Get(webhooks.HeaderTimestamp))
+
+		// slow enough for the attempts to be signed at different seconds.
+		time.Sleep(time.Second)
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	deliveries := []*webhook.Delivery{
+		{ID: 1, Webhook: &webhook.Webhook{URL: receiver.URL}, Payload: []byte(`{}`), Status: webhook.StatusPending},
+		{ID: 2, Webhook: &webhook.Webhook{URL: receiver.URL}, Payload: []byte(`{}`), Status: webhook.StatusPending},
+	}
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().ClaimDeliveries(gomock.Any(), now, 10, now.Add(110*time.Second)).Return(deliveries, nil)
+	store.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+
+	helperNewDispatcher(t, store).Dispatch(context.Background(), now)
+
+	// every attempt is signed and scheduled at the time it is made, not at the time of the claim.
+	require.Len(t, timestamps, 2)
+	assert.NotEqual(t, timestamps[0], timestamps[1])
+	assert.True(t, deliveries[1].NextAttemptAt.Sub(deliveries[0].NextAttemptAt) >= time.Second)
+}
+
+func TestDispatcher_DispatchClaimError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().ClaimDeliveries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+	store.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Times(0)
+
+	helperNewDispatcher(t, store).Dispatch(context.Background(), time.Now())
+}
+
+func helperNewDispatcher(t *testing.T, store *mock_storage.MockStorage) *webhooks.Dispatcher {
+	t.Helper()
+
+	cfg, _ := config.Load()
+	cfg.Webhook.MaxAttempts = 8
+	cfg.Webhook.BatchSize = 10
+	cfg.Webhook.Timeout = 10 * time.Second
+	cfg.Webhook.InitialBackoff = 10 * time.Second
+	cfg.Webhook.MaxBackoff = 5 * time.Minute
+
+	return webhooks.New(cfg, logruslog.DefaultLogger(cfg), store)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 )
 
+// Fields are the structured fields of a log line, passed along with its messages.
+type Fields map[string]interface{}
+
 type Logger interface {
 	Panic(ctx context.Context, err error, messages ...interface{})
 	Error(ctx context.Context, err error, messages ...interface{})
@@ -68,26 +68,27 @@ func setLevel(lvl string) logrus.Level {
 }
 
 func (l *logruslog) Panic(ctx context.Context, err error, messages ...interface{}) {
-	le := l.parseMessages(ctx, err)
+	le, messages := l.parseMessages(ctx, err, messages)
 	le.Panic(messages...)
 }
 
 func (l *logruslog) Error(ctx context.Context, err error, messages ...interface{}) {
-	le := l.parseMessages(ctx, err)
+	le, messages := l.parseMessages(ctx, err, messages)
 	le.Error(messages...)
 }
 
 func (l *logruslog) Info(ctx context.Context, messages ...interface{}) {
-	le := l.parseMessages(ctx, nil)
+	le, messages := l.parseMessages(ctx, nil, messages)
 	le.Info(messages...)
 }
 
 func (l *logruslog) Debug(ctx context.Context, messages ...interface{}) {
-	le := l.parseMessages(ctx, nil)
+	le, messages := l.parseMessages(ctx, nil, messages)
 	le.Debug(messages...)
 }
 
-func (l *logruslog) parseMessages(ctx context.Context, err error) *logrus.Entry {
+// parseMessages returns the entry with the logger.Fields among the messages, and the other messages.
+func (l *logruslog) parseMessages(ctx context.Context, err error, messages []interface{}) (*logrus.Entry, []interface{}) {
 	if ctx == nil {
 		ctx = context.TODO()
 	}
@@ -98,5 +99,17 @@ func (l *logruslog) parseMessages(ctx context.Context, err error) *logrus.Entry
 		e = e.WithField(string(Settings.ErrorKey), err.Error())
 	}
 
-	return e
+	rest := make([]interface{}, 0, len(messages))
+
+	for _, m := range messages {
+		if fields, ok := m.(logger.Fields); ok {
+			e = e.WithFields(logrus.Fields(fields))
+
+			continue
+		}
+
+		rest = append(rest, m)
+	}
+
+	return e, rest
 }
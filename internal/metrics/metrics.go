@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "json_validation"
+
+// Results of the validations and of the cache lookups.
+const (
+	ResultPass = "pass"
+	ResultFail = "fail"
+	ResultHit  = "hit"
+	ResultMiss = "miss"
+)
+
+// Registry holds the metrics of the service, along with those of the Go runtime and of the process.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts the HTTP requests by route template, method and status.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route template, method and status.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPDuration observes the latency of the HTTP requests by route template, method and status.
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the HTTP requests by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// Validations counts the validations by namespace, schema and result, pass or fail.
+	Validations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "validations_total",
+		Help:      "Validations of documents by namespace, schema and result.",
+	}, []string{"namespace", "schema", "result"})
+
+	// CompileDuration observes the time spent compiling the json-schema documents.
+	CompileDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "schema_compile_duration_seconds",
+		Help:      "Time spent compiling the json-schema documents.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+	})
+
+	// DBQueryDuration observes the latency of the database queries by operation and table.
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Latency of the database queries by operation and table.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 8),
+	}, []string{"operation", "table"})
+
+	// CacheRequests counts the lookups of the schema cache by result, hit or miss.
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Lookups of the schema cache by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		Validations,
+		CompileDuration,
+		DBQueryDuration,
+		CacheRequests,
+	)
+}
+
+// Register registers the collector, unless an identical one already is.
+func Register(c prometheus.Collector) error {
+	if err := Registry.Register(c); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Actions recorded in the audit log.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+
+	ActionActivate  = "activate"
+	ActionDeprecate = "deprecate"
+	ActionRetire    = "retire"
+)
+
+// Entry is an append-only record of a schema mutation.
+type Entry struct {
+	ID             int            `json:"id" gorm:"not null;column:id;primaryKey"`
+	Namespace      string         `json:"namespace" gorm:"not null;column:namespace;default:'default';index:idx_audit_log_namespace_schema_id"`
+	SchemaID       string         `json:"name" gorm:"not null;column:schema_id;index:idx_audit_log_namespace_schema_id"`
+	Action         string         `json:"action" gorm:"not null;column:action"`
+	Actor          string         `json:"actor" gorm:"not null;column:actor"`
+	RequestID      string         `json:"requestId,omitempty" gorm:"column:request_id"`
+	PreviousDigest string         `json:"previousDigest,omitempty" gorm:"column:previous_digest"`
+	NewDigest      string         `json:"newDigest,omitempty" gorm:"column:new_digest"`
+	Diff           datatypes.JSON `json:"diff,omitempty" gorm:"column:diff"`
+	CreatedAt      time.Time      `json:"createdAt" gorm:"column:created_at;autoCreateTime;index"`
+}
+
+// Filter narrows down a query of the audit log, to the schema IDs starting with one of the Prefixes if any.
+type Filter struct {
+	SchemaID string
+	Prefixes []string
+	Since    time.Time
+	Limit    int
+}
+
+func (Entry) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Methods authenticating the requests.
+const (
+	MethodAPIKey = "apikey"
+	MethodJWT    = "jwt"
+	MethodNone   = "none"
+)
+
+// KeyPrefix starts every API key, telling them apart from JWT bearer tokens.
+const KeyPrefix = "jvs_"
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject   string `json:"subject"`
+	Method    string `json:"method"`
+	Namespace string `json:"namespace,omitempty"`
+	Grants    Grants `json:"grants,omitempty"`
+}
+
+// APIKey is a static credential, of which only the SHA-256 hash is stored.
+type APIKey struct {
+	ID        uint      `json:"id" gorm:"not null;column:id;primaryKey"`
+	Name      string    `json:"name" gorm:"not null;column:name"`
+	Namespace string    `json:"namespace,omitempty" gorm:"not null;column:namespace;default:''"`
+	Prefix    string    `json:"prefix" gorm:"not null;column:prefix"`
+	Hash      string    `json:"-" gorm:"not null;column:hash;uniqueIndex"`
+	Grants    Grants    `json:"grants" gorm:"not null;column:grants;default:'[]'"`
+	Key       string    `json:"key,omitempty" gorm:"-"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// Anonymous returns the principal of the requests of the route groups open to anyone, granted every role.
+func Anonymous() *Principal {
+	return &Principal{
+		Subject: "anonymous",
+		Method:  MethodNone,
+		Grants:  Grants{{Role: RoleAdmin, Namespace: AnyNamespace}},
+	}
+}
+
+// String identifies the principal in the logs and the audit log, e.g. "apikey:ci".
+func (p *Principal) String() string {
+	return p.Method + ":" + p.Subject
+}
+
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
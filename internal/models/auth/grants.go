@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Roles granted to the principals, each one allowing what the previous ones do.
+const (
+	RoleReader    = "reader"    // download and list schemas
+	RoleValidator = "validator" // validate documents
+	RolePublisher = "publisher" // upload, update, delete and tag schemas
+	RoleAdmin     = "admin"     // manage webhooks and API keys
+)
+
+// AnyNamespace grants a role in every namespace.
+const AnyNamespace = "*"
+
+var ranks = map[string]int{
+	RoleReader:    1,
+	RoleValidator: 2,
+	RolePublisher: 3,
+	RoleAdmin:     4,
+}
+
+func ValidRole(role string) bool {
+	_, ok := ranks[role]
+
+	return ok
+}
+
+// Grant gives a role over the schemas of a namespace whose ID starts with the prefix, the home namespace of
+// the principal when empty.
+type Grant struct {
+	Role      string `json:"role"`
+	Namespace string `json:"namespace,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+}
+
+// Covers tells whether the grant spans the schema of the namespace, an empty schemaID standing for all of them.
+func (g Grant) Covers(home, namespace, schemaID string) bool {
+	scope := g.Namespace
+	if scope == "" {
+		scope = home
+	}
+
+	if scope != AnyNamespace && scope != namespace {
+		return false
+	}
+
+	return strings.HasPrefix(schemaID, g.Prefix)
+}
+
+// Grants are the grants of a principal, stored as jsonb.
+type Grants []Grant
+
+// Allows tells whether one of the grants gives the role, or a higher one, over the schema of the namespace.
+func (gs Grants) Allows(role, home, namespace, schemaID string) bool {
+	for _, g := range gs {
+		if ranks[g.Role] >= ranks[role] && g.Covers(home, namespace, schemaID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Prefixes returns the prefixes of the schema IDs over which the grants give the role, or all when one of
+// them spans the whole namespace.
+func (gs Grants) Prefixes(role, home, namespace string) (prefixes []string, all bool) {
+	for _, g := range gs {
+		if ranks[g.Role] < ranks[role] || !g.Covers(home, namespace, g.Prefix) {
+			continue
+		}
+
+		if g.Prefix == "" {
+			return nil, true
+		}
+
+		prefixes = append(prefixes, g.Prefix)
+	}
+
+	return prefixes, false
+}
+
+func (Grants) GormDataType() string {
+	return "jsonb"
+}
+
+func (gs Grants) Value() (driver.Value, error) {
+	if gs == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(gs)
+
+	return string(b), err
+}
+
+func (gs *Grants) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*gs = nil
+
+		return nil
+	case []byte:
+		return json.Unmarshal(v, gs)
+	case string:
+		return json.Unmarshal([]byte(v), gs)
+	default:
+		return fmt.Errorf("unsupported grants type %T", value)
+	}
+}
@@ -0,0 +1,32 @@
+package event
+
+import (
+	"time"
+)
+
+// Types of the schema change events.
+const (
+	TypeCreated  = "schema.created"
+	TypeUpdated  = "schema.updated"
+	TypeDeleted  = "schema.deleted"
+	TypeRestored = "schema.restored"
+	TypePurged   = "schema.purged"
+
+	TypeActivated  = "schema.activated"
+	TypeDeprecated = "schema.deprecated"
+	TypeRetired    = "schema.retired"
+)
+
+// Event is a change of a schema. Its ID orders the events and lets consumers resume after the last one they saw.
+type Event struct {
+	ID        uint64    `json:"id" gorm:"not null;column:id;primaryKey"`
+	Type      string    `json:"type" gorm:"not null;column:type"`
+	Namespace string    `json:"namespace" gorm:"not null;column:namespace;default:'default'"`
+	SchemaID  string    `json:"name" gorm:"not null;column:schema_id"`
+	Digest    string    `json:"digest,omitempty" gorm:"column:digest"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Event) TableName() string {
+	return "schema_events"
+}
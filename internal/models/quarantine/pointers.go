@@ -0,0 +1,39 @@
+package quarantine
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Pointers are JSON pointers (RFC 6901), stored as jsonb.
+type Pointers []string
+
+func (Pointers) GormDataType() string {
+	return "jsonb"
+}
+
+func (p Pointers) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(p)
+
+	return string(b), err
+}
+
+func (p *Pointers) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported pointers type %T", value)
+	}
+}
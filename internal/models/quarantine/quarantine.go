@@ -0,0 +1,57 @@
+package quarantine
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Redacted replaces the redacted values of a quarantined payload.
+const Redacted = "[REDACTED]"
+
+// Settings opt a schema in the quarantine of its failing payloads, the values at the Redact pointers never stored.
+type Settings struct {
+	Namespace string    `json:"namespace" gorm:"not null;column:namespace;default:'default';primaryKey"`
+	SchemaID  string    `json:"name" gorm:"not null;column:schema_id;primaryKey"`
+	Enabled   bool      `json:"enabled" gorm:"not null;column:enabled"`
+	Redact    Pointers  `json:"redact" gorm:"not null;column:redact"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// Entry is a payload that failed the validation against a version of a schema.
+type Entry struct {
+	ID        uint64         `json:"id" gorm:"not null;column:id;primaryKey"`
+	Namespace string         `json:"namespace" gorm:"not null;column:namespace;default:'default';index:idx_quarantine_namespace_schema_id"`
+	SchemaID  string         `json:"name" gorm:"not null;column:schema_id;index:idx_quarantine_namespace_schema_id"`
+	Version   int            `json:"version" gorm:"not null;column:version"`
+	Payload   datatypes.JSON `json:"payload" gorm:"not null;column:payload"`
+	Error     string         `json:"error" gorm:"not null;column:error"`
+	Redacted  bool           `json:"redacted" gorm:"not null;column:redacted;default:false"`
+	CreatedAt time.Time      `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	ExpiresAt time.Time      `json:"expiresAt" gorm:"not null;column:expires_at;index"`
+}
+
+// Replay is the outcome of validating the quarantined payloads of a schema again, against one of its versions.
+type Replay struct {
+	SchemaID string    `json:"name"`
+	Version  int       `json:"version"`
+	Passed   int       `json:"passed"`
+	Failed   int       `json:"failed"`
+	Results  []*Result `json:"results"`
+}
+
+// Result is the outcome of validating a quarantined payload again, only best effort when Redacted.
+type Result struct {
+	ID       uint64 `json:"id"`
+	Valid    bool   `json:"valid"`
+	Redacted bool   `json:"redacted,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (Settings) TableName() string {
+	return "quarantine_settings"
+}
+
+func (Entry) TableName() string {
+	return "quarantine"
+}
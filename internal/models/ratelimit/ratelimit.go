@@ -0,0 +1,17 @@
+package ratelimit
+
+import (
+	"time"
+)
+
+// Bucket is the token bucket of a client, shared by the replicas.
+type Bucket struct {
+	Key       string    `json:"key" gorm:"not null;column:key;primaryKey"`
+	Tokens    float64   `json:"tokens" gorm:"not null;column:tokens"`
+	Allowed   bool      `json:"allowed" gorm:"not null;column:allowed"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"not null;column:updated_at;index"`
+}
+
+func (Bucket) TableName() string {
+	return "rate_limit_buckets"
+}
@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Labels are stored as jsonb.
+type Labels map[string]string
+
+// ParseLabels parses labels in the form "key=value,key=value".
+func ParseLabels(s string) (Labels, error) {
+	labels := Labels{}
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("malformed label %q", pair)
+		}
+
+		labels[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	return labels, nil
+}
+
+func (Labels) GormDataType() string {
+	return "jsonb"
+}
+
+func (l Labels) Value() (driver.Value, error) {
+	if l == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(l)
+
+	return string(b), err
+}
+
+func (l *Labels) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("unsupported labels type %T", value)
+	}
+}
@@ -1,11 +1,143 @@
 package schema
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
+// Schema points a schema ID at the Blob of its current version.
 type Schema struct {
-	ID       int             `json:"id" gorm:"not null;column:id;primaryKey"`
-	SchemaID string          `json:"name" gorm:"not null;column:schema_id;uniqueIndex"`
-	Schema   *datatypes.JSON `json:"schema" gorm:"column:schema"`
+	ID        int             `json:"id" gorm:"not null;column:id;primaryKey"`
+	Namespace string          `json:"namespace" gorm:"not null;column:namespace;default:'default';uniqueIndex:idx_schemas_namespace_schema_id"`
+	SchemaID  string          `json:"name" gorm:"not null;column:schema_id;uniqueIndex:idx_schemas_namespace_schema_id"`
+	Schema    *datatypes.JSON `json:"schema" gorm:"-"`
+	Digest    string          `json:"digest" gorm:"not null;column:digest;default:''"`
+	Revision  int             `json:"revision" gorm:"not null;column:revision;default:1"`
+	Metadata  `gorm:"embedded"`
+	// DeletedAt is set while the schema is in the trash.
+	DeletedAt gorm.DeletedAt `json:"deletedAt" gorm:"column:deleted_at;index"`
+}
+
+// Blob is a canonicalized schema body, addressed by its SHA-256 digest.
+type Blob struct {
+	Digest    string    `json:"digest" gorm:"not null;column:digest;primaryKey"`
+	Body      string    `json:"body" gorm:"not null;column:body;type:text"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+// Version records the digest of every revision of a schema.
+type Version struct {
+	ID        int       `json:"-" gorm:"not null;column:id;primaryKey"`
+	Namespace string    `json:"namespace" gorm:"not null;column:namespace;default:'default';uniqueIndex:idx_schema_versions_namespace_schema_id_version"`
+	SchemaID  string    `json:"name" gorm:"not null;column:schema_id;uniqueIndex:idx_schema_versions_namespace_schema_id_version"`
+	Version   int       `json:"version" gorm:"not null;column:version;uniqueIndex:idx_schema_versions_namespace_schema_id_version"`
+	Digest    string    `json:"digest" gorm:"not null;column:digest;index"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+type Metadata struct {
+	Description string `json:"description,omitempty" gorm:"column:description"`
+	Owner       string `json:"owner,omitempty" gorm:"column:owner;index"`
+	Labels      Labels `json:"labels,omitempty" gorm:"column:labels"`
+	Lifecycle   `gorm:"embedded"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// Lifecycle states of a schema.
+const (
+	StateDraft      = "draft"
+	StateActive     = "active"
+	StateDeprecated = "deprecated"
+	StateRetired    = "retired"
+)
+
+// Lifecycle tells whether a schema is still meant to be used.
+type Lifecycle struct {
+	State        string     `json:"state,omitempty" gorm:"not null;column:state;default:'active'"`
+	DeprecatedAt *time.Time `json:"deprecatedAt,omitempty" gorm:"column:deprecated_at"`
+	Sunset       *time.Time `json:"sunset,omitempty" gorm:"column:sunset"`
+	Successor    string     `json:"successor,omitempty" gorm:"column:successor"`
+}
+
+// Info describes a schema without its body.
+type Info struct {
+	SchemaID string `json:"name"`
+	Digest   string `json:"digest"`
+	Revision int    `json:"revision"`
+	Metadata
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// Filter narrows down a listing of schemas, to the IDs starting with one of the Prefixes if any.
+type Filter struct {
+	Owner    string
+	Labels   Labels
+	Prefixes []string
+}
+
+// ImportMode tells how an import handles schemas that already exist.
+type ImportMode string
+
+const (
+	ImportSkipExisting   ImportMode = "skip-existing"
+	ImportOverwrite      ImportMode = "overwrite"
+	ImportFailOnConflict ImportMode = "fail-on-conflict"
+)
+
+// ImportResult lists the schemas affected by an import.
+type ImportResult struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Skipped []string `json:"skipped"`
+}
+
+func (Blob) TableName() string {
+	return "schema_blobs"
+}
+
+func (Version) TableName() string {
+	return "schema_versions"
+}
+
+// ETag is the strong entity tag of the schema.
+func (s *Schema) ETag() string {
+	return strconv.Quote(s.Digest)
+}
+
+// RawETag is the strong entity tag of the schema served as a raw document.
+func (s *Schema) RawETag() string {
+	return strconv.Quote(s.Digest + "-raw")
+}
+
+func (s *Schema) Info() *Info {
+	info := &Info{
+		SchemaID: s.SchemaID,
+		Digest:   s.Digest,
+		Revision: s.Revision,
+		Metadata: s.Metadata,
+	}
+
+	if s.DeletedAt.Valid {
+		info.DeletedAt = &s.DeletedAt.Time
+	}
+
+	return info
+}
+
+// Deprecated tells whether clients should be warned away from the schema.
+func (l *Lifecycle) Deprecated() bool {
+	return l.State == StateDeprecated || l.State == StateRetired
+}
+
+// Digest returns the hex encoded SHA-256 of the canonical schema.
+func Digest(schema string) string {
+	sum := sha256.Sum256([]byte(schema))
+
+	return hex.EncodeToString(sum[:])
 }
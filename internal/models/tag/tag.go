@@ -0,0 +1,43 @@
+package tag
+
+import (
+	"time"
+)
+
+// Separator splits a schema reference into a schema ID and a tag or version, e.g. "orders@stable" or "orders@3".
+const Separator = "@"
+
+// Latest is the tag a schema reference without tag resolves to when no schema has its ID, e.g. after a rename.
+const Latest = "latest"
+
+// Tag is a movable name under a schema ID, pointing at a version of a schema, the current one when Version is 0.
+type Tag struct {
+	Namespace string    `json:"namespace" gorm:"not null;column:namespace;default:'default';primaryKey"`
+	SchemaID  string    `json:"name" gorm:"not null;column:schema_id;primaryKey"`
+	Tag       string    `json:"tag" gorm:"not null;column:tag;primaryKey"`
+	Target    string    `json:"target" gorm:"not null;column:target;index"`
+	Version   int       `json:"version" gorm:"not null;column:version"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// Move records a tag being set, moved or deleted.
+type Move struct {
+	ID              uint64    `json:"id" gorm:"not null;column:id;primaryKey"`
+	Namespace       string    `json:"namespace" gorm:"not null;column:namespace;default:'default';index:idx_schema_tag_moves_namespace_schema_id_tag"`
+	SchemaID        string    `json:"name" gorm:"not null;column:schema_id;index:idx_schema_tag_moves_namespace_schema_id_tag"`
+	Tag             string    `json:"tag" gorm:"not null;column:tag;index:idx_schema_tag_moves_namespace_schema_id_tag"`
+	Target          string    `json:"target,omitempty" gorm:"not null;column:target"`
+	Version         int       `json:"version" gorm:"not null;column:version"`
+	PreviousTarget  string    `json:"previousTarget,omitempty" gorm:"not null;column:previous_target"`
+	PreviousVersion int       `json:"previousVersion" gorm:"not null;column:previous_version"`
+	Deleted         bool      `json:"deleted" gorm:"not null;column:deleted"`
+	CreatedAt       time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Tag) TableName() string {
+	return "schema_tags"
+}
+
+func (Move) TableName() string {
+	return "schema_tag_moves"
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"time"
+)
+
+// Result is the outcome of a validation against a schema.
+type Result struct {
+	ID           uint64    `json:"id" gorm:"not null;column:id;primaryKey"`
+	Namespace    string    `json:"namespace" gorm:"not null;column:namespace;default:'default';index:idx_validation_results_namespace_schema_id_created_at,priority:1"`
+	SchemaID     string    `json:"name" gorm:"not null;column:schema_id;index:idx_validation_results_namespace_schema_id_created_at,priority:2"`
+	Version      int       `json:"version" gorm:"not null;column:version"`
+	Valid        bool      `json:"valid" gorm:"not null;column:valid"`
+	Keyword      string    `json:"keyword,omitempty" gorm:"column:keyword"`
+	InstancePath string    `json:"instancePath,omitempty" gorm:"column:instance_path"`
+	Latency      int64     `json:"latencyUs" gorm:"not null;column:latency_us"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime;index:idx_validation_results_namespace_schema_id_created_at,priority:3"`
+}
+
+// Stats sums up the validations against a schema.
+type Stats struct {
+	SchemaID        string    `json:"name"`
+	SampleRate      float64   `json:"sampleRate"`
+	Windows         []*Window `json:"windows"`
+	TopFailingPaths []*Path   `json:"topFailingPaths"`
+}
+
+// Window sums up the validations within the last Duration.
+type Window struct {
+	Duration    time.Duration `json:"-"`
+	Window      string        `json:"window"`
+	Total       int64         `json:"total"`
+	Failed      int64         `json:"failed"`
+	FailureRate float64       `json:"failureRate"`
+	AvgLatency  float64       `json:"avgLatencyUs"`
+}
+
+// Path is a failing instance path and keyword, with the number of validations it failed.
+type Path struct {
+	InstancePath string `json:"instancePath"`
+	Keyword      string `json:"keyword"`
+	Count        int64  `json:"count"`
+}
+
+func (Result) TableName() string {
+	return "validation_results"
+}
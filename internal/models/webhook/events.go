@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Events are the types of events a webhook subscribes to, stored as jsonb.
+type Events []string
+
+func (Events) GormDataType() string {
+	return "jsonb"
+}
+
+func (e Events) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(e)
+
+	return string(b), err
+}
+
+func (e *Events) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*e = nil
+
+		return nil
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	default:
+		return fmt.Errorf("unsupported events type %T", value)
+	}
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TypeFailureSpike is sent when the validations failing against a schema exceed the configured threshold.
+const TypeFailureSpike = "validation.failure_spike"
+
+// Statuses of a delivery.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusDead      = "dead"
+)
+
+// Webhook is a subscription of a URL to some types of events, all of them if none is given.
+type Webhook struct {
+	ID        uint      `json:"id" gorm:"not null;column:id;primaryKey"`
+	URL       string    `json:"url" gorm:"not null;column:url"`
+	Secret    string    `json:"secret,omitempty" gorm:"not null;column:secret"`
+	Events    Events    `json:"events" gorm:"not null;column:events"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+}
+
+// Delivery is an event to be posted to a webhook, along with the outcome of the attempts so far.
+type Delivery struct {
+	ID             uint64         `json:"id" gorm:"not null;column:id;primaryKey"`
+	WebhookID      uint           `json:"webhookId" gorm:"not null;column:webhook_id;index"`
+	Webhook        *Webhook       `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	EventType      string         `json:"eventType" gorm:"not null;column:event_type"`
+	SchemaID       string         `json:"name" gorm:"not null;column:schema_id"`
+	Payload        datatypes.JSON `json:"payload" gorm:"not null;column:payload"`
+	Status         string         `json:"status" gorm:"not null;column:status;index"`
+	Attempts       int            `json:"attempts" gorm:"not null;column:attempts"`
+	NextAttemptAt  time.Time      `json:"nextAttemptAt" gorm:"not null;column:next_attempt_at;index"`
+	ResponseStatus int            `json:"responseStatus,omitempty" gorm:"column:response_status"`
+	LastError      string         `json:"lastError,omitempty" gorm:"column:last_error"`
+	CreatedAt      time.Time      `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time      `json:"updatedAt" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+type DeliveryFilter struct {
+	WebhookID uint
+	Status    string
+	Limit     int
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,309 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/storage"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+const batchSize = 100
+
+// Bus fans out the persisted schema events to the subscribers, reading them whenever notified and every
+// EVENTS_POLL_INTERVAL in any case.
+type Bus struct {
+	cfg *config.Config
+	log logger.Logger
+	db  storage.Storage
+
+	mu       sync.Mutex
+	closed   bool
+	ready    bool
+	cursor   uint64
+	subs     map[chan *event.Event]struct{}
+	watchers []func(e *event.Event)
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(cfg *config.Config, log logger.Logger, db storage.Storage) *Bus {
+	return &Bus{
+		cfg:  cfg,
+		log:  log,
+		db:   db,
+		subs: make(map[chan *event.Event]struct{}),
+		wake: make(chan struct{}, 1),
+	}
+}
+
+func (b *Bus) Start(ctx context.Context) {
+	b.log.Debug(ctx, "starting event bus")
+
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.done = make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	if b.cfg.Events.Notify {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			b.listen(ctx)
+		}()
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		b.run(ctx)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(b.done)
+	}()
+}
+
+// Shutdown ends the subscriptions, so that the streams serving them can complete.
+func (b *Bus) Shutdown(ctx context.Context) {
+	b.log.Debug(ctx, "shutting down event bus")
+
+	b.cancel()
+
+	b.mu.Lock()
+	b.closed = true
+
+	for live := range b.subs {
+		delete(b.subs, live)
+		close(live)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+	}
+}
+
+// Watch calls fn with every event, of any namespace, in order. fn must not block.
+func (b *Bus) Watch(fn func(e *event.Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.watchers = append(b.watchers, fn)
+}
+
+func (b *Bus) Notify() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe streams the events of the namespace of ctx after the ID, 0 meaning only the new ones. The channel
+// is closed once ctx is done, or when the subscriber falls too far behind and should subscribe again.
+func (b *Bus) Subscribe(ctx context.Context, after uint64) <-chan *event.Event {
+	live := make(chan *event.Event, b.cfg.Events.Buffer)
+	out := make(chan *event.Event)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(out)
+
+		return out
+	}
+
+	b.subs[live] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		defer close(out)
+		defer b.unsubscribe(live)
+
+		last := after
+		namespace := contexts.Namespace(ctx)
+
+		// the live events are buffered while replaying, the ones already replayed are skipped.
+		if after > 0 {
+			for {
+				events, err := b.db.ListEvents(ctx, last, batchSize)
+				if err != nil {
+					b.log.Error(ctx, err, "could not replay events")
+
+					return
+				}
+
+				for _, e := range events {
+					if e.Namespace != namespace {
+						last = e.ID
+
+						continue
+					}
+
+					select {
+					case out <- e:
+						last = e.ID
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				if len(events) < batchSize {
+					break
+				}
+			}
+		}
+
+		for {
+			select {
+			case e, ok := <-live:
+				if !ok {
+					return
+				}
+
+				if e.ID <= last || e.Namespace != namespace {
+					continue
+				}
+
+				select {
+				case out <- e:
+					last = e.ID
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func (b *Bus) unsubscribe(live chan *event.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[live]; ok {
+		delete(b.subs, live)
+		close(live)
+	}
+}
+
+// Poll publishes the events persisted since the last poll, the first one only looking up the last event.
+func (b *Bus) Poll(ctx context.Context) error {
+	if !b.started() {
+		cursor, err := b.db.LastEventID(ctx)
+		if err != nil {
+			return err
+		}
+
+		b.mu.Lock()
+		b.ready, b.cursor = true, cursor
+		b.mu.Unlock()
+
+		return nil
+	}
+
+	for {
+		b.mu.Lock()
+		cursor := b.cursor
+		b.mu.Unlock()
+
+		events, err := b.db.ListEvents(ctx, cursor, batchSize)
+		if err != nil {
+			return err
+		}
+
+		b.publish(events)
+
+		if len(events) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (b *Bus) started() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.ready
+}
+
+func (b *Bus) publish(events []*event.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, e := range events {
+		if e.ID <= b.cursor {
+			continue
+		}
+
+		b.cursor = e.ID
+
+		for _, fn := range b.watchers {
+			fn(e)
+		}
+
+		for live := range b.subs {
+			select {
+			case live <- e:
+			default:
+				// a slow subscriber is dropped rather than holding back the others.
+				delete(b.subs, live)
+				close(live)
+			}
+		}
+	}
+}
+
+func (b *Bus) run(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.Events.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.Poll(ctx); err != nil && ctx.Err() == nil {
+			b.log.Error(ctx, err, "could not poll events")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-b.wake:
+		}
+	}
+}
+
+func (b *Bus) listen(ctx context.Context) {
+	for {
+		err := b.db.Listen(ctx, b.Notify)
+		if ctx.Err() != nil {
+			return
+		}
+
+		b.log.Error(ctx, err, fmt.Sprintf("lost events notifications, retrying in %s", b.cfg.Events.PollInterval))
+
+		// events may have been missed while not listening.
+		b.Notify()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.cfg.Events.PollInterval):
+		}
+	}
+}
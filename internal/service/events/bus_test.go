@@ -0,0 +1,227 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/service/events"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+func TestBus_Poll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	gomock.InOrder(
+		store.EXPECT().LastEventID(gomock.Any()).Times(1).Return(uint64(5), nil),
+		store.EXPECT().ListEvents(gomock.Any(), uint64(5), gomock.Any()).Times(1).Return(helperEvents(6, 7), nil),
+		store.EXPECT().ListEvents(gomock.Any(), uint64(7), gomock.Any()).Times(1).Return(nil, nil),
+	)
+
+	bus := helperNewBus(t, store, 8)
+
+	require.NoError(t, bus.Poll(ctx))
+
+	sub := bus.Subscribe(ctx, 0)
+
+	require.NoError(t, bus.Poll(ctx))
+	require.NoError(t, bus.Poll(ctx))
+
+	assert.Equal(t, uint64(6), helperReceive(t, sub).ID)
+	assert.Equal(t, uint64(7), helperReceive(t, sub).ID)
+}
+
+func TestBus_SubscribeResume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replayed := make(chan struct{})
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().LastEventID(gomock.Any()).Times(1).Return(uint64(2), nil)
+	store.EXPECT().
+		ListEvents(gomock.Any(), uint64(1), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(context.Context, uint64, int) ([]*event.Event, error) {
+			defer close(replayed)
+
+			return helperEvents(2, 3), nil
+		})
+	store.EXPECT().ListEvents(gomock.Any(), uint64(2), gomock.Any()).Times(1).Return(helperEvents(3, 4), nil)
+
+	bus := helperNewBus(t, store, 8)
+	require.NoError(t, bus.Poll(ctx))
+
+	sub := bus.Subscribe(ctx, 1)
+	<-replayed
+
+	// event 3 is both replayed and published live, it must be received once.
+	require.NoError(t, bus.Poll(ctx))
+
+	for _, id := range []uint64{2, 3, 4} {
+		assert.Equal(t, id, helperReceive(t, sub).ID)
+	}
+}
+
+func TestBus_SubscribeNamespace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(contexts.WithNamespace(context.Background(), "team-a"))
+	defer cancel()
+
+	replayed := helperEvents(2, 3)
+	replayed[0].Namespace = "team-a"
+
+	live := helperEvents(4, 5)
+	live[1].Namespace = "team-a"
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().LastEventID(gomock.Any()).Times(1).Return(uint64(3), nil)
+	store.EXPECT().ListEvents(gomock.Any(), uint64(1), gomock.Any()).Times(1).Return(replayed, nil)
+	store.EXPECT().ListEvents(gomock.Any(), uint64(3), gomock.Any()).Times(1).Return(live, nil)
+
+	bus := helperNewBus(t, store, 8)
+	require.NoError(t, bus.Poll(ctx))
+
+	sub := bus.Subscribe(ctx, 1)
+
+	assert.Equal(t, uint64(2), helperReceive(t, sub).ID)
+
+	require.NoError(t, bus.Poll(ctx))
+
+	// the events of the other namespaces are skipped.
+	assert.Equal(t, uint64(5), helperReceive(t, sub).ID)
+}
+
+func TestBus_Watch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	events := helperEvents(6, 7)
+	events[1].Namespace = "team-a"
+
+	store := mock_storage.NewMockStorage(ctrl)
+	gomock.InOrder(
+		store.EXPECT().LastEventID(gomock.Any()).Times(1).Return(uint64(5), nil),
+		store.EXPECT().ListEvents(gomock.Any(), uint64(5), gomock.Any()).Times(1).Return(events, nil),
+	)
+
+	bus := helperNewBus(t, store, 8)
+
+	var watched []*event.Event
+
+	bus.Watch(func(e *event.Event) {
+		watched = append(watched, e)
+	})
+
+	require.NoError(t, bus.Poll(ctx))
+	require.NoError(t, bus.Poll(ctx))
+
+	// the events of every namespace are watched.
+	assert.Equal(t, events, watched)
+}
+
+func TestBus_SlowSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().LastEventID(gomock.Any()).Times(1).Return(uint64(0), nil)
+	store.EXPECT().ListEvents(gomock.Any(), uint64(0), gomock.Any()).Times(1).Return(helperEvents(1, 2, 3, 4), nil)
+
+	bus := helperNewBus(t, store, 1)
+	require.NoError(t, bus.Poll(ctx))
+
+	sub := bus.Subscribe(ctx, 0)
+
+	require.NoError(t, bus.Poll(ctx))
+
+	// the subscription is closed after what it could buffer, the client resumes from there.
+	var received []uint64
+	for e := range sub {
+		received = append(received, e.ID)
+	}
+
+	assert.NotEmpty(t, received)
+	assert.Less(t, len(received), 4)
+}
+
+func TestBus_Shutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().LastEventID(gomock.Any()).AnyTimes().Return(uint64(0), nil)
+
+	bus := helperNewBus(t, store, 8)
+	bus.Start(context.Background())
+
+	sub := bus.Subscribe(context.Background(), 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	bus.Shutdown(ctx)
+
+	_, ok := <-sub
+	assert.False(t, ok)
+
+	_, ok = <-bus.Subscribe(context.Background(), 0)
+	assert.False(t, ok)
+}
+
+func helperNewBus(t *testing.T, store *mock_storage.MockStorage, buffer int) *events.Bus {
+	t.Helper()
+
+	cfg, _ := config.Load()
+	cfg.Events.Buffer = buffer
+	cfg.Events.PollInterval = time.Hour
+
+	return events.New(cfg, logruslog.DefaultLogger(cfg), store)
+}
+
+func helperReceive(t *testing.T, sub <-chan *event.Event) *event.Event {
+	t.Helper()
+
+	select {
+	case e, ok := <-sub:
+		require.True(t, ok)
+
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+
+		return nil
+	}
+}
+
+func helperEvents(ids ...uint64) []*event.Event {
+	res := make([]*event.Event, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, &event.Event{ID: id, Type: event.TypeUpdated, Namespace: contexts.DefaultNamespace, SchemaID: "config-schema"})
+	}
+
+	return res
+}
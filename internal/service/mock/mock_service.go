@@ -8,6 +8,15 @@ import (
 	context "context"
 	reflect "reflect"
 
+	audit "github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	auth "github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	event "github.com/KarolosLykos/json-validation-service/internal/models/event"
+	quarantine "github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	ratelimit "github.com/KarolosLykos/json-validation-service/internal/models/ratelimit"
+	schema "github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	tag "github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	validation "github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	webhook "github.com/KarolosLykos/json-validation-service/internal/models/webhook"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,11 +43,110 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// AuthenticateAPIKey mocks base method.
+func (m *MockService) AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, key)
+	ret0, _ := ret[0].(*auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockServiceMockRecorder) AuthenticateAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockService)(nil).AuthenticateAPIKey), ctx, key)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockService) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockServiceMockRecorder) CreateAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockService)(nil).CreateAPIKey), ctx, key)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockService) CreateWebhook(ctx context.Context, wh *webhook.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, wh)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockServiceMockRecorder) CreateWebhook(ctx, wh interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockService)(nil).CreateWebhook), ctx, wh)
+}
+
+// DeleteAPIKey mocks base method.
+func (m *MockService) DeleteAPIKey(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAPIKey indicates an expected call of DeleteAPIKey.
+func (mr *MockServiceMockRecorder) DeleteAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKey", reflect.TypeOf((*MockService)(nil).DeleteAPIKey), ctx, id)
+}
+
+// DeleteSchema mocks base method.
+func (m *MockService) DeleteSchema(ctx context.Context, schemaID, digest string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSchema", ctx, schemaID, digest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSchema indicates an expected call of DeleteSchema.
+func (mr *MockServiceMockRecorder) DeleteSchema(ctx, schemaID, digest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSchema", reflect.TypeOf((*MockService)(nil).DeleteSchema), ctx, schemaID, digest)
+}
+
+// DeleteTag mocks base method.
+func (m *MockService) DeleteTag(ctx context.Context, schemaID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, schemaID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockServiceMockRecorder) DeleteTag(ctx, schemaID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockService)(nil).DeleteTag), ctx, schemaID, name)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockService) DeleteWebhook(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockServiceMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockService)(nil).DeleteWebhook), ctx, id)
+}
+
 // DownloadSchema mocks base method.
-func (m *MockService) DownloadSchema(ctx context.Context, schemaID string) (string, error) {
+func (m *MockService) DownloadSchema(ctx context.Context, schemaID string) (*schema.Schema, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DownloadSchema", ctx, schemaID)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*schema.Schema)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -49,26 +157,381 @@ func (mr *MockServiceMockRecorder) DownloadSchema(ctx, schemaID interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadSchema", reflect.TypeOf((*MockService)(nil).DownloadSchema), ctx, schemaID)
 }
 
+// DownloadSchemaByDigest mocks base method.
+func (m *MockService) DownloadSchemaByDigest(ctx context.Context, digest string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadSchemaByDigest", ctx, digest)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadSchemaByDigest indicates an expected call of DownloadSchemaByDigest.
+func (mr *MockServiceMockRecorder) DownloadSchemaByDigest(ctx, digest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadSchemaByDigest", reflect.TypeOf((*MockService)(nil).DownloadSchemaByDigest), ctx, digest)
+}
+
+// ExportSchemas mocks base method.
+func (m *MockService) ExportSchemas(ctx context.Context, filter schema.Filter, fn func(*schema.Schema) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportSchemas", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportSchemas indicates an expected call of ExportSchemas.
+func (mr *MockServiceMockRecorder) ExportSchemas(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSchemas", reflect.TypeOf((*MockService)(nil).ExportSchemas), ctx, filter, fn)
+}
+
+// GetQuarantineSettings mocks base method.
+func (m *MockService) GetQuarantineSettings(ctx context.Context, schemaID string) (*quarantine.Settings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuarantineSettings", ctx, schemaID)
+	ret0, _ := ret[0].(*quarantine.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuarantineSettings indicates an expected call of GetQuarantineSettings.
+func (mr *MockServiceMockRecorder) GetQuarantineSettings(ctx, schemaID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuarantineSettings", reflect.TypeOf((*MockService)(nil).GetQuarantineSettings), ctx, schemaID)
+}
+
+// GetWebhook mocks base method.
+func (m *MockService) GetWebhook(ctx context.Context, id uint) (*webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(*webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockServiceMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockService)(nil).GetWebhook), ctx, id)
+}
+
+// ImportSchemas mocks base method.
+func (m *MockService) ImportSchemas(ctx context.Context, schemas []*schema.Schema, mode schema.ImportMode) (*schema.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSchemas", ctx, schemas, mode)
+	ret0, _ := ret[0].(*schema.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportSchemas indicates an expected call of ImportSchemas.
+func (mr *MockServiceMockRecorder) ImportSchemas(ctx, schemas, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSchemas", reflect.TypeOf((*MockService)(nil).ImportSchemas), ctx, schemas, mode)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockService) ListAPIKeys(ctx context.Context) ([]*auth.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].([]*auth.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockServiceMockRecorder) ListAPIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockService)(nil).ListAPIKeys), ctx)
+}
+
+// ListAudit mocks base method.
+func (m *MockService) ListAudit(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudit", ctx, filter)
+	ret0, _ := ret[0].([]*audit.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAudit indicates an expected call of ListAudit.
+func (mr *MockServiceMockRecorder) ListAudit(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudit", reflect.TypeOf((*MockService)(nil).ListAudit), ctx, filter)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockService) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, filter)
+	ret0, _ := ret[0].([]*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockServiceMockRecorder) ListDeliveries(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockService)(nil).ListDeliveries), ctx, filter)
+}
+
+// ListQuarantine mocks base method.
+func (m *MockService) ListQuarantine(ctx context.Context, schemaID string, limit int) ([]*quarantine.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuarantine", ctx, schemaID, limit)
+	ret0, _ := ret[0].([]*quarantine.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuarantine indicates an expected call of ListQuarantine.
+func (mr *MockServiceMockRecorder) ListQuarantine(ctx, schemaID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuarantine", reflect.TypeOf((*MockService)(nil).ListQuarantine), ctx, schemaID, limit)
+}
+
+// ListSchemas mocks base method.
+func (m *MockService) ListSchemas(ctx context.Context, filter schema.Filter) ([]*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchemas", ctx, filter)
+	ret0, _ := ret[0].([]*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchemas indicates an expected call of ListSchemas.
+func (mr *MockServiceMockRecorder) ListSchemas(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchemas", reflect.TypeOf((*MockService)(nil).ListSchemas), ctx, filter)
+}
+
+// ListTags mocks base method.
+func (m *MockService) ListTags(ctx context.Context, schemaID string) ([]*tag.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, schemaID)
+	ret0, _ := ret[0].([]*tag.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockServiceMockRecorder) ListTags(ctx, schemaID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockService)(nil).ListTags), ctx, schemaID)
+}
+
+// ListTrash mocks base method.
+func (m *MockService) ListTrash(ctx context.Context) ([]*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx)
+	ret0, _ := ret[0].([]*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockServiceMockRecorder) ListTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockService)(nil).ListTrash), ctx)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockService) ListWebhooks(ctx context.Context) ([]*webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]*webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockServiceMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockService)(nil).ListWebhooks), ctx)
+}
+
+// PutTag mocks base method.
+func (m *MockService) PutTag(ctx context.Context, t *tag.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutTag", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutTag indicates an expected call of PutTag.
+func (mr *MockServiceMockRecorder) PutTag(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutTag", reflect.TypeOf((*MockService)(nil).PutTag), ctx, t)
+}
+
+// RedeliverDelivery mocks base method.
+func (m *MockService) RedeliverDelivery(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverDelivery", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeliverDelivery indicates an expected call of RedeliverDelivery.
+func (mr *MockServiceMockRecorder) RedeliverDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverDelivery", reflect.TypeOf((*MockService)(nil).RedeliverDelivery), ctx, id)
+}
+
+// ReplayQuarantine mocks base method.
+func (m *MockService) ReplayQuarantine(ctx context.Context, schemaID string, version int) (*quarantine.Replay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayQuarantine", ctx, schemaID, version)
+	ret0, _ := ret[0].(*quarantine.Replay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayQuarantine indicates an expected call of ReplayQuarantine.
+func (mr *MockServiceMockRecorder) ReplayQuarantine(ctx, schemaID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayQuarantine", reflect.TypeOf((*MockService)(nil).ReplayQuarantine), ctx, schemaID, version)
+}
+
+// RestoreSchema mocks base method.
+func (m *MockService) RestoreSchema(ctx context.Context, schemaID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSchema", ctx, schemaID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSchema indicates an expected call of RestoreSchema.
+func (mr *MockServiceMockRecorder) RestoreSchema(ctx, schemaID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSchema", reflect.TypeOf((*MockService)(nil).RestoreSchema), ctx, schemaID)
+}
+
+// SchemaStats mocks base method.
+func (m *MockService) SchemaStats(ctx context.Context, schemaID string) (*validation.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchemaStats", ctx, schemaID)
+	ret0, _ := ret[0].(*validation.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchemaStats indicates an expected call of SchemaStats.
+func (mr *MockServiceMockRecorder) SchemaStats(ctx, schemaID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaStats", reflect.TypeOf((*MockService)(nil).SchemaStats), ctx, schemaID)
+}
+
+// SubscribeEvents mocks base method.
+func (m *MockService) SubscribeEvents(ctx context.Context, lastEventID uint64) <-chan *event.Event {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeEvents", ctx, lastEventID)
+	ret0, _ := ret[0].(<-chan *event.Event)
+	return ret0
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents.
+func (mr *MockServiceMockRecorder) SubscribeEvents(ctx, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockService)(nil).SubscribeEvents), ctx, lastEventID)
+}
+
+// TagHistory mocks base method.
+func (m *MockService) TagHistory(ctx context.Context, schemaID, name string, limit int) ([]*tag.Move, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagHistory", ctx, schemaID, name, limit)
+	ret0, _ := ret[0].([]*tag.Move)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagHistory indicates an expected call of TagHistory.
+func (mr *MockServiceMockRecorder) TagHistory(ctx, schemaID, name, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagHistory", reflect.TypeOf((*MockService)(nil).TagHistory), ctx, schemaID, name, limit)
+}
+
+// TakeToken mocks base method.
+func (m *MockService) TakeToken(ctx context.Context, key string, rate float64, burst int) (*ratelimit.Bucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeToken", ctx, key, rate, burst)
+	ret0, _ := ret[0].(*ratelimit.Bucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeToken indicates an expected call of TakeToken.
+func (mr *MockServiceMockRecorder) TakeToken(ctx, key, rate, burst interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeToken", reflect.TypeOf((*MockService)(nil).TakeToken), ctx, key, rate, burst)
+}
+
+// UpdateLifecycle mocks base method.
+func (m *MockService) UpdateLifecycle(ctx context.Context, schemaID string, lifecycle schema.Lifecycle) (*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLifecycle", ctx, schemaID, lifecycle)
+	ret0, _ := ret[0].(*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLifecycle indicates an expected call of UpdateLifecycle.
+func (mr *MockServiceMockRecorder) UpdateLifecycle(ctx, schemaID, lifecycle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLifecycle", reflect.TypeOf((*MockService)(nil).UpdateLifecycle), ctx, schemaID, lifecycle)
+}
+
+// UpdateQuarantineSettings mocks base method.
+func (m *MockService) UpdateQuarantineSettings(ctx context.Context, settings *quarantine.Settings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQuarantineSettings", ctx, settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateQuarantineSettings indicates an expected call of UpdateQuarantineSettings.
+func (mr *MockServiceMockRecorder) UpdateQuarantineSettings(ctx, settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuarantineSettings", reflect.TypeOf((*MockService)(nil).UpdateQuarantineSettings), ctx, settings)
+}
+
+// UpdateSchema mocks base method.
+func (m *MockService) UpdateSchema(ctx context.Context, schemaID, payload, digest string) (*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchema", ctx, schemaID, payload, digest)
+	ret0, _ := ret[0].(*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSchema indicates an expected call of UpdateSchema.
+func (mr *MockServiceMockRecorder) UpdateSchema(ctx, schemaID, payload, digest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchema", reflect.TypeOf((*MockService)(nil).UpdateSchema), ctx, schemaID, payload, digest)
+}
+
 // UploadSchema mocks base method.
-func (m *MockService) UploadSchema(ctx context.Context, schemaID, schema string) error {
+func (m *MockService) UploadSchema(ctx context.Context, schemaID, payload string, meta schema.Metadata) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadSchema", ctx, schemaID, schema)
+	ret := m.ctrl.Call(m, "UploadSchema", ctx, schemaID, payload, meta)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UploadSchema indicates an expected call of UploadSchema.
-func (mr *MockServiceMockRecorder) UploadSchema(ctx, schemaID, schema interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) UploadSchema(ctx, schemaID, payload, meta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSchema", reflect.TypeOf((*MockService)(nil).UploadSchema), ctx, schemaID, schema)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSchema", reflect.TypeOf((*MockService)(nil).UploadSchema), ctx, schemaID, payload, meta)
 }
 
 // ValidateSchema mocks base method.
-func (m *MockService) ValidateSchema(ctx context.Context, schemaID string, payload map[string]interface{}) error {
+func (m *MockService) ValidateSchema(ctx context.Context, schemaID string, payload map[string]interface{}) (*schema.Schema, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ValidateSchema", ctx, schemaID, payload)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ValidateSchema indicates an expected call of ValidateSchema.
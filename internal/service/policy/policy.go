@@ -0,0 +1,404 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
+	"github.com/KarolosLykos/json-validation-service/internal/service"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// policy decorates a service.Service, denying the principal of the request the operations none of its
+// grants allow. The requests without any principal are denied.
+type policy struct {
+	service.Service
+
+	log logger.Logger
+}
+
+func New(log logger.Logger, next service.Service) service.Service {
+	return &policy{Service: next, log: log}
+}
+
+// authorize denies the request unless its principal is granted the role over the schema of the namespace.
+func (p *policy) authorize(ctx context.Context, role, namespace, schemaID string) error {
+	principal := contexts.Principal(ctx)
+	if principal != nil && principal.Grants.Allows(role, home(principal), namespace, schemaID) {
+		return nil
+	}
+
+	scope := "namespace " + namespace
+	if schemaID != "" {
+		scope = fmt.Sprintf("schema %s in namespace %s", schemaID, namespace)
+	}
+
+	if principal == nil {
+		p.log.Info(ctx, fmt.Sprintf("Policy: denied a request without principal the %s role over %s", role, scope))
+
+		return fmt.Errorf("%w: no principal for the %s role over %s", exceptions.ErrForbidden, role, scope)
+	}
+
+	p.log.Info(ctx, fmt.Sprintf("Policy: denied %s, lacking the %s role over %s", principal, role, scope))
+
+	return fmt.Errorf("%w: %s lacks the %s role over %s", exceptions.ErrForbidden, principal, role, scope)
+}
+
+func (p *policy) schema(ctx context.Context, role, ref string) error {
+	if i := strings.Index(ref, tag.Separator); i >= 0 {
+		ref = ref[:i]
+	}
+
+	return p.authorize(ctx, role, contexts.Namespace(ctx), ref)
+}
+
+func (p *policy) admin(ctx context.Context) error {
+	return p.authorize(ctx, auth.RoleAdmin, auth.AnyNamespace, "")
+}
+
+// allowed tells, without logging, whether the principal of the request may read the schema.
+func allowed(ctx context.Context, namespace, schemaID string) bool {
+	principal := contexts.Principal(ctx)
+
+	return principal != nil && principal.Grants.Allows(auth.RoleReader, home(principal), namespace, schemaID)
+}
+
+// home returns the namespace the grants without namespace are scoped to, the default one when unbound.
+func home(principal *auth.Principal) string {
+	if principal.Namespace != "" {
+		return principal.Namespace
+	}
+
+	return contexts.DefaultNamespace
+}
+
+// readablePrefixes returns the prefixes of the schema IDs the principal may read, none meaning all of them.
+func readablePrefixes(ctx context.Context) ([]string, bool) {
+	principal := contexts.Principal(ctx)
+	if principal == nil {
+		return nil, false
+	}
+
+	prefixes, all := principal.Grants.Prefixes(auth.RoleReader, home(principal), contexts.Namespace(ctx))
+
+	return prefixes, all || len(prefixes) > 0
+}
+
+func readable(ctx context.Context, schemas []*schema.Schema) []*schema.Schema {
+	kept := schemas[:0]
+
+	for _, s := range schemas {
+		if allowed(ctx, s.Namespace, s.SchemaID) {
+			kept = append(kept, s)
+		}
+	}
+
+	return kept
+}
+
+func (p *policy) UploadSchema(ctx context.Context, schemaID, payload string, meta schema.Metadata) error {
+	if err := p.schema(ctx, auth.RolePublisher, schemaID); err != nil {
+		return err
+	}
+
+	return p.Service.UploadSchema(ctx, schemaID, payload, meta)
+}
+
+func (p *policy) DownloadSchema(ctx context.Context, ref string) (*schema.Schema, error) {
+	if err := p.schema(ctx, auth.RoleReader, ref); err != nil {
+		return nil, err
+	}
+
+	return p.Service.DownloadSchema(ctx, ref)
+}
+
+// DownloadSchemaByDigest requires the reader role over the namespace as a whole, the bodies being content
+// addressed.
+func (p *policy) DownloadSchemaByDigest(ctx context.Context, digest string) (string, error) {
+	if err := p.authorize(ctx, auth.RoleReader, contexts.Namespace(ctx), ""); err != nil {
+		return "", err
+	}
+
+	return p.Service.DownloadSchemaByDigest(ctx, digest)
+}
+
+func (p *policy) UpdateSchema(ctx context.Context, schemaID, payload, digest string) (*schema.Schema, error) {
+	if err := p.schema(ctx, auth.RolePublisher, schemaID); err != nil {
+		return nil, err
+	}
+
+	return p.Service.UpdateSchema(ctx, schemaID, payload, digest)
+}
+
+func (p *policy) DeleteSchema(ctx context.Context, schemaID, digest string) error {
+	if err := p.schema(ctx, auth.RolePublisher, schemaID); err != nil {
+		return err
+	}
+
+	return p.Service.DeleteSchema(ctx, schemaID, digest)
+}
+
+func (p *policy) ListSchemas(ctx context.Context, filter schema.Filter) ([]*schema.Schema, error) {
+	prefixes, ok := readablePrefixes(ctx)
+	if !ok {
+		return []*schema.Schema{}, nil
+	}
+
+	filter.Prefixes = prefixes
+
+	return p.Service.ListSchemas(ctx, filter)
+}
+
+// ImportSchemas is denied as a whole unless the publisher role is granted over every schema of the bundle.
+func (p *policy) ImportSchemas(ctx context.Context, schemas []*schema.Schema, mode schema.ImportMode) (*schema.ImportResult, error) {
+	for _, s := range schemas {
+		if err := p.schema(ctx, auth.RolePublisher, s.SchemaID); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.Service.ImportSchemas(ctx, schemas, mode)
+}
+
+func (p *policy) ExportSchemas(ctx context.Context, filter schema.Filter, fn func(*schema.Schema) error) error {
+	prefixes, ok := readablePrefixes(ctx)
+	if !ok {
+		return nil
+	}
+
+	filter.Prefixes = prefixes
+
+	return p.Service.ExportSchemas(ctx, filter, fn)
+}
+
+func (p *policy) ListTrash(ctx context.Context) ([]*schema.Schema, error) {
+	schemas, err := p.Service.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return readable(ctx, schemas), nil
+}
+
+func (p *policy) RestoreSchema(ctx context.Context, schemaID string) error {
+	if err := p.schema(ctx, auth.RolePublisher, schemaID); err != nil {
+		return err
+	}
+
+	return p.Service.RestoreSchema(ctx, schemaID)
+}
+
+// ListAudit narrows the entries down in the storage, before the limit applies.
+func (p *policy) ListAudit(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	prefixes, ok := readablePrefixes(ctx)
+	if !ok {
+		return []*audit.Entry{}, nil
+	}
+
+	filter.Prefixes = prefixes
+
+	return p.Service.ListAudit(ctx, filter)
+}
+
+func (p *policy) CreateWebhook(ctx context.Context, wh *webhook.Webhook) error {
+	if err := p.admin(ctx); err != nil {
+		return err
+	}
+
+	return p.Service.CreateWebhook(ctx, wh)
+}
+
+func (p *policy) GetWebhook(ctx context.Context, id uint) (*webhook.Webhook, error) {
+	if err := p.admin(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.Service.GetWebhook(ctx, id)
+}
+
+func (p *policy) ListWebhooks(ctx context.Context) ([]*webhook.Webhook, error) {
+	if err := p.admin(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.Service.ListWebhooks(ctx)
+}
+
+func (p *policy) DeleteWebhook(ctx context.Context, id uint) error {
+	if err := p.admin(ctx); err != nil {
+		return err
+	}
+
+	return p.Service.DeleteWebhook(ctx, id)
+}
+
+func (p *policy) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	if err := p.admin(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.Service.ListDeliveries(ctx, filter)
+}
+
+func (p *policy) RedeliverDelivery(ctx context.Context, id uint64) error {
+	if err := p.admin(ctx); err != nil {
+		return err
+	}
+
+	return p.Service.RedeliverDelivery(ctx, id)
+}
+
+func (p *policy) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	if err := p.admin(ctx); err != nil {
+		return err
+	}
+
+	return p.Service.CreateAPIKey(ctx, key)
+}
+
+func (p *policy) ListAPIKeys(ctx context.Context) ([]*auth.APIKey, error) {
+	if err := p.admin(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.Service.ListAPIKeys(ctx)
+}
+
+func (p *policy) DeleteAPIKey(ctx context.Context, id uint) error {
+	if err := p.admin(ctx); err != nil {
+		return err
+	}
+
+	return p.Service.DeleteAPIKey(ctx, id)
+}
+
+func (p *policy) SubscribeEvents(ctx context.Context, lastEventID uint64) <-chan *event.Event {
+	events := p.Service.SubscribeEvents(ctx, lastEventID)
+
+	out := make(chan *event.Event)
+
+	go func() {
+		defer close(out)
+
+		for e := range events {
+			if !allowed(ctx, e.Namespace, e.SchemaID) {
+				continue
+			}
+
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func (p *policy) SchemaStats(ctx context.Context, schemaID string) (*validation.Stats, error) {
+	if err := p.schema(ctx, auth.RoleReader, schemaID); err != nil {
+		return nil, err
+	}
+
+	return p.Service.SchemaStats(ctx, schemaID)
+}
+
+func (p *policy) GetQuarantineSettings(ctx context.Context, schemaID string) (*quarantine.Settings, error) {
+	if err := p.schema(ctx, auth.RoleReader, schemaID); err != nil {
+		return nil, err
+	}
+
+	return p.Service.GetQuarantineSettings(ctx, schemaID)
+}
+
+func (p *policy) UpdateQuarantineSettings(ctx context.Context, settings *quarantine.Settings) error {
+	if err := p.schema(ctx, auth.RolePublisher, settings.SchemaID); err != nil {
+		return err
+	}
+
+	return p.Service.UpdateQuarantineSettings(ctx, settings)
+}
+
+func (p *policy) ListQuarantine(ctx context.Context, schemaID string, limit int) ([]*quarantine.Entry, error) {
+	if err := p.schema(ctx, auth.RoleReader, schemaID); err != nil {
+		return nil, err
+	}
+
+	return p.Service.ListQuarantine(ctx, schemaID, limit)
+}
+
+func (p *policy) ReplayQuarantine(ctx context.Context, schemaID string, version int) (*quarantine.Replay, error) {
+	if err := p.schema(ctx, auth.RoleValidator, schemaID); err != nil {
+		return nil, err
+	}
+
+	return p.Service.ReplayQuarantine(ctx, schemaID, version)
+}
+
+func (p *policy) ListTags(ctx context.Context, schemaID string) ([]*tag.Tag, error) {
+	if err := p.schema(ctx, auth.RoleReader, schemaID); err != nil {
+		return nil, err
+	}
+
+	return p.Service.ListTags(ctx, schemaID)
+}
+
+// PutTag requires the publisher role over the schema the tag is under, and over its target as well.
+func (p *policy) PutTag(ctx context.Context, t *tag.Tag) error {
+	if err := p.schema(ctx, auth.RolePublisher, t.SchemaID); err != nil {
+		return err
+	}
+
+	if t.Target != "" && t.Target != t.SchemaID {
+		if err := p.schema(ctx, auth.RolePublisher, t.Target); err != nil {
+			return err
+		}
+	}
+
+	return p.Service.PutTag(ctx, t)
+}
+
+func (p *policy) DeleteTag(ctx context.Context, schemaID, name string) error {
+	if err := p.schema(ctx, auth.RolePublisher, schemaID); err != nil {
+		return err
+	}
+
+	return p.Service.DeleteTag(ctx, schemaID, name)
+}
+
+func (p *policy) TagHistory(ctx context.Context, schemaID, name string, limit int) ([]*tag.Move, error) {
+	if err := p.schema(ctx, auth.RoleReader, schemaID); err != nil {
+		return nil, err
+	}
+
+	return p.Service.TagHistory(ctx, schemaID, name, limit)
+}
+
+func (p *policy) UpdateLifecycle(ctx context.Context, schemaID string, lifecycle schema.Lifecycle) (*schema.Schema, error) {
+	if err := p.schema(ctx, auth.RolePublisher, schemaID); err != nil {
+		return nil, err
+	}
+
+	return p.Service.UpdateLifecycle(ctx, schemaID, lifecycle)
+}
+
+// ValidateSchema requires the validator role over the schema ID of the reference, whichever schema its tag
+// points at.
+func (p *policy) ValidateSchema(ctx context.Context, ref string, payload map[string]interface{}) (*schema.Schema, error) {
+	if err := p.schema(ctx, auth.RoleValidator, ref); err != nil {
+		return nil, err
+	}
+
+	return p.Service.ValidateSchema(ctx, ref, payload)
+}
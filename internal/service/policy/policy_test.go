@@ -0,0 +1,358 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/service"
+	mock_service "github.com/KarolosLykos/json-validation-service/internal/service/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/service/policy"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestPolicy_Authorize(t *testing.T) {
+	tc := []struct {
+		name        string
+		grants      auth.Grants
+		home        string
+		namespace   string
+		call        func(ctx context.Context, srv service.Service) error
+		serviceStub func(srv *mock_service.MockService)
+		err         error
+	}{
+		{
+			name:      "publisher uploads",
+			grants:    auth.Grants{{Role: auth.RolePublisher, Namespace: "team-a", Prefix: "orders-"}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				return srv.UploadSchema(ctx, "orders-v1", "{}", schema.Metadata{})
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UploadSchema(gomock.Any(), "orders-v1", "{}", gomock.Any()).Times(1).Return(nil)
+			},
+		},
+		{
+			name:      "validator cannot upload",
+			grants:    auth.Grants{{Role: auth.RoleValidator}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				return srv.UploadSchema(ctx, "orders-v1", "{}", schema.Metadata{})
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+		{
+			name:      "publisher outside of its prefix",
+			grants:    auth.Grants{{Role: auth.RolePublisher, Prefix: "orders-"}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				return srv.UploadSchema(ctx, "invoices-v1", "{}", schema.Metadata{})
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+		{
+			name:      "publisher outside of its namespace",
+			grants:    auth.Grants{{Role: auth.RolePublisher, Namespace: "team-a"}},
+			namespace: "team-b",
+			call: func(ctx context.Context, srv service.Service) error {
+				return srv.UploadSchema(ctx, "orders-v1", "{}", schema.Metadata{})
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+		{
+			name:      "reader downloads a tag",
+			grants:    auth.Grants{{Role: auth.RoleReader, Namespace: auth.AnyNamespace, Prefix: "orders-"}},
+			namespace: "team-b",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.DownloadSchema(ctx, "orders-v1@stable")
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DownloadSchema(gomock.Any(), "orders-v1@stable").Times(1).Return(&schema.Schema{}, nil)
+			},
+		},
+		{
+			name:      "reader downloads a body of its namespace",
+			grants:    auth.Grants{{Role: auth.RoleReader, Namespace: "team-a"}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.DownloadSchemaByDigest(ctx, "abc")
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DownloadSchemaByDigest(gomock.Any(), "abc").Times(1).Return("{}", nil)
+			},
+		},
+		{
+			name:      "reader of a prefix cannot download bodies",
+			grants:    auth.Grants{{Role: auth.RoleReader, Namespace: "team-a", Prefix: "orders-"}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.DownloadSchemaByDigest(ctx, "abc")
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DownloadSchemaByDigest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+		{
+			name:      "reader cannot validate",
+			grants:    auth.Grants{{Role: auth.RoleReader}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.ValidateSchema(ctx, "orders-v1", map[string]interface{}{})
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ValidateSchema(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+		{
+			name:      "publisher validates",
+			grants:    auth.Grants{{Role: auth.RolePublisher}},
+			home:      "team-a",
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.ValidateSchema(ctx, "orders-v1", map[string]interface{}{})
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ValidateSchema(gomock.Any(), "orders-v1", gomock.Any()).Times(1).Return(&schema.Schema{}, nil)
+			},
+		},
+		{
+			name:      "bound publisher outside of its namespace",
+			grants:    auth.Grants{{Role: auth.RolePublisher}},
+			home:      "team-a",
+			namespace: "team-b",
+			call: func(ctx context.Context, srv service.Service) error {
+				return srv.UploadSchema(ctx, "orders-v1", "{}", schema.Metadata{})
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+		{
+			name:      "unbound reader in the default namespace",
+			grants:    auth.Grants{{Role: auth.RoleReader}},
+			namespace: contexts.DefaultNamespace,
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.DownloadSchema(ctx, "orders-v1")
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DownloadSchema(gomock.Any(), "orders-v1").Times(1).Return(&schema.Schema{}, nil)
+			},
+		},
+		{
+			name:      "unbound reader outside of the default namespace",
+			grants:    auth.Grants{{Role: auth.RoleReader}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.DownloadSchema(ctx, "orders-v1")
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DownloadSchema(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+		{
+			name:      "namespace admin cannot manage api keys",
+			grants:    auth.Grants{{Role: auth.RoleAdmin, Namespace: "team-a"}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.ListAPIKeys(ctx)
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ListAPIKeys(gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+		{
+			name:      "admin manages api keys",
+			grants:    auth.Grants{{Role: auth.RoleAdmin, Namespace: auth.AnyNamespace}},
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.ListAPIKeys(ctx)
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().ListAPIKeys(gomock.Any()).Times(1).Return(nil, nil)
+			},
+		},
+		{
+			name:      "no grants",
+			namespace: "team-a",
+			call: func(ctx context.Context, srv service.Service) error {
+				_, err := srv.DownloadSchema(ctx, "orders-v1")
+
+				return err
+			},
+			serviceStub: func(srv *mock_service.MockService) {
+				srv.EXPECT().DownloadSchema(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, next := helperNewPolicy(t)
+			tt.serviceStub(next)
+
+			principal := &auth.Principal{Subject: "ci", Method: auth.MethodAPIKey, Namespace: tt.home, Grants: tt.grants}
+			ctx := contexts.WithNamespace(contexts.WithPrincipal(context.TODO(), principal), tt.namespace)
+
+			err := tt.call(ctx, srv)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_Anonymous(t *testing.T) {
+	srv, next := helperNewPolicy(t)
+	next.EXPECT().UploadSchema(gomock.Any(), "orders-v1", "{}", gomock.Any()).Times(1).Return(nil)
+
+	ctx := contexts.WithNamespace(contexts.WithPrincipal(context.TODO(), auth.Anonymous()), "team-a")
+
+	require.NoError(t, srv.UploadSchema(ctx, "orders-v1", "{}", schema.Metadata{}))
+}
+
+func TestPolicy_NoPrincipal(t *testing.T) {
+	srv, next := helperNewPolicy(t)
+	next.EXPECT().UploadSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// the requests not authenticated at all are denied rather than let through.
+	assert.ErrorIs(t, srv.UploadSchema(context.TODO(), "orders-v1", "{}", schema.Metadata{}), exceptions.ErrForbidden)
+}
+
+func TestPolicy_ListSchemas(t *testing.T) {
+	tc := []struct {
+		name     string
+		grants   auth.Grants
+		called   bool
+		prefixes []string
+	}{
+		{
+			name:   "whole namespace",
+			grants: auth.Grants{{Role: auth.RoleReader}, {Role: auth.RoleReader, Prefix: "orders-"}},
+			called: true,
+		},
+		{
+			name:     "prefixes",
+			grants:   auth.Grants{{Role: auth.RoleReader, Prefix: "orders-"}, {Role: auth.RolePublisher, Prefix: "invoices-"}},
+			called:   true,
+			prefixes: []string{"orders-", "invoices-"},
+		},
+		{
+			name:   "other namespace",
+			grants: auth.Grants{{Role: auth.RoleReader, Namespace: "team-b"}},
+		},
+		{
+			name: "no grants",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, next := helperNewPolicy(t)
+
+			if tt.called {
+				// the schemas are narrowed down by the storage, rather than once listed.
+				next.EXPECT().
+					ListSchemas(gomock.Any(), schema.Filter{Owner: "team", Prefixes: tt.prefixes}).
+					Times(1).
+					Return([]*schema.Schema{{Namespace: "team-a", SchemaID: "orders-v1"}}, nil)
+				next.EXPECT().
+					ListAudit(gomock.Any(), audit.Filter{Prefixes: tt.prefixes, Limit: 10}).
+					Times(1).
+					Return([]*audit.Entry{{Namespace: "team-a", SchemaID: "orders-v1"}}, nil)
+			}
+
+			principal := &auth.Principal{Subject: "ci", Method: auth.MethodAPIKey, Namespace: "team-a", Grants: tt.grants}
+			ctx := contexts.WithNamespace(contexts.WithPrincipal(context.TODO(), principal), "team-a")
+
+			schemas, err := srv.ListSchemas(ctx, schema.Filter{Owner: "team"})
+			require.NoError(t, err)
+
+			entries, err := srv.ListAudit(ctx, audit.Filter{Limit: 10})
+			require.NoError(t, err)
+
+			if tt.called {
+				assert.Len(t, schemas, 1)
+				assert.Len(t, entries, 1)
+			} else {
+				assert.Empty(t, schemas)
+				assert.Empty(t, entries)
+			}
+		})
+	}
+}
+
+func TestPolicy_SubscribeEvents(t *testing.T) {
+	srv, next := helperNewPolicy(t)
+
+	events := make(chan *event.Event, 3)
+	events <- &event.Event{ID: 1, Namespace: "team-a", SchemaID: "orders-v1"}
+	events <- &event.Event{ID: 2, Namespace: "team-b", SchemaID: "orders-v1"}
+	events <- &event.Event{ID: 3, Namespace: "team-a", SchemaID: "invoices-v1"}
+	close(events)
+
+	next.EXPECT().SubscribeEvents(gomock.Any(), uint64(0)).Times(1).Return(events)
+
+	grants := auth.Grants{{Role: auth.RoleReader, Namespace: "team-a", Prefix: "orders-"}}
+	ctx := contexts.WithPrincipal(context.TODO(), &auth.Principal{Subject: "ci", Method: auth.MethodAPIKey, Grants: grants})
+
+	var ids []uint64
+	for e := range srv.SubscribeEvents(ctx, 0) {
+		ids = append(ids, e.ID)
+	}
+
+	assert.Equal(t, []uint64{1}, ids)
+}
+
+func helperNewPolicy(t *testing.T) (service.Service, *mock_service.MockService) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	next := mock_service.NewMockService(ctrl)
+
+	return policy.New(logruslog.DefaultLogger(&config.Config{}), next), next
+}
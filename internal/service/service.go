@@ -2,10 +2,51 @@ package service
 
 import (
 	"context"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/models/ratelimit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
 )
 
 type Service interface {
-	UploadSchema(ctx context.Context, schemaID, schema string) error
-	DownloadSchema(ctx context.Context, schemaID string) (string, error)
-	ValidateSchema(ctx context.Context, schemaID string, payload map[string]interface{}) error
+	UploadSchema(ctx context.Context, schemaID, payload string, meta schema.Metadata) error
+	DownloadSchema(ctx context.Context, schemaID string) (*schema.Schema, error)
+	DownloadSchemaByDigest(ctx context.Context, digest string) (string, error)
+	UpdateSchema(ctx context.Context, schemaID, payload, digest string) (*schema.Schema, error)
+	DeleteSchema(ctx context.Context, schemaID, digest string) error
+	ListSchemas(ctx context.Context, filter schema.Filter) ([]*schema.Schema, error)
+	ImportSchemas(ctx context.Context, schemas []*schema.Schema, mode schema.ImportMode) (*schema.ImportResult, error)
+	ExportSchemas(ctx context.Context, filter schema.Filter, fn func(*schema.Schema) error) error
+	ListTrash(ctx context.Context) ([]*schema.Schema, error)
+	RestoreSchema(ctx context.Context, schemaID string) error
+	ListAudit(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error)
+	CreateWebhook(ctx context.Context, wh *webhook.Webhook) error
+	GetWebhook(ctx context.Context, id uint) (*webhook.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*webhook.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error)
+	RedeliverDelivery(ctx context.Context, id uint64) error
+	CreateAPIKey(ctx context.Context, key *auth.APIKey) error
+	ListAPIKeys(ctx context.Context) ([]*auth.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id uint) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+	TakeToken(ctx context.Context, key string, rate float64, burst int) (*ratelimit.Bucket, error)
+	SubscribeEvents(ctx context.Context, lastEventID uint64) <-chan *event.Event
+	SchemaStats(ctx context.Context, schemaID string) (*validation.Stats, error)
+	GetQuarantineSettings(ctx context.Context, schemaID string) (*quarantine.Settings, error)
+	UpdateQuarantineSettings(ctx context.Context, settings *quarantine.Settings) error
+	ListQuarantine(ctx context.Context, schemaID string, limit int) ([]*quarantine.Entry, error)
+	ReplayQuarantine(ctx context.Context, schemaID string, version int) (*quarantine.Replay, error)
+	ListTags(ctx context.Context, schemaID string) ([]*tag.Tag, error)
+	PutTag(ctx context.Context, t *tag.Tag) error
+	DeleteTag(ctx context.Context, schemaID, name string) error
+	TagHistory(ctx context.Context, schemaID, name string, limit int) ([]*tag.Move, error)
+	UpdateLifecycle(ctx context.Context, schemaID string, lifecycle schema.Lifecycle) (*schema.Schema, error)
+	ValidateSchema(ctx context.Context, schemaID string, payload map[string]interface{}) (*schema.Schema, error)
 }
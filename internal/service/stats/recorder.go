@@ -0,0 +1,159 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/storage"
+)
+
+const batchSize = 500
+
+// Recorder stores a sample of the validation results in the background.
+type Recorder struct {
+	cfg *config.Config
+	log logger.Logger
+	db  storage.Storage
+
+	results chan *validation.Result
+	dropped uint64
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(cfg *config.Config, log logger.Logger, db storage.Storage) *Recorder {
+	return &Recorder{
+		cfg:     cfg,
+		log:     log,
+		db:      db,
+		results: make(chan *validation.Result, cfg.Stats.Buffer),
+		rnd:     rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec // sampling needs no crypto.
+	}
+}
+
+func (r *Recorder) Start(ctx context.Context) {
+	r.log.Debug(ctx, "starting validation stats recorder")
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		flush := time.NewTicker(r.cfg.Stats.FlushInterval)
+		defer flush.Stop()
+
+		purge := time.NewTicker(time.Hour)
+		defer purge.Stop()
+
+		batch := make([]*validation.Result, 0, batchSize)
+
+		for {
+			select {
+			case <-ctx.Done():
+				// the pending results are stored before leaving, regardless of ctx.
+				r.drain(&batch)
+				r.flush(context.Background(), batch)
+
+				return
+			case res := <-r.results:
+				batch = append(batch, res)
+				if len(batch) < batchSize {
+					continue
+				}
+			case <-flush.C:
+			case <-purge.C:
+				r.Purge(ctx, time.Now())
+
+				continue
+			}
+
+			r.flush(ctx, batch)
+			batch = batch[:0]
+		}
+	}()
+}
+
+func (r *Recorder) Shutdown(ctx context.Context) {
+	r.log.Debug(ctx, "shutting down validation stats recorder")
+
+	r.cancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+	}
+}
+
+func (r *Recorder) Sample() bool {
+	if !r.cfg.Stats.Enabled || r.cfg.Stats.SampleRate <= 0 {
+		return false
+	}
+
+	if r.cfg.Stats.SampleRate >= 1 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rnd.Float64() < r.cfg.Stats.SampleRate
+}
+
+// Record queues the result, dropping it while the queue is full.
+func (r *Recorder) Record(res *validation.Result) {
+	select {
+	case r.results <- res:
+	default:
+		atomic.AddUint64(&r.dropped, 1)
+	}
+}
+
+func (r *Recorder) Purge(ctx context.Context, now time.Time) {
+	n, err := r.db.PurgeValidations(ctx, now.Add(-r.cfg.Stats.Retention))
+	if err != nil {
+		r.log.Error(ctx, err, "could not purge validation results")
+
+		return
+	}
+
+	if n > 0 {
+		r.log.Debug(ctx, fmt.Sprintf("purged %d validation results", n))
+	}
+}
+
+func (r *Recorder) drain(batch *[]*validation.Result) {
+	for {
+		select {
+		case res := <-r.results:
+			*batch = append(*batch, res)
+		default:
+			return
+		}
+	}
+}
+
+func (r *Recorder) flush(ctx context.Context, batch []*validation.Result) {
+	if dropped := atomic.SwapUint64(&r.dropped, 0); dropped > 0 {
+		r.log.Error(ctx, fmt.Errorf("%d results dropped", dropped), "validation stats recorder is falling behind")
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := r.db.RecordValidations(ctx, batch); err != nil {
+		r.log.Error(ctx, err, fmt.Sprintf("could not record %d validation results", len(batch)))
+	}
+}
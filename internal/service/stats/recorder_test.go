@@ -0,0 +1,119 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/service/stats"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+)
+
+func TestRecorder_Shutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	results := []*validation.Result{
+		{SchemaID: "config-schema", Valid: true},
+		{SchemaID: "config-schema", Valid: false, Keyword: "required", InstancePath: "#"},
+	}
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		RecordValidations(gomock.Any(), results).
+		Times(1).
+		Return(nil)
+
+	r := helperNewRecorder(t, store, 10)
+
+	// the results still queued are stored on shutdown.
+	for _, res := range results {
+		r.Record(res)
+	}
+
+	r.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r.Shutdown(ctx)
+}
+
+func TestRecorder_RecordFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		RecordValidations(gomock.Any(), gomock.Len(1)).
+		Times(1).
+		Return(nil)
+
+	r := helperNewRecorder(t, store, 1)
+
+	// recording never blocks, results exceeding the buffer are dropped.
+	r.Record(&validation.Result{SchemaID: "config-schema"})
+	r.Record(&validation.Result{SchemaID: "config-schema"})
+
+	r.Start(context.Background())
+	r.Shutdown(context.Background())
+}
+
+func TestRecorder_Sample(t *testing.T) {
+	tc := []struct {
+		name    string
+		enabled bool
+		rate    float64
+		sampled bool
+	}{
+		{name: "everything", enabled: true, rate: 1, sampled: true},
+		{name: "nothing", enabled: true, rate: 0, sampled: false},
+		{name: "disabled", enabled: false, rate: 1, sampled: false},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _ := config.Load()
+			cfg.Stats.Enabled = tt.enabled
+			cfg.Stats.SampleRate = tt.rate
+
+			r := stats.New(cfg, logruslog.DefaultLogger(cfg), nil)
+
+			for i := 0; i < 10; i++ {
+				assert.Equal(t, tt.sampled, r.Sample())
+			}
+		})
+	}
+}
+
+func TestRecorder_Purge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2022, 11, 30, 12, 0, 0, 0, time.UTC)
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		PurgeValidations(gomock.Any(), time.Date(2022, 11, 23, 12, 0, 0, 0, time.UTC)).
+		Times(1).
+		Return(int64(3), nil)
+
+	helperNewRecorder(t, store, 1).Purge(context.Background(), now)
+}
+
+func helperNewRecorder(t *testing.T, store *mock_storage.MockStorage, buffer int) *stats.Recorder {
+	t.Helper()
+
+	cfg, _ := config.Load()
+	cfg.Stats.Buffer = buffer
+	cfg.Stats.FlushInterval = time.Hour
+	cfg.Stats.Retention = 7 * 24 * time.Hour
+
+	return stats.New(cfg, logruslog.DefaultLogger(cfg), store)
+}
@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// keyPrefixLength is the length of the start of the API keys kept in clear.
+const keyPrefixLength = len(auth.KeyPrefix) + 8
+
+// CreateAPIKey generates a new API key, only ever returned here.
+func (v *Validator) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	v.log.Debug(ctx, "Validator: creating api key")
+
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" {
+		return fmt.Errorf("%w: name is required", exceptions.ErrInvalidAPIKey)
+	}
+
+	if key.Namespace != "" && !contexts.ValidNamespace(key.Namespace) {
+		return fmt.Errorf("%w: invalid namespace %q", exceptions.ErrInvalidAPIKey, key.Namespace)
+	}
+
+	for _, g := range key.Grants {
+		if !auth.ValidRole(g.Role) {
+			return fmt.Errorf("%w: unknown role %q", exceptions.ErrInvalidAPIKey, g.Role)
+		}
+
+		if g.Namespace != "" && g.Namespace != auth.AnyNamespace && !contexts.ValidNamespace(g.Namespace) {
+			return fmt.Errorf("%w: invalid grant namespace %q", exceptions.ErrInvalidAPIKey, g.Namespace)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("%w:%v", exceptions.ErrCreateAPIKey, err)
+	}
+
+	key.Key = auth.KeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	key.Prefix = key.Key[:keyPrefixLength]
+	key.Hash = auth.HashKey(key.Key)
+
+	if err := v.db.CreateAPIKey(ctx, key); err != nil {
+		return fmt.Errorf("%w:%v", exceptions.ErrCreateAPIKey, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) ListAPIKeys(ctx context.Context) ([]*auth.APIKey, error) {
+	v.log.Debug(ctx, "Validator: listing api keys")
+
+	keys, err := v.db.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListAPIKeys, err)
+	}
+
+	return keys, nil
+}
+
+func (v *Validator) DeleteAPIKey(ctx context.Context, id uint) error {
+	v.log.Debug(ctx, "Validator: deleting api key")
+
+	if err := v.db.DeleteAPIKey(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.ErrNotFound
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrDeleteAPIKey, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	if !strings.HasPrefix(key, auth.KeyPrefix) {
+		return nil, fmt.Errorf("%w: malformed api key", exceptions.ErrUnauthorized)
+	}
+
+	k, err := v.db.GetAPIKey(ctx, auth.HashKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: unknown api key", exceptions.ErrUnauthorized)
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrAuthenticate, err)
+	}
+
+	return &auth.Principal{
+		Subject:   k.Name,
+		Method:    auth.MethodAPIKey,
+		Namespace: k.Namespace,
+		Grants:    k.Grants,
+	}, nil
+}
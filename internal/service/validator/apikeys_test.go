@@ -0,0 +1,200 @@
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestValidator_CreateAPIKey(t *testing.T) {
+	tc := []struct {
+		name      string
+		key       *auth.APIKey
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name: "created",
+			key: &auth.APIKey{Name: " ci ", Namespace: "team-a", Grants: auth.Grants{
+				{Role: auth.RolePublisher, Namespace: "team-a", Prefix: "orders-"},
+				{Role: auth.RoleReader, Namespace: auth.AnyNamespace},
+			}},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, key *auth.APIKey) error {
+						assert.Equal(t, "ci", key.Name)
+						assert.Equal(t, auth.HashKey(key.Key), key.Hash)
+
+						return nil
+					})
+			},
+		},
+		{
+			name: "no name",
+			key:  &auth.APIKey{Name: " "},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrInvalidAPIKey,
+		},
+		{
+			name: "invalid namespace",
+			key:  &auth.APIKey{Name: "ci", Namespace: "Team_A"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrInvalidAPIKey,
+		},
+		{
+			name: "unknown role",
+			key:  &auth.APIKey{Name: "ci", Grants: auth.Grants{{Role: "owner"}}},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrInvalidAPIKey,
+		},
+		{
+			name: "invalid grant namespace",
+			key:  &auth.APIKey{Name: "ci", Grants: auth.Grants{{Role: auth.RoleReader, Namespace: "Team_A"}}},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrInvalidAPIKey,
+		},
+		{
+			name: "generic error",
+			key:  &auth.APIKey{Name: "ci"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("error"))
+			},
+			err: exceptions.ErrCreateAPIKey,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			err := v.CreateAPIKey(context.TODO(), tt.key)
+			if tt.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(tt.key.Key, auth.KeyPrefix))
+			assert.True(t, strings.HasPrefix(tt.key.Key, tt.key.Prefix))
+			assert.Greater(t, len(tt.key.Key), len(tt.key.Prefix))
+		})
+	}
+}
+
+func TestValidator_AuthenticateAPIKey(t *testing.T) {
+	key := auth.KeyPrefix + "s3cr3t"
+
+	tc := []struct {
+		name      string
+		key       string
+		storeStub func(store *mock_storage.MockStorage)
+		principal *auth.Principal
+		err       error
+	}{
+		{
+			name: "known key",
+			key:  key,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetAPIKey(gomock.Any(), auth.HashKey(key)).
+					Times(1).
+					Return(&auth.APIKey{Name: "ci", Namespace: "team-a", Grants: auth.Grants{{Role: auth.RoleReader}}}, nil)
+			},
+			principal: &auth.Principal{
+				Subject:   "ci",
+				Method:    auth.MethodAPIKey,
+				Namespace: "team-a",
+				Grants:    auth.Grants{{Role: auth.RoleReader}},
+			},
+		},
+		{
+			name: "unknown key",
+			key:  key,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(nil, gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrUnauthorized,
+		},
+		{
+			name: "malformed key",
+			key:  "s3cr3t",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetAPIKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrUnauthorized,
+		},
+		{
+			name: "generic error",
+			key:  key,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetAPIKey(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("error"))
+			},
+			err: exceptions.ErrAuthenticate,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			principal, err := v.AuthenticateAPIKey(context.TODO(), tt.key)
+			if tt.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.principal, principal)
+		})
+	}
+}
+
+func TestValidator_DeleteAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().DeleteAPIKey(gomock.Any(), uint(1)).Times(1).Return(nil)
+	store.EXPECT().DeleteAPIKey(gomock.Any(), uint(2)).Times(1).Return(gorm.ErrRecordNotFound)
+
+	v := helperNewValidator(t, store)
+
+	require.NoError(t, v.DeleteAPIKey(context.TODO(), 1))
+	assert.ErrorIs(t, v.DeleteAPIKey(context.TODO(), 2), exceptions.ErrNotFound)
+}
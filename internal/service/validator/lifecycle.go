@@ -0,0 +1,136 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// transitions lists the states each state can move to, deprecating again changing the sunset or successor.
+var transitions = map[string][]string{
+	schema.StateDraft:      {schema.StateActive, schema.StateRetired},
+	schema.StateActive:     {schema.StateDeprecated, schema.StateRetired},
+	schema.StateDeprecated: {schema.StateActive, schema.StateDeprecated, schema.StateRetired},
+	schema.StateRetired:    {schema.StateDeprecated},
+}
+
+// UpdateLifecycle moves the schema to another lifecycle state.
+func (v *Validator) UpdateLifecycle(ctx context.Context, schemaID string, lifecycle schema.Lifecycle) (*schema.Schema, error) {
+	v.log.Debug(ctx, "Validator: updating schema lifecycle")
+
+	s, err := v.db.GetSchema(ctx, schemaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrUpdateLifecycle, err)
+	}
+
+	if !canTransition(s.State, lifecycle.State) {
+		return nil, fmt.Errorf("%w: cannot move from %q to %q", exceptions.ErrInvalidLifecycle, s.State, lifecycle.State)
+	}
+
+	if !lifecycle.Deprecated() {
+		if lifecycle.Sunset != nil || lifecycle.Successor != "" {
+			return nil, fmt.Errorf("%w: sunset and successor require a deprecated or retired schema", exceptions.ErrInvalidLifecycle)
+		}
+	} else if err = v.checkSuccessor(ctx, schemaID, lifecycle.Successor); err != nil {
+		return nil, err
+	}
+
+	lifecycle.DeprecatedAt = nil
+
+	if lifecycle.Deprecated() {
+		lifecycle.DeprecatedAt = s.DeprecatedAt
+
+		if lifecycle.DeprecatedAt == nil {
+			now := time.Now().UTC()
+			lifecycle.DeprecatedAt = &now
+		}
+	}
+
+	updated, err := v.db.UpdateLifecycle(ctx, schemaID, s.Lifecycle, lifecycle)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, exceptions.ErrNotFound
+		case errors.Is(err, exceptions.ErrPreconditionFailed):
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrUpdateLifecycle, err)
+	}
+
+	v.events.Notify()
+
+	return updated, nil
+}
+
+func (v *Validator) checkSuccessor(ctx context.Context, schemaID, successor string) error {
+	if successor == "" {
+		return nil
+	}
+
+	if successor == schemaID || strings.HasPrefix(successor, schemaID+tag.Separator) {
+		return fmt.Errorf("%w: a schema cannot succeed itself", exceptions.ErrInvalidLifecycle)
+	}
+
+	if _, err := v.resolve(ctx, successor); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, exceptions.ErrInvalidRef) {
+			return fmt.Errorf("%w: unknown successor %q", exceptions.ErrInvalidLifecycle, successor)
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrUpdateLifecycle, err)
+	}
+
+	return nil
+}
+
+func checkState(meta *schema.Metadata) error {
+	switch meta.State {
+	case "":
+		meta.State = schema.StateActive
+	case schema.StateDraft, schema.StateActive:
+	default:
+		return fmt.Errorf("%w: schemas are uploaded as %q or %q", exceptions.ErrInvalidMetadata, schema.StateDraft, schema.StateActive)
+	}
+
+	meta.DeprecatedAt, meta.Sunset, meta.Successor = nil, nil, ""
+
+	return nil
+}
+
+func retired(s *schema.Schema) error {
+	if s.State != schema.StateRetired {
+		return nil
+	}
+
+	if s.Successor != "" {
+		return fmt.Errorf("%w: use %q instead", exceptions.ErrRetired, s.Successor)
+	}
+
+	return exceptions.ErrRetired
+}
+
+func canTransition(from, to string) bool {
+	if from == "" {
+		from = schema.StateActive
+	}
+
+	for _, state := range transitions[from] {
+		if state == to {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,163 @@
+package validator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestValidator_UpdateLifecycle(t *testing.T) {
+	sunset := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tc := []struct {
+		name       string
+		state      string
+		lifecycle  schema.Lifecycle
+		storeStub  func(store *mock_storage.MockStorage, updated *schema.Lifecycle)
+		deprecated bool
+		err        error
+	}{
+		{
+			name:      "deprecate with sunset and successor",
+			state:     schema.StateActive,
+			lifecycle: schema.Lifecycle{State: schema.StateDeprecated, Sunset: &sunset, Successor: "orders-v2"},
+			storeStub: func(store *mock_storage.MockStorage, updated *schema.Lifecycle) {
+				store.EXPECT().GetSchema(gomock.Any(), "orders-v2").Times(1).Return(helperSchema(`{}`), nil)
+				store.EXPECT().UpdateLifecycle(gomock.Any(), "orders", gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, _ string, _, lifecycle schema.Lifecycle) (*schema.Schema, error) {
+						*updated = lifecycle
+
+						return helperSchema(`{}`), nil
+					})
+			},
+			deprecated: true,
+		},
+		{
+			name:      "activate a draft",
+			state:     schema.StateDraft,
+			lifecycle: schema.Lifecycle{State: schema.StateActive},
+			storeStub: func(store *mock_storage.MockStorage, updated *schema.Lifecycle) {
+				store.EXPECT().UpdateLifecycle(gomock.Any(), "orders", gomock.Any(), gomock.Any()).Times(1).Return(helperSchema(`{}`), nil)
+			},
+		},
+		{
+			name:      "draft cannot be deprecated",
+			state:     schema.StateDraft,
+			lifecycle: schema.Lifecycle{State: schema.StateDeprecated},
+			storeStub: func(store *mock_storage.MockStorage, updated *schema.Lifecycle) {},
+			err:       exceptions.ErrInvalidLifecycle,
+		},
+		{
+			name:      "retired cannot be activated",
+			state:     schema.StateRetired,
+			lifecycle: schema.Lifecycle{State: schema.StateActive},
+			storeStub: func(store *mock_storage.MockStorage, updated *schema.Lifecycle) {},
+			err:       exceptions.ErrInvalidLifecycle,
+		},
+		{
+			name:      "sunset of an active schema",
+			state:     schema.StateDeprecated,
+			lifecycle: schema.Lifecycle{State: schema.StateActive, Sunset: &sunset},
+			storeStub: func(store *mock_storage.MockStorage, updated *schema.Lifecycle) {},
+			err:       exceptions.ErrInvalidLifecycle,
+		},
+		{
+			name:      "succeeds itself",
+			state:     schema.StateActive,
+			lifecycle: schema.Lifecycle{State: schema.StateRetired, Successor: "orders@2"},
+			storeStub: func(store *mock_storage.MockStorage, updated *schema.Lifecycle) {},
+			err:       exceptions.ErrInvalidLifecycle,
+		},
+		{
+			name:      "unknown successor",
+			state:     schema.StateActive,
+			lifecycle: schema.Lifecycle{State: schema.StateDeprecated, Successor: "orders-v2"},
+			storeStub: func(store *mock_storage.MockStorage, updated *schema.Lifecycle) {
+				store.EXPECT().GetSchema(gomock.Any(), "orders-v2").Times(1).Return(nil, gorm.ErrRecordNotFound)
+				store.EXPECT().GetTag(gomock.Any(), "orders-v2", gomock.Any()).Times(1).Return(nil, gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrInvalidLifecycle,
+		},
+		{
+			name:      "concurrent transition",
+			state:     schema.StateActive,
+			lifecycle: schema.Lifecycle{State: schema.StateRetired},
+			storeStub: func(store *mock_storage.MockStorage, updated *schema.Lifecycle) {
+				store.EXPECT().UpdateLifecycle(gomock.Any(), "orders", gomock.Any(), gomock.Any()).Times(1).Return(nil, exceptions.ErrPreconditionFailed)
+			},
+			err: exceptions.ErrPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			current := helperSchema(`{}`)
+			current.State = tt.state
+
+			store := mock_storage.NewMockStorage(ctrl)
+			store.EXPECT().GetSchema(gomock.Any(), "orders").Times(1).Return(current, nil)
+
+			var updated schema.Lifecycle
+
+			tt.storeStub(store, &updated)
+
+			v := helperNewValidator(t, store)
+
+			_, err := v.UpdateLifecycle(context.TODO(), "orders", tt.lifecycle)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.deprecated, updated.DeprecatedAt != nil)
+		})
+	}
+}
+
+func TestValidator_ValidateRetiredSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := helperSchema(`{"type":"object"}`)
+	s.State, s.Successor = schema.StateRetired, "orders-v2"
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().GetSchema(gomock.Any(), "orders").Times(1).Return(s, nil)
+
+	v := helperNewValidator(t, store)
+
+	res, err := v.ValidateSchema(context.TODO(), "orders", map[string]interface{}{})
+	assert.ErrorIs(t, err, exceptions.ErrRetired)
+	assert.Contains(t, err.Error(), "orders-v2")
+	assert.Equal(t, s, res)
+}
+
+func TestValidator_UploadSchemaState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().CreateSchema(gomock.Any(), "orders", gomock.Any(), schema.Metadata{Lifecycle: schema.Lifecycle{State: schema.StateActive}}).
+		Times(1).Return(nil)
+
+	v := helperNewValidator(t, store)
+
+	require.NoError(t, v.UploadSchema(context.TODO(), "orders", `{}`, schema.Metadata{}))
+
+	err := v.UploadSchema(context.TODO(), "orders", `{}`, schema.Metadata{Lifecycle: schema.Lifecycle{State: schema.StateRetired}})
+	assert.ErrorIs(t, err, exceptions.ErrInvalidMetadata)
+}
@@ -0,0 +1,243 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func (v *Validator) GetQuarantineSettings(ctx context.Context, schemaID string) (*quarantine.Settings, error) {
+	v.log.Debug(ctx, "Validator: getting quarantine settings")
+
+	if _, err := v.db.GetSchema(ctx, schemaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrDownloadSchema, err)
+	}
+
+	settings, err := v.db.GetQuarantineSettings(ctx, schemaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &quarantine.Settings{SchemaID: schemaID, Redact: quarantine.Pointers{}}, nil
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrDownloadSchema, err)
+	}
+
+	return settings, nil
+}
+
+func (v *Validator) UpdateQuarantineSettings(ctx context.Context, settings *quarantine.Settings) error {
+	v.log.Debug(ctx, "Validator: updating quarantine settings")
+
+	for _, p := range settings.Redact {
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("%w:redact pointer %q must start with /", exceptions.ErrInvalidSettings, p)
+		}
+	}
+
+	if settings.Redact == nil {
+		settings.Redact = quarantine.Pointers{}
+	}
+
+	if _, err := v.db.GetSchema(ctx, settings.SchemaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.ErrNotFound
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrQuarantineSettings, err)
+	}
+
+	if err := v.db.PutQuarantineSettings(ctx, settings); err != nil {
+		return fmt.Errorf("%w:%v", exceptions.ErrQuarantineSettings, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) ListQuarantine(ctx context.Context, schemaID string, limit int) ([]*quarantine.Entry, error) {
+	v.log.Debug(ctx, "Validator: listing quarantine")
+
+	entries, err := v.db.ListQuarantine(ctx, schemaID, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListQuarantine, err)
+	}
+
+	return entries, nil
+}
+
+// ReplayQuarantine validates the quarantined payloads against the version, 0 being the current one. Replays are
+// neither recorded in the stats nor quarantined again.
+func (v *Validator) ReplayQuarantine(ctx context.Context, schemaID string, version int) (*quarantine.Replay, error) {
+	v.log.Debug(ctx, "Validator: replaying quarantine")
+
+	var (
+		s   *schema.Schema
+		err error
+	)
+
+	if version == 0 {
+		s, err = v.db.GetSchema(ctx, schemaID)
+	} else {
+		s, err = v.db.GetSchemaVersion(ctx, schemaID, version)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrReplayQuarantine, err)
+	}
+
+	compiled, err := compile(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrReplayQuarantine, err)
+	}
+
+	entries, err := v.db.ListQuarantine(ctx, schemaID, time.Now(), v.cfg.Quarantine.MaxEntries)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrReplayQuarantine, err)
+	}
+
+	replay := &quarantine.Replay{SchemaID: schemaID, Version: s.Revision, Results: make([]*quarantine.Result, 0, len(entries))}
+
+	for _, e := range entries {
+		res := &quarantine.Result{ID: e.ID, Valid: true, Redacted: e.Redacted}
+
+		if err = compiled.Validate(bytes.NewReader(e.Payload)); err != nil {
+			res.Valid = false
+			res.Error = formatValidationError(err).Error()
+			replay.Failed++
+		} else {
+			replay.Passed++
+		}
+
+		replay.Results = append(replay.Results, res)
+	}
+
+	return replay, nil
+}
+
+// quarantine stores the failing payload when the schema opted in. Failing to do so never fails the validation.
+func (v *Validator) quarantine(ctx context.Context, s *schema.Schema, payload []byte, validationErr error) {
+	settings, err := v.db.GetQuarantineSettings(ctx, s.SchemaID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			v.log.Error(ctx, fmt.Errorf("%w:%v", exceptions.ErrQuarantine, err), "could not get quarantine settings")
+		}
+
+		return
+	}
+
+	if !settings.Enabled {
+		return
+	}
+
+	if len(payload) > v.cfg.Quarantine.MaxPayloadBytes {
+		v.log.Debug(ctx, fmt.Sprintf("Validator: payload of %d bytes too large to quarantine", len(payload)))
+
+		return
+	}
+
+	redacted, replaced, err := redact(payload, settings.Redact)
+	if err != nil {
+		v.log.Error(ctx, fmt.Errorf("%w:%v", exceptions.ErrQuarantine, err), "could not redact payload")
+
+		return
+	}
+
+	entry := &quarantine.Entry{
+		SchemaID:  s.SchemaID,
+		Version:   s.Revision,
+		Payload:   redacted,
+		Error:     formatValidationError(validationErr).Error(),
+		Redacted:  replaced,
+		ExpiresAt: time.Now().Add(v.cfg.Quarantine.TTL),
+	}
+
+	if err = v.db.QuarantinePayload(ctx, entry, v.cfg.Quarantine.MaxEntries); err != nil {
+		v.log.Error(ctx, fmt.Errorf("%w:%v", exceptions.ErrQuarantine, err), "could not quarantine payload")
+	}
+}
+
+// redact replaces the values at the pointers, "*" matching any key or index, and tells whether any was.
+func redact(payload []byte, pointers []string) ([]byte, bool, error) {
+	if len(pointers) == 0 {
+		return payload, false, nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, false, err
+	}
+
+	replaced := false
+
+	for _, p := range pointers {
+		tokens := strings.Split(p, "/")[1:]
+		for i, t := range tokens {
+			tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+		}
+
+		if redactTokens(doc, tokens) {
+			replaced = true
+		}
+	}
+
+	redacted, err := json.Marshal(doc)
+
+	return redacted, replaced, err
+}
+
+func redactTokens(doc interface{}, tokens []string) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+
+	replaced := false
+
+	token, rest := tokens[0], tokens[1:]
+
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		for k, child := range node {
+			if token != "*" && token != k {
+				continue
+			}
+
+			if len(rest) == 0 {
+				node[k], replaced = quarantine.Redacted, true
+			} else if redactTokens(child, rest) {
+				replaced = true
+			}
+		}
+	case []interface{}:
+		for i, child := range node {
+			if token != "*" && token != strconv.Itoa(i) {
+				continue
+			}
+
+			if len(rest) == 0 {
+				node[i], replaced = quarantine.Redacted, true
+			} else if redactTokens(child, rest) {
+				replaced = true
+			}
+		}
+	}
+
+	return replaced
+}
@@ -0,0 +1,263 @@
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/service/events"
+	"github.com/KarolosLykos/json-validation-service/internal/service/stats"
+	"github.com/KarolosLykos/json-validation-service/internal/service/validator"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+const quarantinedSchema = `{"type":"object","required":["source"]}`
+
+func TestValidator_ValidateSchemaQuarantine(t *testing.T) {
+	tc := []struct {
+		name      string
+		payload   map[string]interface{}
+		maxBytes  int
+		storeStub func(store *mock_storage.MockStorage)
+	}{
+		{
+			name:    "quarantines redacted payload",
+			payload: map[string]interface{}{"user": map[string]interface{}{"password": "secret", "name": "john"}, "cards": []interface{}{"1234", "5678"}},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetQuarantineSettings(gomock.Any(), "config-schema").
+					Times(1).
+					Return(&quarantine.Settings{SchemaID: "config-schema", Enabled: true, Redact: quarantine.Pointers{"/user/password", "/cards/*", "/missing"}}, nil)
+				store.EXPECT().
+					QuarantinePayload(gomock.Any(), gomock.Any(), 1000).
+					Times(1).
+					DoAndReturn(func(_ context.Context, e *quarantine.Entry, _ int) error {
+						assert.Equal(t, "config-schema", e.SchemaID)
+						assert.Equal(t, 2, e.Version)
+						assert.JSONEq(t, `{"user":{"password":"[REDACTED]","name":"john"},"cards":["[REDACTED]","[REDACTED]"]}`, e.Payload.String())
+						assert.Contains(t, e.Error, exceptions.ErrValidation.Error())
+						assert.True(t, e.Redacted)
+						assert.False(t, e.ExpiresAt.IsZero())
+
+						return nil
+					})
+			},
+		},
+		{
+			name:    "disabled",
+			payload: map[string]interface{}{},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetQuarantineSettings(gomock.Any(), "config-schema").
+					Times(1).
+					Return(&quarantine.Settings{SchemaID: "config-schema"}, nil)
+			},
+		},
+		{
+			name:     "payload too large",
+			payload:  map[string]interface{}{"data": strings.Repeat("x", 100)},
+			maxBytes: 64,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetQuarantineSettings(gomock.Any(), "config-schema").
+					Times(1).
+					Return(&quarantine.Settings{SchemaID: "config-schema", Enabled: true}, nil)
+			},
+		},
+		{
+			name:    "store error does not fail the validation differently",
+			payload: map[string]interface{}{},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetQuarantineSettings(gomock.Any(), "config-schema").
+					Times(1).
+					Return(&quarantine.Settings{SchemaID: "config-schema", Enabled: true}, nil)
+				store.EXPECT().
+					QuarantinePayload(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := helperSchema(quarantinedSchema)
+			s.SchemaID = "config-schema"
+			s.Revision = 2
+
+			store := mock_storage.NewMockStorage(ctrl)
+			store.EXPECT().GetSchema(gomock.Any(), "config-schema").Times(1).Return(s, nil)
+			tt.storeStub(store)
+
+			cfg, _ := config.Load()
+			if tt.maxBytes > 0 {
+				cfg.Quarantine.MaxPayloadBytes = tt.maxBytes
+			}
+
+			log := logruslog.DefaultLogger(cfg)
+			v := validator.New(cfg, log, store, events.New(cfg, log, store), stats.New(cfg, log, store))
+
+			_, err := v.ValidateSchema(context.TODO(), "config-schema", tt.payload)
+			assert.ErrorContains(t, err, exceptions.ErrValidation.Error())
+		})
+	}
+}
+
+func TestValidator_UpdateQuarantineSettings(t *testing.T) {
+	tc := []struct {
+		name      string
+		settings  *quarantine.Settings
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name:     "success",
+			settings: &quarantine.Settings{SchemaID: "config-schema", Enabled: true},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), "config-schema").Times(1).Return(helperSchema(quarantinedSchema), nil)
+				store.EXPECT().
+					PutQuarantineSettings(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, s *quarantine.Settings) error {
+						assert.NotNil(t, s.Redact)
+
+						return nil
+					})
+			},
+		},
+		{
+			name:      "invalid pointer",
+			settings:  &quarantine.Settings{SchemaID: "config-schema", Redact: quarantine.Pointers{"user"}},
+			storeStub: func(store *mock_storage.MockStorage) {},
+			err:       exceptions.ErrInvalidSettings,
+		},
+		{
+			name:     "not found",
+			settings: &quarantine.Settings{SchemaID: "config-schema"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), "config-schema").Times(1).Return(nil, gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			err := v.UpdateQuarantineSettings(context.TODO(), tt.settings)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidator_GetQuarantineSettingsDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().GetSchema(gomock.Any(), "config-schema").Times(1).Return(helperSchema(quarantinedSchema), nil)
+	store.EXPECT().GetQuarantineSettings(gomock.Any(), "config-schema").Times(1).Return(nil, gorm.ErrRecordNotFound)
+
+	v := helperNewValidator(t, store)
+
+	settings, err := v.GetQuarantineSettings(context.TODO(), "config-schema")
+	require.NoError(t, err)
+	assert.False(t, settings.Enabled)
+	assert.Equal(t, "config-schema", settings.SchemaID)
+}
+
+func TestValidator_ReplayQuarantine(t *testing.T) {
+	entries := []*quarantine.Entry{
+		{ID: 2, Payload: datatypes.JSON(`{"source":"s3"}`)},
+		{ID: 1, Payload: datatypes.JSON(`{"token":"[REDACTED]"}`), Redacted: true},
+	}
+
+	tc := []struct {
+		name      string
+		version   int
+		storeStub func(store *mock_storage.MockStorage)
+		passed    int
+		err       error
+	}{
+		{
+			name: "current version",
+			storeStub: func(store *mock_storage.MockStorage) {
+				s := helperSchema(quarantinedSchema)
+				s.Revision = 3
+				store.EXPECT().GetSchema(gomock.Any(), "config-schema").Times(1).Return(s, nil)
+				store.EXPECT().ListQuarantine(gomock.Any(), "config-schema", gomock.Any(), 1000).Times(1).Return(entries, nil)
+			},
+			passed: 1,
+		},
+		{
+			name:    "given version",
+			version: 1,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchemaVersion(gomock.Any(), "config-schema", 1).Times(1).Return(helperSchema(`{"type":"object"}`), nil)
+				store.EXPECT().ListQuarantine(gomock.Any(), "config-schema", gomock.Any(), 1000).Times(1).Return(entries, nil)
+			},
+			passed: 2,
+		},
+		{
+			name:    "version not found",
+			version: 7,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchemaVersion(gomock.Any(), "config-schema", 7).Times(1).Return(nil, gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			replay, err := v.ReplayQuarantine(context.TODO(), "config-schema", tt.version)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.passed, replay.Passed)
+			assert.Equal(t, len(entries)-tt.passed, replay.Failed)
+			require.Len(t, replay.Results, len(entries))
+			assert.True(t, replay.Results[0].Valid)
+			assert.False(t, replay.Results[0].Redacted)
+			assert.True(t, replay.Results[1].Redacted)
+		})
+	}
+}
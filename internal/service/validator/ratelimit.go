@@ -0,0 +1,18 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/ratelimit"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func (v *Validator) TakeToken(ctx context.Context, key string, rate float64, burst int) (*ratelimit.Bucket, error) {
+	b, err := v.db.TakeToken(ctx, key, rate, burst)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrTakeToken, err)
+	}
+
+	return b, nil
+}
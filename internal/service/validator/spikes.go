@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"sync"
+	"time"
+)
+
+// spikes counts the failed validations of every schema over fixed windows, to tell when they exceed a threshold.
+type spikes struct {
+	threshold int
+	window    time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*spikeWindow
+	swept   time.Time
+}
+
+type spikeWindow struct {
+	start    time.Time
+	failures int
+}
+
+func newSpikes(threshold int, window time.Duration) *spikes {
+	return &spikes{
+		threshold: threshold,
+		window:    window,
+		windows:   make(map[string]*spikeWindow),
+	}
+}
+
+// fail records a failed validation at now, reporting whether its window just reached the threshold.
+func (s *spikes) fail(key string, now time.Time) (spikeWindow, bool) {
+	if s.threshold <= 0 {
+		return spikeWindow{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the windows over are swept once per window, forgetting the schemas which stopped failing.
+	if now.Sub(s.swept) >= s.window {
+		for k, w := range s.windows {
+			if now.Sub(w.start) >= s.window {
+				delete(s.windows, k)
+			}
+		}
+
+		s.swept = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok || now.Sub(w.start) >= s.window {
+		w = &spikeWindow{start: now}
+		s.windows[key] = w
+	}
+
+	w.failures++
+
+	return *w, w.failures == s.threshold
+}
@@ -0,0 +1,32 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpikes_Fail(t *testing.T) {
+	s := newSpikes(2, time.Minute)
+	start := time.Now()
+
+	_, spiked := s.fail("default/a", start)
+	assert.False(t, spiked)
+
+	w, spiked := s.fail("default/a", start.Add(time.Second))
+	assert.True(t, spiked)
+	assert.Equal(t, spikeWindow{start: start, failures: 2}, w)
+
+	_, spiked = s.fail("default/a", start.Add(2*time.Second))
+	assert.False(t, spiked, "the spike is notified once per window")
+
+	_, _ = s.fail("default/b", start.Add(30*time.Second))
+	assert.Len(t, s.windows, 2)
+
+	// the window of a, over, is swept along with the next failure, that of b is kept.
+	_, _ = s.fail("default/c", start.Add(time.Minute))
+	assert.Len(t, s.windows, 2)
+	assert.NotContains(t, s.windows, "default/a")
+	assert.Contains(t, s.windows, "default/b")
+}
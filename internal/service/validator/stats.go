@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+var statsWindows = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+const topFailingPaths = 10
+
+func (v *Validator) SchemaStats(ctx context.Context, schemaID string) (*validation.Stats, error) {
+	v.log.Debug(ctx, "Validator: getting schema stats")
+
+	if _, err := v.db.GetSchema(ctx, schemaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrSchemaStats, err)
+	}
+
+	res, err := v.db.ValidationStats(ctx, schemaID, time.Now(), statsWindows, topFailingPaths)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrSchemaStats, err)
+	}
+
+	res.SampleRate = v.cfg.Stats.SampleRate
+
+	return res, nil
+}
+
+func (v *Validator) record(s *schema.Schema, start time.Time, err error) {
+	if !v.stats.Sample() {
+		return
+	}
+
+	res := &validation.Result{
+		// the results are stored in the background, outside of the namespace of the request.
+		Namespace: s.Namespace,
+		SchemaID:  s.SchemaID,
+		Version:   s.Revision,
+		Valid:     err == nil,
+		Latency:   time.Since(start).Microseconds(),
+	}
+
+	if ve, ok := err.(*jsonschema.ValidationError); ok {
+		leaf := firstLeaf(ve)
+
+		res.Keyword = keyword(leaf.SchemaPtr)
+		res.InstancePath = leaf.InstancePtr
+	}
+
+	v.stats.Record(res)
+}
+
+// firstLeaf returns the deepest first cause, the one pointing at the failing keyword.
+func firstLeaf(ve *jsonschema.ValidationError) *jsonschema.ValidationError {
+	for len(ve.Causes) > 0 {
+		ve = ve.Causes[0]
+	}
+
+	return ve
+}
+
+// keyword returns the last token of a schema pointer, e.g. "required" for "#/properties/chunks/required".
+func keyword(schemaPtr string) string {
+	i := strings.LastIndex(schemaPtr, "/")
+
+	token := schemaPtr[i+1:]
+	token = strings.ReplaceAll(token, "~1", "/")
+
+	return strings.ReplaceAll(token, "~0", "~")
+}
@@ -0,0 +1,140 @@
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/service/events"
+	"github.com/KarolosLykos/json-validation-service/internal/service/stats"
+	"github.com/KarolosLykos/json-validation-service/internal/service/validator"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestValidator_SchemaStats(t *testing.T) {
+	tc := []struct {
+		name      string
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name: "success",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(helperSchema(`{}`), nil)
+				store.EXPECT().
+					ValidationStats(gomock.Any(), "config-schema", gomock.Any(), gomock.Len(3), 10).
+					Times(1).
+					Return(&validation.Stats{SchemaID: "config-schema"}, nil)
+			},
+			err: nil,
+		},
+		{
+			name: "not found",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+		{
+			name: "generic error",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(helperSchema(`{}`), nil)
+				store.EXPECT().
+					ValidationStats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("error"))
+			},
+			err: exceptions.ErrSchemaStats,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			res, err := v.SchemaStats(ctx, "config-schema")
+			if tt.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, float64(1), res.SampleRate)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateSchemaRecordsResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := helperSchema(`{"type":"object","properties":{"chunks":{"type":"object","required":["size"]}}}`)
+	s.SchemaID = "config-schema"
+	s.Revision = 3
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		GetSchema(gomock.Any(), "config-schema").
+		Times(2).
+		Return(s, nil)
+	store.EXPECT().
+		GetQuarantineSettings(gomock.Any(), "config-schema").
+		Times(1).
+		Return(nil, gorm.ErrRecordNotFound)
+	store.EXPECT().
+		RecordValidations(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, results []*validation.Result) error {
+			require.Len(t, results, 2)
+
+			assert.True(t, results[0].Valid)
+			assert.Equal(t, 3, results[0].Version)
+
+			assert.False(t, results[1].Valid)
+			assert.Equal(t, "required", results[1].Keyword)
+			assert.Equal(t, "#/chunks", results[1].InstancePath)
+
+			return nil
+		})
+
+	cfg, _ := config.Load()
+	log := logruslog.DefaultLogger(cfg)
+	recorder := stats.New(cfg, log, store)
+	v := validator.New(cfg, log, store, events.New(cfg, log, store), recorder)
+
+	_, err := v.ValidateSchema(context.TODO(), "config-schema", map[string]interface{}{})
+	require.NoError(t, err)
+
+	_, err = v.ValidateSchema(context.TODO(), "config-schema", map[string]interface{}{
+		"chunks": map[string]interface{}{},
+	})
+	require.Error(t, err)
+
+	recorder.Start(context.Background())
+	recorder.Shutdown(context.Background())
+}
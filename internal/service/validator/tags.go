@@ -0,0 +1,146 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// tagPattern matches the tag names, which cannot be numbers as those reference versions.
+var tagPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func (v *Validator) ListTags(ctx context.Context, schemaID string) ([]*tag.Tag, error) {
+	v.log.Debug(ctx, "Validator: listing tags")
+
+	tags, err := v.db.ListTags(ctx, schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListTags, err)
+	}
+
+	return tags, nil
+}
+
+func (v *Validator) PutTag(ctx context.Context, t *tag.Tag) error {
+	v.log.Debug(ctx, "Validator: setting tag")
+
+	if t.Target == "" {
+		t.Target = t.SchemaID
+	}
+
+	if strings.Contains(t.SchemaID, tag.Separator) || strings.Contains(t.Target, tag.Separator) {
+		return fmt.Errorf("%w: schema names cannot contain %q", exceptions.ErrInvalidTag, tag.Separator)
+	}
+
+	if _, err := strconv.Atoi(t.Tag); err == nil || !tagPattern.MatchString(t.Tag) {
+		return fmt.Errorf("%w: %q", exceptions.ErrInvalidTag, t.Tag)
+	}
+
+	if t.Version < 0 {
+		return fmt.Errorf("%w: version %d", exceptions.ErrInvalidTag, t.Version)
+	}
+
+	s, err := v.db.GetSchema(ctx, t.Target)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.ErrNotFound
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrPutTag, err)
+	}
+
+	if t.Version > s.Revision {
+		return fmt.Errorf("%w: %s has no version %d", exceptions.ErrInvalidTag, t.Target, t.Version)
+	}
+
+	if err = v.db.PutTag(ctx, t); err != nil {
+		return fmt.Errorf("%w:%v", exceptions.ErrPutTag, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) DeleteTag(ctx context.Context, schemaID, name string) error {
+	v.log.Debug(ctx, "Validator: deleting tag")
+
+	if err := v.db.DeleteTag(ctx, schemaID, name); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.ErrNotFound
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrDeleteTag, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) TagHistory(ctx context.Context, schemaID, name string, limit int) ([]*tag.Move, error) {
+	v.log.Debug(ctx, "Validator: getting tag history")
+
+	moves, err := v.db.TagHistory(ctx, schemaID, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListTags, err)
+	}
+
+	return moves, nil
+}
+
+// resolve returns the schema referenced by ref, either a schema ID, "<schemaID>@<version>" or "<schemaID>@<tag>".
+func (v *Validator) resolve(ctx context.Context, ref string) (*schema.Schema, error) {
+	i := strings.Index(ref, tag.Separator)
+	if i < 0 {
+		s, err := v.db.GetSchema(ctx, ref)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if t, tagErr := v.db.GetTag(ctx, ref, tag.Latest); tagErr == nil {
+				return v.target(ctx, t)
+			}
+		}
+
+		return s, err
+	}
+
+	schemaID, qualifier := ref[:i], ref[i+1:]
+	if schemaID == "" || qualifier == "" {
+		return nil, fmt.Errorf("%w: %q", exceptions.ErrInvalidRef, ref)
+	}
+
+	if version, err := strconv.Atoi(qualifier); err == nil {
+		if version <= 0 {
+			return nil, fmt.Errorf("%w: %q", exceptions.ErrInvalidRef, ref)
+		}
+
+		return v.version(ctx, schemaID, version)
+	}
+
+	t, err := v.db.GetTag(ctx, schemaID, qualifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.target(ctx, t)
+}
+
+func (v *Validator) target(ctx context.Context, t *tag.Tag) (*schema.Schema, error) {
+	if t.Version == 0 {
+		return v.db.GetSchema(ctx, t.Target)
+	}
+
+	return v.version(ctx, t.Target, t.Version)
+}
+
+func (v *Validator) version(ctx context.Context, schemaID string, version int) (*schema.Schema, error) {
+	s, err := v.db.GetSchema(ctx, schemaID)
+	if err != nil || s.Revision == version {
+		return s, err
+	}
+
+	return v.db.GetSchemaVersion(ctx, schemaID, version)
+}
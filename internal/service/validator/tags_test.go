@@ -0,0 +1,108 @@
+package validator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestValidator_PutTag(t *testing.T) {
+	tc := []struct {
+		name      string
+		tag       *tag.Tag
+		storeStub func(store *mock_storage.MockStorage)
+		target    string
+		err       error
+	}{
+		{
+			name: "defaults the target to the schema",
+			tag:  &tag.Tag{SchemaID: "orders", Tag: "stable", Version: 2},
+			storeStub: func(store *mock_storage.MockStorage) {
+				s := helperSchema(`{"type":"object"}`)
+				s.Revision = 3
+				store.EXPECT().GetSchema(gomock.Any(), "orders").Times(1).Return(s, nil)
+				store.EXPECT().PutTag(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			target: "orders",
+		},
+		{
+			name: "other schema",
+			tag:  &tag.Tag{SchemaID: "orders", Tag: tag.Latest, Target: "purchase-orders"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), "purchase-orders").Times(1).Return(helperSchema(`{"type":"object"}`), nil)
+				store.EXPECT().PutTag(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			target: "purchase-orders",
+		},
+		{
+			name:      "numeric tag",
+			tag:       &tag.Tag{SchemaID: "orders", Tag: "2"},
+			storeStub: func(store *mock_storage.MockStorage) {},
+			err:       exceptions.ErrInvalidTag,
+		},
+		{
+			name:      "invalid tag",
+			tag:       &tag.Tag{SchemaID: "orders", Tag: "-stable"},
+			storeStub: func(store *mock_storage.MockStorage) {},
+			err:       exceptions.ErrInvalidTag,
+		},
+		{
+			name: "unknown version",
+			tag:  &tag.Tag{SchemaID: "orders", Tag: "canary", Version: 4},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), "orders").Times(1).Return(helperSchema(`{"type":"object"}`), nil)
+			},
+			err: exceptions.ErrInvalidTag,
+		},
+		{
+			name: "unknown target",
+			tag:  &tag.Tag{SchemaID: "orders", Tag: "canary"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), "orders").Times(1).Return(nil, gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			err := v.PutTag(context.TODO(), tt.tag)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.target, tt.tag.Target)
+		})
+	}
+}
+
+func TestValidator_DeleteTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().DeleteTag(gomock.Any(), "orders", "canary").Times(1).Return(gorm.ErrRecordNotFound)
+
+	v := helperNewValidator(t, store)
+
+	assert.ErrorIs(t, v.DeleteTag(context.TODO(), "orders", "canary"), exceptions.ErrNotFound)
+}
@@ -7,53 +7,112 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
+	"time"
 
-	"github.com/lib/pq"
+	"github.com/jackc/pgconn"
 	"github.com/santhosh-tekuri/jsonschema"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
+	"github.com/KarolosLykos/json-validation-service/internal/config"
 	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/metrics"
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
 	"github.com/KarolosLykos/json-validation-service/internal/service"
+	"github.com/KarolosLykos/json-validation-service/internal/service/events"
+	"github.com/KarolosLykos/json-validation-service/internal/service/stats"
 	"github.com/KarolosLykos/json-validation-service/internal/storage"
 	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jcs"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsonguard"
 )
 
+var digestPattern = regexp.MustCompile("^[0-9a-f]{64}$")
+
+const uniqueViolation = "23505"
+
 type Validator struct {
-	log logger.Logger
-	db  storage.Storage
+	cfg    *config.Config
+	log    logger.Logger
+	db     storage.Storage
+	events *events.Bus
+	stats  *stats.Recorder
+	spikes *spikes
 }
 
-func New(log logger.Logger, db storage.Storage) service.Service {
+func New(cfg *config.Config, log logger.Logger, db storage.Storage, bus *events.Bus, recorder *stats.Recorder) service.Service {
 	return &Validator{
-		log: log,
-		db:  db,
+		cfg:    cfg,
+		log:    log,
+		db:     db,
+		events: bus,
+		stats:  recorder,
+		spikes: newSpikes(cfg.Webhook.FailureSpikeThreshold, cfg.Webhook.FailureSpikeWindow),
 	}
 }
 
-func (v *Validator) UploadSchema(ctx context.Context, schemaID, schema string) error {
+func (v *Validator) UploadSchema(ctx context.Context, schemaID, payload string, meta schema.Metadata) error {
 	v.log.Debug(ctx, "Validator: uploading schema")
 
-	var empty struct{}
-	if err := json.Unmarshal([]byte(schema), &empty); err != nil {
-		return exceptions.ErrInvalidJSON
+	if strings.Contains(schemaID, tag.Separator) {
+		return fmt.Errorf("%w: schema names cannot contain %q", exceptions.ErrInvalidRef, tag.Separator)
+	}
+
+	if err := checkState(&meta); err != nil {
+		return err
+	}
+
+	canonical, err := v.canonicalize(payload)
+	if err != nil {
+		return err
 	}
 
-	if err := v.db.CreateSchema(ctx, schemaID, schema); err != nil {
-		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
-			return fmt.Errorf("%w:%v", exceptions.ErrAlreadyExists, err)
+	if err = v.db.CreateSchema(ctx, schemaID, canonical, meta); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: %q", exceptions.ErrAlreadyExists, schemaID)
+		}
+
+		if errors.Is(err, exceptions.ErrQuotaExceeded) {
+			return err
 		}
 
 		return fmt.Errorf("%w:%v", exceptions.ErrCreateSchema, err)
 	}
 
+	v.events.Notify()
+
 	return nil
 }
 
-func (v *Validator) DownloadSchema(ctx context.Context, schemaID string) (string, error) {
+// DownloadSchema returns the schema referenced by ref, see resolve.
+func (v *Validator) DownloadSchema(ctx context.Context, ref string) (*schema.Schema, error) {
 	v.log.Debug(ctx, "Validator: downloading schema")
 
-	s, err := v.db.GetSchema(ctx, schemaID)
+	s, err := v.resolve(ctx, ref)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrDownloadSchema, err)
+	}
+
+	return s, nil
+}
+
+func (v *Validator) DownloadSchemaByDigest(ctx context.Context, digest string) (string, error) {
+	v.log.Debug(ctx, "Validator: downloading schema by digest")
+
+	if !digestPattern.MatchString(digest) {
+		return "", exceptions.ErrInvalidDigest
+	}
+
+	s, err := v.db.GetBlob(ctx, digest)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return "", exceptions.ErrNotFound
@@ -65,34 +124,246 @@ func (v *Validator) DownloadSchema(ctx context.Context, schemaID string) (string
 	return s, nil
 }
 
-func (v *Validator) ValidateSchema(ctx context.Context, schemaID string, payload map[string]interface{}) error {
+func (v *Validator) UpdateSchema(ctx context.Context, schemaID, payload, digest string) (*schema.Schema, error) {
+	v.log.Debug(ctx, "Validator: updating schema")
+
+	canonical, err := v.canonicalize(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := v.db.UpdateSchema(ctx, schemaID, canonical, digest)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, exceptions.ErrNotFound
+		case errors.Is(err, exceptions.ErrPreconditionFailed):
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrUpdateSchema, err)
+	}
+
+	v.events.Notify()
+
+	return s, nil
+}
+
+func (v *Validator) DeleteSchema(ctx context.Context, schemaID, digest string) error {
+	v.log.Debug(ctx, "Validator: deleting schema")
+
+	if err := v.db.DeleteSchema(ctx, schemaID, digest); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return exceptions.ErrNotFound
+		case errors.Is(err, exceptions.ErrPreconditionFailed):
+			return err
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrDeleteSchema, err)
+	}
+
+	v.events.Notify()
+
+	return nil
+}
+
+func (v *Validator) ListSchemas(ctx context.Context, filter schema.Filter) ([]*schema.Schema, error) {
+	v.log.Debug(ctx, "Validator: listing schemas")
+
+	schemas, err := v.db.ListSchemas(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListSchemas, err)
+	}
+
+	return schemas, nil
+}
+
+// ImportSchemas canonicalizes and imports all the schemas, or none of them.
+func (v *Validator) ImportSchemas(ctx context.Context, schemas []*schema.Schema, mode schema.ImportMode) (*schema.ImportResult, error) {
+	v.log.Debug(ctx, "Validator: importing schemas")
+
+	switch mode {
+	case schema.ImportSkipExisting, schema.ImportOverwrite, schema.ImportFailOnConflict:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", exceptions.ErrInvalidQuery, mode)
+	}
+
+	seen := make(map[string]bool, len(schemas))
+
+	for _, s := range schemas {
+		if s.SchemaID == "" || strings.ContainsAny(s.SchemaID, "/"+tag.Separator) || seen[s.SchemaID] || s.Schema == nil {
+			return nil, fmt.Errorf("%w: invalid or duplicate schema %q", exceptions.ErrInvalidBundle, s.SchemaID)
+		}
+
+		seen[s.SchemaID] = true
+
+		canonical, err := v.canonicalize(s.Schema.String())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", exceptions.ErrInvalidBundle, s.SchemaID, err)
+		}
+
+		body := datatypes.JSON(canonical)
+		s.Schema = &body
+	}
+
+	res, err := v.db.ImportSchemas(ctx, schemas, mode)
+	if err != nil {
+		if errors.Is(err, exceptions.ErrAlreadyExists) || errors.Is(err, exceptions.ErrQuotaExceeded) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrImportSchemas, err)
+	}
+
+	v.events.Notify()
+
+	return res, nil
+}
+
+func (v *Validator) ExportSchemas(ctx context.Context, filter schema.Filter, fn func(*schema.Schema) error) error {
+	v.log.Debug(ctx, "Validator: exporting schemas")
+
+	if err := v.db.ExportSchemas(ctx, filter, fn); err != nil {
+		return fmt.Errorf("%w:%v", exceptions.ErrExportSchemas, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) ListTrash(ctx context.Context) ([]*schema.Schema, error) {
+	v.log.Debug(ctx, "Validator: listing trash")
+
+	schemas, err := v.db.ListTrash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListTrash, err)
+	}
+
+	return schemas, nil
+}
+
+func (v *Validator) RestoreSchema(ctx context.Context, schemaID string) error {
+	v.log.Debug(ctx, "Validator: restoring schema")
+
+	if err := v.db.RestoreSchema(ctx, schemaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.ErrNotFound
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrRestoreSchema, err)
+	}
+
+	v.events.Notify()
+
+	return nil
+}
+
+func (v *Validator) ListAudit(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	v.log.Debug(ctx, "Validator: listing audit log")
+
+	entries, err := v.db.ListAudit(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListAudit, err)
+	}
+
+	return entries, nil
+}
+
+// SubscribeEvents streams the schema events following lastEventID, or only the new ones when it is 0.
+func (v *Validator) SubscribeEvents(ctx context.Context, lastEventID uint64) <-chan *event.Event {
+	v.log.Debug(ctx, "Validator: subscribing to events")
+
+	return v.events.Subscribe(ctx, lastEventID)
+}
+
+// ValidateSchema validates the payload against the schema referenced by ref, see resolve.
+// The resolved schema is returned along with the outcome, so that callers can surface its lifecycle.
+func (v *Validator) ValidateSchema(ctx context.Context, ref string, payload map[string]interface{}) (*schema.Schema, error) {
 	v.log.Debug(ctx, "Validator: validating schema")
 
+	start := time.Now()
+
 	removeNulls(payload)
 
 	payloadB, _ := json.Marshal(payload)
 
-	s, err := v.db.GetSchema(ctx, schemaID)
+	s, err := v.resolve(ctx, ref)
 	if err != nil {
-		return err
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, exceptions.ErrNotFound
+		case errors.Is(err, exceptions.ErrNotFound), errors.Is(err, exceptions.ErrInvalidRef):
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrValidateSchema, err)
+	}
+
+	if err = retired(s); err != nil {
+		return s, err
+	}
+
+	compiled, err := compile(s)
+	if err != nil {
+		return s, err
 	}
 
+	err = compiled.Validate(bytes.NewReader(payloadB))
+
+	v.record(s, start, err)
+	count(s, err)
+
+	if err != nil {
+		v.failed(ctx, s.SchemaID)
+		v.quarantine(ctx, s, payloadB, err)
+
+		return s, formatValidationError(err)
+	}
+
+	return s, nil
+}
+
+// compile compiles the json-schema document of the schema.
+func compile(s *schema.Schema) (*jsonschema.Schema, error) {
+	defer func(start time.Time) {
+		metrics.CompileDuration.Observe(time.Since(start).Seconds())
+	}(time.Now())
+
 	compiler := jsonschema.NewCompiler()
 
-	if err = compiler.AddResource(schemaID, strings.NewReader(s)); err != nil {
-		return fmt.Errorf("%w:%v", exceptions.ErrValidateSchema, err)
+	if err := compiler.AddResource(s.SchemaID, strings.NewReader(s.Schema.String())); err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrValidateSchema, err)
 	}
 
-	schema, err := compiler.Compile(schemaID)
+	return compiler.Compile(s.SchemaID)
+}
+
+func count(s *schema.Schema, err error) {
+	result := metrics.ResultPass
 	if err != nil {
-		return err
+		result = metrics.ResultFail
 	}
 
-	if err = schema.Validate(bytes.NewReader(payloadB)); err != nil {
-		return formatValidationError(err)
+	metrics.Validations.WithLabelValues(s.Namespace, s.SchemaID, result).Inc()
+}
+
+// canonicalize returns the canonical form (RFC 8785) of the schema, so that identical ones share the digest.
+func (v *Validator) canonicalize(payload string) (string, error) {
+	if err := jsonguard.Check([]byte(payload), jsonguard.FromConfig(v.cfg)); err != nil {
+		return "", fmt.Errorf("%w: %v", exceptions.ErrJSONTooComplex, err)
 	}
 
-	return nil
+	var empty struct{}
+	if err := json.Unmarshal([]byte(payload), &empty); err != nil {
+		return "", exceptions.ErrInvalidJSON
+	}
+
+	canonical, err := jcs.Canonicalize([]byte(payload))
+	if err != nil {
+		return "", fmt.Errorf("%w:%v", exceptions.ErrInvalidJSON, err)
+	}
+
+	return string(canonical), nil
 }
 
 // removeNulls https://gist.github.com/ribice/074ad38d9f2fc5c88b20663659988d19.
@@ -113,6 +384,12 @@ func removeNulls(m map[string]interface{}) {
 	}
 }
 
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
 func formatValidationError(err error) error {
 	if ve, ok := err.(*jsonschema.ValidationError); ok {
 		msg := ve.Message
@@ -120,7 +397,7 @@ func formatValidationError(err error) error {
 			msg += c.Message + ","
 		}
 
-		return fmt.Errorf("%v:%v", exceptions.ErrValidation, msg)
+		return fmt.Errorf("%w:%v", exceptions.ErrValidation, msg)
 	}
 
 	return err
@@ -3,17 +3,26 @@ package validator_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
-	"github.com/lib/pq"
+	"github.com/jackc/pgconn"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
 	"github.com/KarolosLykos/json-validation-service/internal/config"
 	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/metrics"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
 	"github.com/KarolosLykos/json-validation-service/internal/service"
+	"github.com/KarolosLykos/json-validation-service/internal/service/events"
+	"github.com/KarolosLykos/json-validation-service/internal/service/stats"
 	"github.com/KarolosLykos/json-validation-service/internal/service/validator"
 	"github.com/KarolosLykos/json-validation-service/internal/storage"
 	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
@@ -34,7 +43,19 @@ func TestValidator_UploadSchema(t *testing.T) {
 			schema:   `{ "valid": "json" }`,
 			storeStub: func(store *mock_storage.MockStorage) {
 				store.EXPECT().
-					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:     "stores the canonical form",
+			schemaID: "config-schema",
+			schema:   `{ "type": "object", "$schema": "http://json-schema.org/draft-04/schema#", "maximum": 1.50 }`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					CreateSchema(gomock.Any(), "config-schema", `{"$schema":"http://json-schema.org/draft-04/schema#","maximum":1.5,"type":"object"}`, gomock.Any()).
 					Times(1).
 					Return(nil)
 			},
@@ -46,21 +67,56 @@ func TestValidator_UploadSchema(t *testing.T) {
 			schema:   `{ "invalid"  }`,
 			storeStub: func(store *mock_storage.MockStorage) {
 				store.EXPECT().
-					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0).
+					Return(nil)
+			},
+			err: exceptions.ErrInvalidJSON,
+		},
+		{
+			name:     "duplicate key",
+			schemaID: "config-schema",
+			schema:   `{ "type": "object", "type": "array" }`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(0).
 					Return(nil)
 			},
 			err: exceptions.ErrInvalidJSON,
 		},
+		{
+			name:     "array too long",
+			schemaID: "config-schema",
+			schema:   `{"enum":[` + strings.Repeat(`{"a":1},`, 10000) + `{"b":1}]}`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			err: exceptions.ErrJSONTooComplex,
+		},
+		{
+			name:     "namespace quota exceeded",
+			schemaID: "config-schema",
+			schema:   `{ "valid": "json" }`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(fmt.Errorf("%w: namespace default holds 10 schemas", exceptions.ErrQuotaExceeded))
+			},
+			err: exceptions.ErrQuotaExceeded,
+		},
 		{
 			name:     "already exists",
 			schemaID: "config-schema",
 			schema:   `{ "valid": "json" }`,
 			storeStub: func(store *mock_storage.MockStorage) {
 				store.EXPECT().
-					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(&pq.Error{Code: "23505"})
+					Return(fmt.Errorf("create: %w", &pgconn.PgError{Code: "23505"}))
 			},
 			err: exceptions.ErrAlreadyExists,
 		},
@@ -70,7 +126,7 @@ func TestValidator_UploadSchema(t *testing.T) {
 			schema:   `{ "valid": "json" }`,
 			storeStub: func(store *mock_storage.MockStorage) {
 				store.EXPECT().
-					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					CreateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(errors.New("error"))
 			},
@@ -89,7 +145,7 @@ func TestValidator_UploadSchema(t *testing.T) {
 
 			v := helperNewValidator(t, store)
 
-			err := v.UploadSchema(ctx, tt.schemaID, tt.schema)
+			err := v.UploadSchema(ctx, tt.schemaID, tt.schema, schema.Metadata{})
 			if tt.err != nil {
 				require.Error(t, err)
 				assert.ErrorContains(t, err, tt.err.Error())
@@ -114,7 +170,7 @@ func TestValidator_DownloadSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(`{ "valid": "json" }`, nil)
+					Return(helperSchema(`{ "valid": "json" }`), nil)
 			},
 			err: nil,
 		},
@@ -125,10 +181,79 @@ func TestValidator_DownloadSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return("", gorm.ErrRecordNotFound)
+					Return(nil, gorm.ErrRecordNotFound)
+				store.EXPECT().
+					GetTag(gomock.Any(), "config-schema", tag.Latest).
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+		{
+			name:     "renamed schema resolved by its latest tag",
+			schemaID: "config-schema",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
+				store.EXPECT().
+					GetTag(gomock.Any(), "config-schema", tag.Latest).
+					Times(1).
+					Return(&tag.Tag{SchemaID: "config-schema", Tag: tag.Latest, Target: "service-config"}, nil)
+				store.EXPECT().
+					GetSchema(gomock.Any(), "service-config").
+					Times(1).
+					Return(helperSchema(`{ "valid": "json" }`), nil)
+			},
+		},
+		{
+			name:     "tag",
+			schemaID: "config-schema@stable",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetTag(gomock.Any(), "config-schema", "stable").
+					Times(1).
+					Return(&tag.Tag{SchemaID: "config-schema", Tag: "stable", Target: "config-schema", Version: 1}, nil)
+				store.EXPECT().
+					GetSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(helperSchema(`{ "valid": "json" }`), nil)
+			},
+		},
+		{
+			name:     "previous version",
+			schemaID: "config-schema@1",
+			storeStub: func(store *mock_storage.MockStorage) {
+				s := helperSchema(`{ "valid": "json" }`)
+				s.Revision = 2
+				store.EXPECT().
+					GetSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(s, nil)
+				store.EXPECT().
+					GetSchemaVersion(gomock.Any(), "config-schema", 1).
+					Times(1).
+					Return(helperSchema(`{ "valid": "json" }`), nil)
+			},
+		},
+		{
+			name:     "unknown tag",
+			schemaID: "config-schema@canary",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetTag(gomock.Any(), "config-schema", "canary").
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
 			},
 			err: exceptions.ErrNotFound,
 		},
+		{
+			name:      "invalid reference",
+			schemaID:  "config-schema@",
+			storeStub: func(store *mock_storage.MockStorage) {},
+			err:       exceptions.ErrInvalidRef,
+		},
 		{
 			name:     "generic error",
 			schemaID: "config-schema",
@@ -136,7 +261,7 @@ func TestValidator_DownloadSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return("", errors.New("error"))
+					Return(nil, errors.New("error"))
 			},
 			err: exceptions.ErrDownloadSchema,
 		},
@@ -181,7 +306,7 @@ func TestValidator_ValidateSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(`{ "valid": "json" }`, nil)
+					Return(helperSchema(`{ "valid": "json" }`), nil)
 			},
 			err: nil,
 		},
@@ -193,7 +318,17 @@ func TestValidator_ValidateSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return("", exceptions.ErrNotFound)
+					Return(nil, exceptions.ErrNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+		{
+			name:     "record not found",
+			schemaID: "config-schema",
+			payload:  map[string]interface{}{"valid": "json"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), gomock.Any()).Times(1).Return(nil, gorm.ErrRecordNotFound)
+				store.EXPECT().GetTag(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, gorm.ErrRecordNotFound)
 			},
 			err: exceptions.ErrNotFound,
 		},
@@ -205,7 +340,7 @@ func TestValidator_ValidateSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return("-", nil)
+					Return(helperSchema("-"), nil)
 			},
 			err: exceptions.ErrValidateSchema,
 		},
@@ -217,7 +352,7 @@ func TestValidator_ValidateSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(`{
+					Return(helperSchema(`{
 					  "$schema":"http://json-schema.org/draft-04/schema#",
 					  "type": "object",
 					  "properties": {
@@ -229,7 +364,11 @@ func TestValidator_ValidateSchema(t *testing.T) {
 						}
 					  },
 					  "required": ["source", "destination"]
-					}`, nil)
+					}`), nil)
+				store.EXPECT().
+					GetQuarantineSettings(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
 			},
 			err: exceptions.ErrValidation,
 		},
@@ -241,7 +380,7 @@ func TestValidator_ValidateSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(`{
+					Return(helperSchema(`{
 					  "$schema":"http://json-schema.org/draft-04/schema#",
 					  "type": "object",
 					  "properties": {
@@ -253,7 +392,11 @@ func TestValidator_ValidateSchema(t *testing.T) {
 						}
 					  },
 					  "required": ["source", "destination"]
-					}`, nil)
+					}`), nil)
+				store.EXPECT().
+					GetQuarantineSettings(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
 			},
 			err: exceptions.ErrValidation,
 		},
@@ -265,7 +408,7 @@ func TestValidator_ValidateSchema(t *testing.T) {
 				store.EXPECT().
 					GetSchema(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(`{
+					Return(helperSchema(`{
 					  "$schema":"http://json-schema.org/draft-04/schema#",
 					  "type": "object",
 					  "properties": {
@@ -282,7 +425,11 @@ func TestValidator_ValidateSchema(t *testing.T) {
 						  "required": ["size"]
 						}
 					  }
-					}`, nil)
+					}`), nil)
+				store.EXPECT().
+					GetQuarantineSettings(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
 			},
 			err: exceptions.ErrValidation,
 		},
@@ -299,22 +446,470 @@ func TestValidator_ValidateSchema(t *testing.T) {
 
 			v := helperNewValidator(t, store)
 
-			err := v.ValidateSchema(ctx, tt.schemaID, tt.payload)
+			_, err := v.ValidateSchema(ctx, tt.schemaID, tt.payload)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidator_DownloadSchemaByDigest(t *testing.T) {
+	digest := schema.Digest(`{"valid":"json"}`)
+
+	tc := []struct {
+		name      string
+		digest    string
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name:   "success",
+			digest: digest,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetBlob(gomock.Any(), digest).
+					Times(1).
+					Return(`{"valid":"json"}`, nil)
+			},
+			err: nil,
+		},
+		{
+			name:   "invalid digest",
+			digest: "config-schema",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetBlob(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			err: exceptions.ErrInvalidDigest,
+		},
+		{
+			name:   "not found",
+			digest: digest,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetBlob(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return("", gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+		{
+			name:   "generic error",
+			digest: digest,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetBlob(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return("", errors.New("error"))
+			},
+			err: exceptions.ErrDownloadSchema,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			s, err := v.DownloadSchemaByDigest(ctx, tt.digest)
 			if tt.err != nil {
 				require.Error(t, err)
-				assert.ErrorContains(t, err, tt.err.Error())
+				assert.ErrorIs(t, err, tt.err)
 			} else {
 				require.NoError(t, err)
+				require.NotEmpty(t, s)
 			}
 		})
 	}
 }
 
+func TestValidator_UpdateSchema(t *testing.T) {
+	tc := []struct {
+		name      string
+		schemaID  string
+		schema    string
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name:     "success",
+			schemaID: "config-schema",
+			schema:   `{ "valid": "json" }`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					UpdateSchema(gomock.Any(), "config-schema", `{"valid":"json"}`, "digest").
+					Times(1).
+					Return(helperSchema(`{ "valid": "json" }`), nil)
+			},
+			err: nil,
+		},
+		{
+			name:     "invalid json",
+			schemaID: "config-schema",
+			schema:   `{ "invalid"  }`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					UpdateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			err: exceptions.ErrInvalidJSON,
+		},
+		{
+			name:     "not found",
+			schemaID: "config-schema",
+			schema:   `{ "valid": "json" }`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					UpdateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+		{
+			name:     "modified concurrently",
+			schemaID: "config-schema",
+			schema:   `{ "valid": "json" }`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					UpdateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, exceptions.ErrPreconditionFailed)
+			},
+			err: exceptions.ErrPreconditionFailed,
+		},
+		{
+			name:     "generic error",
+			schemaID: "config-schema",
+			schema:   `{ "valid": "json" }`,
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					UpdateSchema(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("error"))
+			},
+			err: exceptions.ErrUpdateSchema,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			s, err := v.UpdateSchema(ctx, tt.schemaID, tt.schema, "digest")
+			if tt.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestValidator_DeleteSchema(t *testing.T) {
+	tc := []struct {
+		name      string
+		schemaID  string
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name:     "success",
+			schemaID: "config-schema",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					DeleteSchema(gomock.Any(), "config-schema", "digest").
+					Times(1).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:     "not found",
+			schemaID: "config-schema",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					DeleteSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+		{
+			name:     "modified concurrently",
+			schemaID: "config-schema",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					DeleteSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(exceptions.ErrPreconditionFailed)
+			},
+			err: exceptions.ErrPreconditionFailed,
+		},
+		{
+			name:     "generic error",
+			schemaID: "config-schema",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					DeleteSchema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errors.New("error"))
+			},
+			err: exceptions.ErrDeleteSchema,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			err := v.DeleteSchema(ctx, tt.schemaID, "digest")
+			if tt.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidator_RestoreSchema(t *testing.T) {
+	tc := []struct {
+		name      string
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name: "success",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					RestoreSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name: "not in trash",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					RestoreSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(gorm.ErrRecordNotFound)
+			},
+			err: exceptions.ErrNotFound,
+		},
+		{
+			name: "generic error",
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					RestoreSchema(gomock.Any(), "config-schema").
+					Times(1).
+					Return(errors.New("error"))
+			},
+			err: exceptions.ErrRestoreSchema,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			err := v.RestoreSchema(ctx, "config-schema")
+			if tt.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidator_ImportSchemas(t *testing.T) {
+	named := func(name, payload string) *schema.Schema {
+		s := helperSchema(payload)
+		s.SchemaID = name
+
+		return s
+	}
+
+	tc := []struct {
+		name      string
+		mode      schema.ImportMode
+		schemas   []*schema.Schema
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name:    "canonicalized",
+			mode:    schema.ImportOverwrite,
+			schemas: []*schema.Schema{named("orders", `{ "type" : "object" }`)},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), schema.ImportOverwrite).
+					Times(1).
+					DoAndReturn(func(_ context.Context, schemas []*schema.Schema, _ schema.ImportMode) (*schema.ImportResult, error) {
+						assert.Equal(t, `{"type":"object"}`, schemas[0].Schema.String())
+
+						return &schema.ImportResult{Updated: []string{"orders"}}, nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name:    "unknown mode",
+			mode:    schema.ImportMode("merge"),
+			schemas: []*schema.Schema{named("orders", `{}`)},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			err: exceptions.ErrInvalidQuery,
+		},
+		{
+			name:    "duplicate name",
+			mode:    schema.ImportSkipExisting,
+			schemas: []*schema.Schema{named("orders", `{}`), named("orders", `{}`)},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			err: exceptions.ErrInvalidBundle,
+		},
+		{
+			name:    "not an object",
+			mode:    schema.ImportSkipExisting,
+			schemas: []*schema.Schema{named("orders", `[]`)},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			err: exceptions.ErrInvalidBundle,
+		},
+		{
+			name:    "conflict",
+			mode:    schema.ImportFailOnConflict,
+			schemas: []*schema.Schema{named("orders", `{}`)},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, exceptions.ErrAlreadyExists)
+			},
+			err: exceptions.ErrAlreadyExists,
+		},
+		{
+			name:    "generic error",
+			mode:    schema.ImportFailOnConflict,
+			schemas: []*schema.Schema{named("orders", `{}`)},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					ImportSchemas(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("error"))
+			},
+			err: exceptions.ErrImportSchemas,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			_, err := v.ImportSchemas(ctx, tt.schemas, tt.mode)
+			if tt.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateSchema_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := helperSchema(`{"type":"object","required":["name"]}`)
+	s.Namespace, s.SchemaID = "team-a", "metrics-schema"
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().GetSchema(gomock.Any(), "metrics-schema").Times(2).Return(s, nil)
+	store.EXPECT().GetQuarantineSettings(gomock.Any(), gomock.Any()).Times(1).Return(nil, gorm.ErrRecordNotFound)
+
+	srv := helperNewValidator(t, store)
+
+	_, err := srv.ValidateSchema(context.TODO(), "metrics-schema", map[string]interface{}{"name": "config"})
+	require.NoError(t, err)
+
+	_, err = srv.ValidateSchema(context.TODO(), "metrics-schema", map[string]interface{}{})
+	require.ErrorIs(t, err, exceptions.ErrValidation)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Validations.WithLabelValues("team-a", "metrics-schema", metrics.ResultPass)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Validations.WithLabelValues("team-a", "metrics-schema", metrics.ResultFail)))
+}
+
 func helperNewValidator(t *testing.T, store storage.Storage) service.Service {
 	t.Helper()
 
 	cfg, _ := config.Load()
 	log := logruslog.DefaultLogger(cfg)
 
-	return validator.New(log, store)
+	return validator.New(cfg, log, store, events.New(cfg, log, store), stats.New(cfg, log, store))
+}
+
+func helperSchema(payload string) *schema.Schema {
+	s := datatypes.JSON(payload)
+
+	return &schema.Schema{
+		Schema:   &s,
+		Digest:   schema.Digest(payload),
+		Revision: 1,
+	}
 }
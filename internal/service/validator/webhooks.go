@@ -0,0 +1,169 @@
+package validator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+var webhookEvents = map[string]bool{
+	event.TypeCreated:        true,
+	event.TypeUpdated:        true,
+	event.TypeDeleted:        true,
+	event.TypeRestored:       true,
+	event.TypePurged:         true,
+	event.TypeActivated:      true,
+	event.TypeDeprecated:     true,
+	event.TypeRetired:        true,
+	webhook.TypeFailureSpike: true,
+}
+
+// CreateWebhook subscribes the webhook, generating its secret unless given one.
+func (v *Validator) CreateWebhook(ctx context.Context, wh *webhook.Webhook) error {
+	v.log.Debug(ctx, "Validator: creating webhook")
+
+	u, err := url.Parse(wh.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", exceptions.ErrInvalidWebhook)
+	}
+
+	for _, t := range wh.Events {
+		if !webhookEvents[t] {
+			return fmt.Errorf("%w: unknown event %q", exceptions.ErrInvalidWebhook, t)
+		}
+	}
+
+	if wh.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err = rand.Read(secret); err != nil {
+			return fmt.Errorf("%w:%v", exceptions.ErrCreateWebhook, err)
+		}
+
+		wh.Secret = hex.EncodeToString(secret)
+	}
+
+	if err = v.db.CreateWebhook(ctx, wh); err != nil {
+		return fmt.Errorf("%w:%v", exceptions.ErrCreateWebhook, err)
+	}
+
+	return nil
+}
+
+// GetWebhook returns the webhook, without its secret.
+func (v *Validator) GetWebhook(ctx context.Context, id uint) (*webhook.Webhook, error) {
+	v.log.Debug(ctx, "Validator: getting webhook")
+
+	wh, err := v.db.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exceptions.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListWebhooks, err)
+	}
+
+	wh.Secret = ""
+
+	return wh, nil
+}
+
+// ListWebhooks returns the webhooks, without their secret.
+func (v *Validator) ListWebhooks(ctx context.Context) ([]*webhook.Webhook, error) {
+	v.log.Debug(ctx, "Validator: listing webhooks")
+
+	webhooks, err := v.db.ListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListWebhooks, err)
+	}
+
+	for _, wh := range webhooks {
+		wh.Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+func (v *Validator) DeleteWebhook(ctx context.Context, id uint) error {
+	v.log.Debug(ctx, "Validator: deleting webhook")
+
+	if err := v.db.DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.ErrNotFound
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrDeleteWebhook, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	v.log.Debug(ctx, "Validator: listing webhook deliveries")
+
+	switch filter.Status {
+	case "", webhook.StatusPending, webhook.StatusSucceeded, webhook.StatusDead:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", exceptions.ErrInvalidQuery, filter.Status)
+	}
+
+	deliveries, err := v.db.ListDeliveries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%v", exceptions.ErrListDeliveries, err)
+	}
+
+	return deliveries, nil
+}
+
+func (v *Validator) RedeliverDelivery(ctx context.Context, id uint64) error {
+	v.log.Debug(ctx, "Validator: redelivering webhook delivery")
+
+	if err := v.db.RedeliverDelivery(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exceptions.ErrNotFound
+		}
+
+		return fmt.Errorf("%w:%v", exceptions.ErrRedeliver, err)
+	}
+
+	return nil
+}
+
+// failed records a failed validation, and notifies the webhooks when the failures of the schema spike.
+func (v *Validator) failed(ctx context.Context, schemaID string) {
+	namespace := contexts.Namespace(ctx)
+
+	w, spiked := v.spikes.fail(namespace+"/"+schemaID, time.Now())
+	if !spiked {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":      webhook.TypeFailureSpike,
+		"namespace": namespace,
+		"name":      schemaID,
+		"failures":  w.failures,
+		"since":     w.start,
+		"window":    v.cfg.Webhook.FailureSpikeWindow.String(),
+	})
+	if err != nil {
+		v.log.Error(ctx, err, "could not encode failure spike")
+
+		return
+	}
+
+	if err = v.db.EnqueueDeliveries(ctx, webhook.TypeFailureSpike, schemaID, payload); err != nil {
+		v.log.Error(ctx, err, "could not notify failure spike")
+	}
+}
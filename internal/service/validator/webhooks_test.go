@@ -0,0 +1,189 @@
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
+	"github.com/KarolosLykos/json-validation-service/internal/service/events"
+	"github.com/KarolosLykos/json-validation-service/internal/service/stats"
+	"github.com/KarolosLykos/json-validation-service/internal/service/validator"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestValidator_CreateWebhook(t *testing.T) {
+	tc := []struct {
+		name      string
+		webhook   *webhook.Webhook
+		storeStub func(store *mock_storage.MockStorage)
+		err       error
+	}{
+		{
+			name:    "secret generated",
+			webhook: &webhook.Webhook{URL: "https://ci.example.com/hook", Events: webhook.Events{"schema.updated"}},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, wh *webhook.Webhook) error {
+						assert.Len(t, wh.Secret, 64)
+
+						return nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name:    "secret given",
+			webhook: &webhook.Webhook{URL: "http://localhost:9000", Secret: "s3cr3t"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), &webhook.Webhook{URL: "http://localhost:9000", Secret: "s3cr3t"}).
+					Times(1).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name: "lifecycle events",
+			webhook: &webhook.Webhook{
+				URL:    "https://ci.example.com/hook",
+				Secret: "s3cr3t",
+				Events: webhook.Events{"schema.activated", "schema.deprecated", "schema.retired"},
+			},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:    "relative url",
+			webhook: &webhook.Webhook{URL: "/hook"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrInvalidWebhook,
+		},
+		{
+			name:    "unsupported scheme",
+			webhook: &webhook.Webhook{URL: "ftp://example.com"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrInvalidWebhook,
+		},
+		{
+			name:    "unknown event",
+			webhook: &webhook.Webhook{URL: "https://example.com", Events: webhook.Events{"schema.renamed"}},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			err: exceptions.ErrInvalidWebhook,
+		},
+		{
+			name:    "generic error",
+			webhook: &webhook.Webhook{URL: "https://example.com"},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("error"))
+			},
+			err: exceptions.ErrCreateWebhook,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			v := helperNewValidator(t, store)
+
+			err := v.CreateWebhook(ctx, tt.webhook)
+			if tt.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidator_ListWebhooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		ListWebhooks(gomock.Any()).
+		Times(1).
+		Return([]*webhook.Webhook{{ID: 1, URL: "https://example.com", Secret: "s3cr3t"}}, nil)
+	store.EXPECT().
+		GetWebhook(gomock.Any(), uint(2)).
+		Times(1).
+		Return(nil, gorm.ErrRecordNotFound)
+
+	v := helperNewValidator(t, store)
+
+	webhooks, err := v.ListWebhooks(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Empty(t, webhooks[0].Secret)
+
+	_, err = v.GetWebhook(context.TODO(), 2)
+	assert.ErrorIs(t, err, exceptions.ErrNotFound)
+}
+
+func TestValidator_FailureSpike(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := helperSchema(`{"type":"object","required":["source"]}`)
+	s.SchemaID = "config-schema"
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		GetSchema(gomock.Any(), "config-schema").
+		Times(4).
+		Return(s, nil)
+	store.EXPECT().
+		GetQuarantineSettings(gomock.Any(), gomock.Any()).
+		Times(4).
+		Return(&quarantine.Settings{SchemaID: "config-schema"}, nil)
+	store.EXPECT().
+		EnqueueDeliveries(gomock.Any(), webhook.TypeFailureSpike, "config-schema", gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, _, _ string, payload []byte) error {
+			spike := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(payload, &spike))
+			assert.Equal(t, float64(3), spike["failures"])
+
+			return nil
+		})
+
+	cfg, _ := config.Load()
+	cfg.Webhook.FailureSpikeThreshold = 3
+
+	log := logruslog.DefaultLogger(cfg)
+	v := validator.New(cfg, log, store, events.New(cfg, log, store), stats.New(cfg, log, store))
+
+	// the spike is notified once per window, when reaching the threshold.
+	for i := 0; i < 4; i++ {
+		_, err := v.ValidateSchema(context.TODO(), "config-schema", map[string]interface{}{})
+		assert.ErrorContains(t, err, exceptions.ErrValidation.Error())
+	}
+}
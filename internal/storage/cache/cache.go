@@ -0,0 +1,275 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/metrics"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/storage"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+// cache is a read-through, size bounded cache of the schemas, invalidated by the mutations.
+type cache struct {
+	storage.Storage
+
+	cfg *config.Config
+	log logger.Logger
+
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	// generation is bumped on every invalidation, so that loads racing with a mutation are not cached.
+	generation uint64
+}
+
+// Invalidator drops the cached schemas, see events.Bus.Watch.
+type Invalidator interface {
+	Invalidate(namespace, schemaID string)
+}
+
+type entry struct {
+	key     string
+	schema  *schema.Schema // nil when the schema was not found
+	expires time.Time
+}
+
+func New(cfg *config.Config, log logger.Logger, next storage.Storage) storage.Storage {
+	return &cache{
+		Storage: next,
+		cfg:     cfg,
+		log:     log,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (c *cache) Connect(ctx context.Context) (storage.Storage, error) {
+	if _, err := c.Storage.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *cache) GetSchema(ctx context.Context, schemaID string) (*schema.Schema, error) {
+	key := cacheKey(ctx, schemaID)
+
+	if s, ok := c.get(key); ok {
+		metrics.CacheRequests.WithLabelValues(metrics.ResultHit).Inc()
+
+		if s == nil {
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		return s, nil
+	}
+
+	metrics.CacheRequests.WithLabelValues(metrics.ResultMiss).Inc()
+
+	// the load is shared by the concurrent misses, each of which can give up on it.
+	loaded := c.group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := c.detach(ctx)
+		defer cancel()
+
+		generation := c.currentGeneration()
+
+		s, err := c.Storage.GetSchema(ctx, schemaID)
+
+		switch {
+		case err == nil:
+			c.set(key, s, c.cfg.Cache.TTL, generation)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.set(key, nil, c.cfg.Cache.NegativeTTL, generation)
+		}
+
+		return s, err
+	})
+
+	select {
+	case res := <-loaded:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		return copySchema(res.Val.(*schema.Schema)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// detach bounds the load by the query timeout rather than by the request starting it.
+func (c *cache) detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.cfg.Storage.QueryTimeout <= 0 {
+		return context.WithCancel(contexts.Detach(ctx))
+	}
+
+	return context.WithTimeout(contexts.Detach(ctx), c.cfg.Storage.QueryTimeout)
+}
+
+func (c *cache) Invalidate(namespace, schemaID string) {
+	c.invalidate(namespace + "/" + schemaID)
+}
+
+func (c *cache) CreateSchema(ctx context.Context, schemaID, payload string, meta schema.Metadata) error {
+	defer c.invalidate(cacheKey(ctx, schemaID))
+
+	return c.Storage.CreateSchema(ctx, schemaID, payload, meta)
+}
+
+func (c *cache) UpdateSchema(ctx context.Context, schemaID, payload, digest string) (*schema.Schema, error) {
+	defer c.invalidate(cacheKey(ctx, schemaID))
+
+	return c.Storage.UpdateSchema(ctx, schemaID, payload, digest)
+}
+
+func (c *cache) DeleteSchema(ctx context.Context, schemaID, digest string) error {
+	defer c.invalidate(cacheKey(ctx, schemaID))
+
+	return c.Storage.DeleteSchema(ctx, schemaID, digest)
+}
+
+func (c *cache) UpdateLifecycle(ctx context.Context, schemaID string, previous, lifecycle schema.Lifecycle) (*schema.Schema, error) {
+	defer c.invalidate(cacheKey(ctx, schemaID))
+
+	return c.Storage.UpdateLifecycle(ctx, schemaID, previous, lifecycle)
+}
+
+func (c *cache) RestoreSchema(ctx context.Context, schemaID string) error {
+	defer c.invalidate(cacheKey(ctx, schemaID))
+
+	return c.Storage.RestoreSchema(ctx, schemaID)
+}
+
+func (c *cache) ImportSchemas(ctx context.Context, schemas []*schema.Schema, mode schema.ImportMode) (*schema.ImportResult, error) {
+	defer func() {
+		for _, s := range schemas {
+			c.invalidate(cacheKey(ctx, s.SchemaID))
+		}
+	}()
+
+	return c.Storage.ImportSchemas(ctx, schemas, mode)
+}
+
+// cacheKey relies on schema IDs not containing "/".
+func cacheKey(ctx context.Context, schemaID string) string {
+	return contexts.Namespace(ctx) + "/" + schemaID
+}
+
+func (c *cache) get(key string) (*schema.Schema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+
+	if time.Now().After(e.expires) {
+		c.remove(el)
+
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+
+	return copySchema(e.schema), true
+}
+
+func (c *cache) set(key string, s *schema.Schema, ttl time.Duration, generation uint64) {
+	if ttl <= 0 || c.cfg.Cache.MaxEntries <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+
+	c.entries[key] = c.lru.PushFront(&entry{
+		key:     key,
+		schema:  s,
+		expires: time.Now().Add(ttl),
+	})
+
+	for c.lru.Len() > c.cfg.Cache.MaxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *cache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.group.Forget(key)
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *cache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// remove must be called with the lock held.
+func (c *cache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*entry).key)
+}
+
+func copySchema(s *schema.Schema) *schema.Schema {
+	if s == nil {
+		return nil
+	}
+
+	cp := *s
+
+	if s.Schema != nil {
+		body := append(datatypes.JSON(nil), *s.Schema...)
+		cp.Schema = &body
+	}
+
+	if s.Labels != nil {
+		cp.Labels = make(schema.Labels, len(s.Labels))
+		for k, v := range s.Labels {
+			cp.Labels[k] = v
+		}
+	}
+
+	if s.DeprecatedAt != nil {
+		deprecatedAt := *s.DeprecatedAt
+		cp.DeprecatedAt = &deprecatedAt
+	}
+
+	if s.Sunset != nil {
+		sunset := *s.Sunset
+		cp.Sunset = &sunset
+	}
+
+	return &cp
+}
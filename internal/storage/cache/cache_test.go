@@ -0,0 +1,350 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+	"github.com/KarolosLykos/json-validation-service/internal/metrics"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/storage"
+	"github.com/KarolosLykos/json-validation-service/internal/storage/cache"
+	mock_storage "github.com/KarolosLykos/json-validation-service/internal/storage/mock"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+func TestCache_GetSchema(t *testing.T) {
+	tc := []struct {
+		name      string
+		cfg       config.Cache
+		storeStub func(store *mock_storage.MockStorage)
+		run       func(t *testing.T, c storage.Storage)
+	}{
+		{
+			name: "serves hits from the cache",
+			cfg:  config.Cache{TTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 10},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "a").
+					Times(1).
+					Return(helperSchema("a"), nil)
+			},
+			run: func(t *testing.T, c storage.Storage) {
+				for i := 0; i < 3; i++ {
+					s, err := c.GetSchema(context.TODO(), "a")
+					require.NoError(t, err)
+					assert.Equal(t, "a", s.SchemaID)
+				}
+			},
+		},
+		{
+			name: "caches not found",
+			cfg:  config.Cache{TTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 10},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "a").
+					Times(1).
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			run: func(t *testing.T, c storage.Storage) {
+				for i := 0; i < 3; i++ {
+					_, err := c.GetSchema(context.TODO(), "a")
+					assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				}
+			},
+		},
+		{
+			name: "does not cache errors",
+			cfg:  config.Cache{TTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 10},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "a").
+					Times(2).
+					Return(nil, errors.New("error"))
+			},
+			run: func(t *testing.T, c storage.Storage) {
+				for i := 0; i < 2; i++ {
+					_, err := c.GetSchema(context.TODO(), "a")
+					assert.Error(t, err)
+				}
+			},
+		},
+		{
+			name: "expires entries",
+			cfg:  config.Cache{TTL: 10 * time.Millisecond, NegativeTTL: time.Minute, MaxEntries: 10},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "a").
+					Times(2).
+					Return(helperSchema("a"), nil)
+			},
+			run: func(t *testing.T, c storage.Storage) {
+				_, err := c.GetSchema(context.TODO(), "a")
+				require.NoError(t, err)
+
+				time.Sleep(20 * time.Millisecond)
+
+				_, err = c.GetSchema(context.TODO(), "a")
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "evicts the least recently used entry",
+			cfg:  config.Cache{TTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 2},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(helperSchema("a"), nil)
+				store.EXPECT().GetSchema(gomock.Any(), "b").Times(2).Return(helperSchema("b"), nil)
+				store.EXPECT().GetSchema(gomock.Any(), "c").Times(1).Return(helperSchema("c"), nil)
+			},
+			run: func(t *testing.T, c storage.Storage) {
+				for _, id := range []string{"a", "b", "a", "c", "a", "b"} {
+					_, err := c.GetSchema(context.TODO(), id)
+					require.NoError(t, err)
+				}
+			},
+		},
+		{
+			name: "invalidates on mutations",
+			cfg:  config.Cache{TTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 10},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(nil, gorm.ErrRecordNotFound)
+				store.EXPECT().CreateSchema(gomock.Any(), "a", gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(helperSchema("a"), nil)
+				store.EXPECT().UpdateSchema(gomock.Any(), "a", gomock.Any(), gomock.Any()).Times(1).Return(helperSchema("a"), nil)
+				store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(helperSchema("a"), nil)
+				store.EXPECT().DeleteSchema(gomock.Any(), "a", gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(nil, gorm.ErrRecordNotFound)
+			},
+			run: func(t *testing.T, c storage.Storage) {
+				ctx := context.TODO()
+
+				_, err := c.GetSchema(ctx, "a")
+				require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+				require.NoError(t, c.CreateSchema(ctx, "a", "{}", schema.Metadata{}))
+
+				_, err = c.GetSchema(ctx, "a")
+				require.NoError(t, err)
+
+				_, err = c.UpdateSchema(ctx, "a", "{}", "digest")
+				require.NoError(t, err)
+
+				_, err = c.GetSchema(ctx, "a")
+				require.NoError(t, err)
+
+				require.NoError(t, c.DeleteSchema(ctx, "a", "digest"))
+
+				_, err = c.GetSchema(ctx, "a")
+				require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "scopes entries to the namespace",
+			cfg:  config.Cache{TTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 10},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(helperSchema("a"), nil)
+				store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(nil, gorm.ErrRecordNotFound)
+			},
+			run: func(t *testing.T, c storage.Storage) {
+				teamA := contexts.WithNamespace(context.TODO(), "team-a")
+				teamB := contexts.WithNamespace(context.TODO(), "team-b")
+
+				_, err := c.GetSchema(teamA, "a")
+				require.NoError(t, err)
+
+				_, err = c.GetSchema(teamB, "a")
+				require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+				_, err = c.GetSchema(teamA, "a")
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "collapses concurrent misses",
+			cfg:  config.Cache{TTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 10},
+			storeStub: func(store *mock_storage.MockStorage) {
+				store.EXPECT().
+					GetSchema(gomock.Any(), "a").
+					Times(1).
+					DoAndReturn(func(context.Context, string) (*schema.Schema, error) {
+						time.Sleep(50 * time.Millisecond)
+
+						return helperSchema("a"), nil
+					})
+			},
+			run: func(t *testing.T, c storage.Storage) {
+				wg := sync.WaitGroup{}
+
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+
+					go func() {
+						defer wg.Done()
+
+						s, err := c.GetSchema(context.TODO(), "a")
+						assert.NoError(t, err)
+						assert.Equal(t, "a", s.SchemaID)
+					}()
+				}
+
+				wg.Wait()
+			},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_storage.NewMockStorage(ctrl)
+			tt.storeStub(store)
+
+			cfg, _ := config.Load()
+			cfg.Cache = tt.cfg
+
+			c := cache.New(cfg, logruslog.DefaultLogger(cfg), store)
+
+			tt.run(t, c)
+		})
+	}
+}
+
+func TestCache_ReturnsCopies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(helperSchema("a"), nil)
+
+	cfg, _ := config.Load()
+	c := cache.New(cfg, logruslog.DefaultLogger(cfg), store)
+
+	s, err := c.GetSchema(context.TODO(), "a")
+	require.NoError(t, err)
+
+	s.Labels["env"] = "changed"
+	*s.Schema = datatypes.JSON("{}")
+
+	s, err = c.GetSchema(context.TODO(), "a")
+	require.NoError(t, err)
+
+	assert.Equal(t, "prod", s.Labels["env"])
+	assert.Equal(t, `{"type":"object"}`, s.Schema.String())
+}
+
+func TestCache_CallerGivesUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loading, release := make(chan struct{}), make(chan struct{})
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().
+		GetSchema(gomock.Any(), "a").
+		Times(1).
+		DoAndReturn(func(ctx context.Context, schemaID string) (*schema.Schema, error) {
+			close(loading)
+			<-release
+
+			// the load outlives the caller starting it, in its namespace.
+			assert.NoError(t, ctx.Err())
+			assert.Equal(t, "team-a", contexts.Namespace(ctx))
+
+			return helperSchema(schemaID), nil
+		})
+
+	cfg, _ := config.Load()
+	c := cache.New(cfg, logruslog.DefaultLogger(cfg), store)
+
+	first, cancel := context.WithCancel(contexts.WithNamespace(context.Background(), "team-a"))
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, err := c.GetSchema(first, "a")
+		firstErr <- err
+	}()
+
+	<-loading
+
+	second := make(chan *schema.Schema, 1)
+
+	go func() {
+		s, err := c.GetSchema(contexts.WithNamespace(context.Background(), "team-a"), "a")
+		assert.NoError(t, err)
+		second <- s
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "a", (<-second).SchemaID)
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().GetSchema(gomock.Any(), "a").Times(2).Return(helperSchema("a"), nil)
+
+	cfg, _ := config.Load()
+	c := cache.New(cfg, logruslog.DefaultLogger(cfg), store)
+
+	ctx := contexts.WithNamespace(context.Background(), "team-a")
+
+	_, err := c.GetSchema(ctx, "a")
+	require.NoError(t, err)
+
+	// changed by another replica.
+	c.(cache.Invalidator).Invalidate("team-a", "a")
+
+	_, err = c.GetSchema(ctx, "a")
+	require.NoError(t, err)
+}
+
+func TestCache_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_storage.NewMockStorage(ctrl)
+	store.EXPECT().GetSchema(gomock.Any(), "a").Times(1).Return(helperSchema("a"), nil)
+
+	cfg, _ := config.Load()
+	c := cache.New(cfg, logruslog.DefaultLogger(cfg), store)
+
+	hits := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues(metrics.ResultHit))
+	misses := testutil.ToFloat64(metrics.CacheRequests.WithLabelValues(metrics.ResultMiss))
+
+	for i := 0; i < 3; i++ {
+		_, err := c.GetSchema(context.TODO(), "a")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, hits+2, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues(metrics.ResultHit)))
+	assert.Equal(t, misses+1, testutil.ToFloat64(metrics.CacheRequests.WithLabelValues(metrics.ResultMiss)))
+}
+
+func helperSchema(schemaID string) *schema.Schema {
+	body := datatypes.JSON(`{"type":"object"}`)
+
+	return &schema.Schema{
+		SchemaID: schemaID,
+		Schema:   &body,
+		Digest:   schema.Digest(body.String()),
+		Revision: 1,
+		Metadata: schema.Metadata{Labels: schema.Labels{"env": "prod"}},
+	}
+}
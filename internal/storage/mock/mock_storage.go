@@ -7,7 +7,17 @@ package mock_storage
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	audit "github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	auth "github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	event "github.com/KarolosLykos/json-validation-service/internal/models/event"
+	quarantine "github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	ratelimit "github.com/KarolosLykos/json-validation-service/internal/models/ratelimit"
+	schema "github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	tag "github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	validation "github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	webhook "github.com/KarolosLykos/json-validation-service/internal/models/webhook"
 	storage "github.com/KarolosLykos/json-validation-service/internal/storage"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +45,21 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// ClaimDeliveries mocks base method.
+func (m *MockStorage) ClaimDeliveries(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) ([]*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, now, limit, leaseUntil)
+	ret0, _ := ret[0].([]*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockStorageMockRecorder) ClaimDeliveries(ctx, now, limit, leaseUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockStorage)(nil).ClaimDeliveries), ctx, now, limit, leaseUntil)
+}
+
 // Connect mocks base method.
 func (m *MockStorage) Connect(ctx context.Context) (storage.Storage, error) {
 	m.ctrl.T.Helper()
@@ -50,25 +75,182 @@ func (mr *MockStorageMockRecorder) Connect(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockStorage)(nil).Connect), ctx)
 }
 
+// CreateAPIKey mocks base method.
+func (m *MockStorage) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockStorageMockRecorder) CreateAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockStorage)(nil).CreateAPIKey), ctx, key)
+}
+
 // CreateSchema mocks base method.
-func (m *MockStorage) CreateSchema(ctx context.Context, schemaID, schema string) error {
+func (m *MockStorage) CreateSchema(ctx context.Context, schemaID, payload string, meta schema.Metadata) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSchema", ctx, schemaID, schema)
+	ret := m.ctrl.Call(m, "CreateSchema", ctx, schemaID, payload, meta)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateSchema indicates an expected call of CreateSchema.
-func (mr *MockStorageMockRecorder) CreateSchema(ctx, schemaID, schema interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) CreateSchema(ctx, schemaID, payload, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchema", reflect.TypeOf((*MockStorage)(nil).CreateSchema), ctx, schemaID, payload, meta)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockStorage) CreateWebhook(ctx context.Context, wh *webhook.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, wh)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStorageMockRecorder) CreateWebhook(ctx, wh interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStorage)(nil).CreateWebhook), ctx, wh)
+}
+
+// DeleteAPIKey mocks base method.
+func (m *MockStorage) DeleteAPIKey(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAPIKey indicates an expected call of DeleteAPIKey.
+func (mr *MockStorageMockRecorder) DeleteAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKey", reflect.TypeOf((*MockStorage)(nil).DeleteAPIKey), ctx, id)
+}
+
+// DeleteSchema mocks base method.
+func (m *MockStorage) DeleteSchema(ctx context.Context, schemaID, digest string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSchema", ctx, schemaID, digest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSchema indicates an expected call of DeleteSchema.
+func (mr *MockStorageMockRecorder) DeleteSchema(ctx, schemaID, digest interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchema", reflect.TypeOf((*MockStorage)(nil).CreateSchema), ctx, schemaID, schema)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSchema", reflect.TypeOf((*MockStorage)(nil).DeleteSchema), ctx, schemaID, digest)
+}
+
+// DeleteTag mocks base method.
+func (m *MockStorage) DeleteTag(ctx context.Context, schemaID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, schemaID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockStorageMockRecorder) DeleteTag(ctx, schemaID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockStorage)(nil).DeleteTag), ctx, schemaID, name)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStorage) DeleteWebhook(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStorageMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStorage)(nil).DeleteWebhook), ctx, id)
+}
+
+// EnqueueDeliveries mocks base method.
+func (m *MockStorage) EnqueueDeliveries(ctx context.Context, eventType, schemaID string, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", ctx, eventType, schemaID, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockStorageMockRecorder) EnqueueDeliveries(ctx, eventType, schemaID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockStorage)(nil).EnqueueDeliveries), ctx, eventType, schemaID, payload)
+}
+
+// ExportSchemas mocks base method.
+func (m *MockStorage) ExportSchemas(ctx context.Context, filter schema.Filter, fn func(*schema.Schema) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportSchemas", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportSchemas indicates an expected call of ExportSchemas.
+func (mr *MockStorageMockRecorder) ExportSchemas(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSchemas", reflect.TypeOf((*MockStorage)(nil).ExportSchemas), ctx, filter, fn)
+}
+
+// GetAPIKey mocks base method.
+func (m *MockStorage) GetAPIKey(ctx context.Context, hash string) (*auth.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKey", ctx, hash)
+	ret0, _ := ret[0].(*auth.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKey indicates an expected call of GetAPIKey.
+func (mr *MockStorageMockRecorder) GetAPIKey(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockStorage)(nil).GetAPIKey), ctx, hash)
+}
+
+// GetBlob mocks base method.
+func (m *MockStorage) GetBlob(ctx context.Context, digest string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlob", ctx, digest)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlob indicates an expected call of GetBlob.
+func (mr *MockStorageMockRecorder) GetBlob(ctx, digest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlob", reflect.TypeOf((*MockStorage)(nil).GetBlob), ctx, digest)
+}
+
+// GetQuarantineSettings mocks base method.
+func (m *MockStorage) GetQuarantineSettings(ctx context.Context, schemaID string) (*quarantine.Settings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuarantineSettings", ctx, schemaID)
+	ret0, _ := ret[0].(*quarantine.Settings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuarantineSettings indicates an expected call of GetQuarantineSettings.
+func (mr *MockStorageMockRecorder) GetQuarantineSettings(ctx, schemaID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuarantineSettings", reflect.TypeOf((*MockStorage)(nil).GetQuarantineSettings), ctx, schemaID)
 }
 
 // GetSchema mocks base method.
-func (m *MockStorage) GetSchema(ctx context.Context, schemaID string) (string, error) {
+func (m *MockStorage) GetSchema(ctx context.Context, schemaID string) (*schema.Schema, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSchema", ctx, schemaID)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*schema.Schema)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -79,6 +261,66 @@ func (mr *MockStorageMockRecorder) GetSchema(ctx, schemaID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchema", reflect.TypeOf((*MockStorage)(nil).GetSchema), ctx, schemaID)
 }
 
+// GetSchemaVersion mocks base method.
+func (m *MockStorage) GetSchemaVersion(ctx context.Context, schemaID string, version int) (*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemaVersion", ctx, schemaID, version)
+	ret0, _ := ret[0].(*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchemaVersion indicates an expected call of GetSchemaVersion.
+func (mr *MockStorageMockRecorder) GetSchemaVersion(ctx, schemaID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemaVersion", reflect.TypeOf((*MockStorage)(nil).GetSchemaVersion), ctx, schemaID, version)
+}
+
+// GetTag mocks base method.
+func (m *MockStorage) GetTag(ctx context.Context, schemaID, name string) (*tag.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, schemaID, name)
+	ret0, _ := ret[0].(*tag.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockStorageMockRecorder) GetTag(ctx, schemaID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockStorage)(nil).GetTag), ctx, schemaID, name)
+}
+
+// GetWebhook mocks base method.
+func (m *MockStorage) GetWebhook(ctx context.Context, id uint) (*webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(*webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockStorageMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockStorage)(nil).GetWebhook), ctx, id)
+}
+
+// ImportSchemas mocks base method.
+func (m *MockStorage) ImportSchemas(ctx context.Context, schemas []*schema.Schema, mode schema.ImportMode) (*schema.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSchemas", ctx, schemas, mode)
+	ret0, _ := ret[0].(*schema.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportSchemas indicates an expected call of ImportSchemas.
+func (mr *MockStorageMockRecorder) ImportSchemas(ctx, schemas, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSchemas", reflect.TypeOf((*MockStorage)(nil).ImportSchemas), ctx, schemas, mode)
+}
+
 // Initialize mocks base method.
 func (m *MockStorage) Initialize(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -93,6 +335,314 @@ func (mr *MockStorageMockRecorder) Initialize(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Initialize", reflect.TypeOf((*MockStorage)(nil).Initialize), ctx)
 }
 
+// LastEventID mocks base method.
+func (m *MockStorage) LastEventID(ctx context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastEventID", ctx)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastEventID indicates an expected call of LastEventID.
+func (mr *MockStorageMockRecorder) LastEventID(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastEventID", reflect.TypeOf((*MockStorage)(nil).LastEventID), ctx)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockStorage) ListAPIKeys(ctx context.Context) ([]*auth.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].([]*auth.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockStorageMockRecorder) ListAPIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockStorage)(nil).ListAPIKeys), ctx)
+}
+
+// ListAudit mocks base method.
+func (m *MockStorage) ListAudit(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudit", ctx, filter)
+	ret0, _ := ret[0].([]*audit.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAudit indicates an expected call of ListAudit.
+func (mr *MockStorageMockRecorder) ListAudit(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudit", reflect.TypeOf((*MockStorage)(nil).ListAudit), ctx, filter)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockStorage) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, filter)
+	ret0, _ := ret[0].([]*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockStorageMockRecorder) ListDeliveries(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockStorage)(nil).ListDeliveries), ctx, filter)
+}
+
+// ListEvents mocks base method.
+func (m *MockStorage) ListEvents(ctx context.Context, after uint64, limit int) ([]*event.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, after, limit)
+	ret0, _ := ret[0].([]*event.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockStorageMockRecorder) ListEvents(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockStorage)(nil).ListEvents), ctx, after, limit)
+}
+
+// ListQuarantine mocks base method.
+func (m *MockStorage) ListQuarantine(ctx context.Context, schemaID string, now time.Time, limit int) ([]*quarantine.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuarantine", ctx, schemaID, now, limit)
+	ret0, _ := ret[0].([]*quarantine.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuarantine indicates an expected call of ListQuarantine.
+func (mr *MockStorageMockRecorder) ListQuarantine(ctx, schemaID, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuarantine", reflect.TypeOf((*MockStorage)(nil).ListQuarantine), ctx, schemaID, now, limit)
+}
+
+// ListSchemas mocks base method.
+func (m *MockStorage) ListSchemas(ctx context.Context, filter schema.Filter) ([]*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchemas", ctx, filter)
+	ret0, _ := ret[0].([]*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchemas indicates an expected call of ListSchemas.
+func (mr *MockStorageMockRecorder) ListSchemas(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchemas", reflect.TypeOf((*MockStorage)(nil).ListSchemas), ctx, filter)
+}
+
+// ListTags mocks base method.
+func (m *MockStorage) ListTags(ctx context.Context, schemaID string) ([]*tag.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, schemaID)
+	ret0, _ := ret[0].([]*tag.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockStorageMockRecorder) ListTags(ctx, schemaID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockStorage)(nil).ListTags), ctx, schemaID)
+}
+
+// ListTrash mocks base method.
+func (m *MockStorage) ListTrash(ctx context.Context) ([]*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx)
+	ret0, _ := ret[0].([]*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockStorageMockRecorder) ListTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockStorage)(nil).ListTrash), ctx)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockStorage) ListWebhooks(ctx context.Context) ([]*webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]*webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockStorageMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockStorage)(nil).ListWebhooks), ctx)
+}
+
+// Listen mocks base method.
+func (m *MockStorage) Listen(ctx context.Context, fn func()) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Listen", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Listen indicates an expected call of Listen.
+func (mr *MockStorageMockRecorder) Listen(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Listen", reflect.TypeOf((*MockStorage)(nil).Listen), ctx, fn)
+}
+
+// PurgeQuarantine mocks base method.
+func (m *MockStorage) PurgeQuarantine(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeQuarantine", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeQuarantine indicates an expected call of PurgeQuarantine.
+func (mr *MockStorageMockRecorder) PurgeQuarantine(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeQuarantine", reflect.TypeOf((*MockStorage)(nil).PurgeQuarantine), ctx, now)
+}
+
+// PurgeRateLimits mocks base method.
+func (m *MockStorage) PurgeRateLimits(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeRateLimits", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeRateLimits indicates an expected call of PurgeRateLimits.
+func (mr *MockStorageMockRecorder) PurgeRateLimits(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeRateLimits", reflect.TypeOf((*MockStorage)(nil).PurgeRateLimits), ctx, before)
+}
+
+// PurgeSchemas mocks base method.
+func (m *MockStorage) PurgeSchemas(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSchemas", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeSchemas indicates an expected call of PurgeSchemas.
+func (mr *MockStorageMockRecorder) PurgeSchemas(ctx, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSchemas", reflect.TypeOf((*MockStorage)(nil).PurgeSchemas), ctx, deletedBefore)
+}
+
+// PurgeValidations mocks base method.
+func (m *MockStorage) PurgeValidations(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeValidations", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeValidations indicates an expected call of PurgeValidations.
+func (mr *MockStorageMockRecorder) PurgeValidations(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeValidations", reflect.TypeOf((*MockStorage)(nil).PurgeValidations), ctx, before)
+}
+
+// PutQuarantineSettings mocks base method.
+func (m *MockStorage) PutQuarantineSettings(ctx context.Context, settings *quarantine.Settings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutQuarantineSettings", ctx, settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutQuarantineSettings indicates an expected call of PutQuarantineSettings.
+func (mr *MockStorageMockRecorder) PutQuarantineSettings(ctx, settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutQuarantineSettings", reflect.TypeOf((*MockStorage)(nil).PutQuarantineSettings), ctx, settings)
+}
+
+// PutTag mocks base method.
+func (m *MockStorage) PutTag(ctx context.Context, t *tag.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutTag", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutTag indicates an expected call of PutTag.
+func (mr *MockStorageMockRecorder) PutTag(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutTag", reflect.TypeOf((*MockStorage)(nil).PutTag), ctx, t)
+}
+
+// QuarantinePayload mocks base method.
+func (m *MockStorage) QuarantinePayload(ctx context.Context, entry *quarantine.Entry, maxEntries int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuarantinePayload", ctx, entry, maxEntries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QuarantinePayload indicates an expected call of QuarantinePayload.
+func (mr *MockStorageMockRecorder) QuarantinePayload(ctx, entry, maxEntries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuarantinePayload", reflect.TypeOf((*MockStorage)(nil).QuarantinePayload), ctx, entry, maxEntries)
+}
+
+// RecordValidations mocks base method.
+func (m *MockStorage) RecordValidations(ctx context.Context, results []*validation.Result) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordValidations", ctx, results)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordValidations indicates an expected call of RecordValidations.
+func (mr *MockStorageMockRecorder) RecordValidations(ctx, results interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordValidations", reflect.TypeOf((*MockStorage)(nil).RecordValidations), ctx, results)
+}
+
+// RedeliverDelivery mocks base method.
+func (m *MockStorage) RedeliverDelivery(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverDelivery", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeliverDelivery indicates an expected call of RedeliverDelivery.
+func (mr *MockStorageMockRecorder) RedeliverDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverDelivery", reflect.TypeOf((*MockStorage)(nil).RedeliverDelivery), ctx, id)
+}
+
+// RestoreSchema mocks base method.
+func (m *MockStorage) RestoreSchema(ctx context.Context, schemaID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSchema", ctx, schemaID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSchema indicates an expected call of RestoreSchema.
+func (mr *MockStorageMockRecorder) RestoreSchema(ctx, schemaID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSchema", reflect.TypeOf((*MockStorage)(nil).RestoreSchema), ctx, schemaID)
+}
+
 // Shutdown mocks base method.
 func (m *MockStorage) Shutdown(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -106,3 +656,92 @@ func (mr *MockStorageMockRecorder) Shutdown(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockStorage)(nil).Shutdown), ctx)
 }
+
+// TagHistory mocks base method.
+func (m *MockStorage) TagHistory(ctx context.Context, schemaID, name string, limit int) ([]*tag.Move, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagHistory", ctx, schemaID, name, limit)
+	ret0, _ := ret[0].([]*tag.Move)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagHistory indicates an expected call of TagHistory.
+func (mr *MockStorageMockRecorder) TagHistory(ctx, schemaID, name, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagHistory", reflect.TypeOf((*MockStorage)(nil).TagHistory), ctx, schemaID, name, limit)
+}
+
+// TakeToken mocks base method.
+func (m *MockStorage) TakeToken(ctx context.Context, key string, rate float64, burst int) (*ratelimit.Bucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeToken", ctx, key, rate, burst)
+	ret0, _ := ret[0].(*ratelimit.Bucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeToken indicates an expected call of TakeToken.
+func (mr *MockStorageMockRecorder) TakeToken(ctx, key, rate, burst interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeToken", reflect.TypeOf((*MockStorage)(nil).TakeToken), ctx, key, rate, burst)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockStorage) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockStorageMockRecorder) UpdateDelivery(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockStorage)(nil).UpdateDelivery), ctx, d)
+}
+
+// UpdateLifecycle mocks base method.
+func (m *MockStorage) UpdateLifecycle(ctx context.Context, schemaID string, previous, lifecycle schema.Lifecycle) (*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLifecycle", ctx, schemaID, previous, lifecycle)
+	ret0, _ := ret[0].(*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLifecycle indicates an expected call of UpdateLifecycle.
+func (mr *MockStorageMockRecorder) UpdateLifecycle(ctx, schemaID, previous, lifecycle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLifecycle", reflect.TypeOf((*MockStorage)(nil).UpdateLifecycle), ctx, schemaID, previous, lifecycle)
+}
+
+// UpdateSchema mocks base method.
+func (m *MockStorage) UpdateSchema(ctx context.Context, schemaID, payload, digest string) (*schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchema", ctx, schemaID, payload, digest)
+	ret0, _ := ret[0].(*schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSchema indicates an expected call of UpdateSchema.
+func (mr *MockStorageMockRecorder) UpdateSchema(ctx, schemaID, payload, digest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchema", reflect.TypeOf((*MockStorage)(nil).UpdateSchema), ctx, schemaID, payload, digest)
+}
+
+// ValidationStats mocks base method.
+func (m *MockStorage) ValidationStats(ctx context.Context, schemaID string, now time.Time, windows []time.Duration, top int) (*validation.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidationStats", ctx, schemaID, now, windows, top)
+	ret0, _ := ret[0].(*validation.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidationStats indicates an expected call of ValidationStats.
+func (mr *MockStorageMockRecorder) ValidationStats(ctx, schemaID, now, windows, top interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidationStats", reflect.TypeOf((*MockStorage)(nil).ValidationStats), ctx, schemaID, now, windows, top)
+}
@@ -2,6 +2,17 @@ package storage
 
 import (
 	"context"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/models/ratelimit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
 )
 
 type Storage interface {
@@ -9,6 +20,58 @@ type Storage interface {
 	Shutdown(ctx context.Context) error
 	Initialize(ctx context.Context) error
 
-	CreateSchema(ctx context.Context, schemaID, schema string) error
-	GetSchema(ctx context.Context, schemaID string) (string, error)
+	CreateSchema(ctx context.Context, schemaID, payload string, meta schema.Metadata) error
+	GetSchema(ctx context.Context, schemaID string) (*schema.Schema, error)
+	GetBlob(ctx context.Context, digest string) (string, error)
+	GetSchemaVersion(ctx context.Context, schemaID string, version int) (*schema.Schema, error)
+	UpdateSchema(ctx context.Context, schemaID, payload, digest string) (*schema.Schema, error)
+	DeleteSchema(ctx context.Context, schemaID, digest string) error
+	ListSchemas(ctx context.Context, filter schema.Filter) ([]*schema.Schema, error)
+	UpdateLifecycle(ctx context.Context, schemaID string, previous, lifecycle schema.Lifecycle) (*schema.Schema, error)
+
+	ImportSchemas(ctx context.Context, schemas []*schema.Schema, mode schema.ImportMode) (*schema.ImportResult, error)
+	ExportSchemas(ctx context.Context, filter schema.Filter, fn func(*schema.Schema) error) error
+
+	ListTrash(ctx context.Context) ([]*schema.Schema, error)
+	RestoreSchema(ctx context.Context, schemaID string) error
+	PurgeSchemas(ctx context.Context, deletedBefore time.Time) (int64, error)
+
+	ListAudit(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error)
+
+	ListEvents(ctx context.Context, after uint64, limit int) ([]*event.Event, error)
+	LastEventID(ctx context.Context) (uint64, error)
+	Listen(ctx context.Context, fn func()) error
+
+	CreateWebhook(ctx context.Context, wh *webhook.Webhook) error
+	GetWebhook(ctx context.Context, id uint) (*webhook.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*webhook.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error)
+	EnqueueDeliveries(ctx context.Context, eventType, schemaID string, payload []byte) error
+	ClaimDeliveries(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) ([]*webhook.Delivery, error)
+	UpdateDelivery(ctx context.Context, d *webhook.Delivery) error
+	RedeliverDelivery(ctx context.Context, id uint64) error
+
+	CreateAPIKey(ctx context.Context, key *auth.APIKey) error
+	GetAPIKey(ctx context.Context, hash string) (*auth.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*auth.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id uint) error
+	TakeToken(ctx context.Context, key string, rate float64, burst int) (*ratelimit.Bucket, error)
+	PurgeRateLimits(ctx context.Context, before time.Time) (int64, error)
+
+	RecordValidations(ctx context.Context, results []*validation.Result) error
+	ValidationStats(ctx context.Context, schemaID string, now time.Time, windows []time.Duration, top int) (*validation.Stats, error)
+	PurgeValidations(ctx context.Context, before time.Time) (int64, error)
+
+	GetQuarantineSettings(ctx context.Context, schemaID string) (*quarantine.Settings, error)
+	PutQuarantineSettings(ctx context.Context, settings *quarantine.Settings) error
+	QuarantinePayload(ctx context.Context, entry *quarantine.Entry, maxEntries int) error
+	ListQuarantine(ctx context.Context, schemaID string, now time.Time, limit int) ([]*quarantine.Entry, error)
+	PurgeQuarantine(ctx context.Context, now time.Time) (int64, error)
+
+	GetTag(ctx context.Context, schemaID, name string) (*tag.Tag, error)
+	ListTags(ctx context.Context, schemaID string) ([]*tag.Tag, error)
+	PutTag(ctx context.Context, t *tag.Tag) error
+	DeleteTag(ctx context.Context, schemaID, name string) error
+	TagHistory(ctx context.Context, schemaID, name string, limit int) ([]*tag.Move, error)
 }
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+)
+
+func (s *store) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	s.log.Debug(ctx, "create api key")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Create(key).Error
+}
+
+func (s *store) GetAPIKey(ctx context.Context, hash string) (*auth.APIKey, error) {
+	s.log.Debug(ctx, "get api key")
+
+	key := &auth.APIKey{}
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Where("hash = ?", hash).Take(key).Error; err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (s *store) ListAPIKeys(ctx context.Context) ([]*auth.APIKey, error) {
+	s.log.Debug(ctx, "list api keys")
+
+	var keys []*auth.APIKey
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Order("id").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *store) DeleteAPIKey(ctx context.Context, id uint) error {
+	s.log.Debug(ctx, "delete api key")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	res := db.Where("id = ?", id).Delete(&auth.APIKey{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsondiff"
+)
+
+// appendOnlyAuditLog makes the audit log reject updates and deletes.
+const appendOnlyAuditLog = `
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+	FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+`
+
+func (s *store) ListAudit(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	s.log.Debug(ctx, "list audit log")
+
+	var entries []*audit.Entry
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	query := db.Where("namespace = ?", contexts.Namespace(ctx)).Order("id")
+
+	if filter.SchemaID != "" {
+		query = query.Where("schema_id = ?", filter.SchemaID)
+	}
+
+	query = withPrefixes(query, "schema_id", filter.Prefixes)
+
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// appendAudit records the mutation, and its event, within the transaction performing it. Unless set, the
+// actor, request ID and namespace are those of the context of the transaction.
+func appendAudit(tx *gorm.DB, entry *audit.Entry, previous, current string) error {
+	ctx := tx.Statement.Context
+
+	ops, err := jsondiff.Diff([]byte(previous), []byte(current))
+	if err != nil {
+		return err
+	}
+
+	diff, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	entry.Actor = contexts.Actor(ctx)
+	entry.RequestID = contexts.RequestID(ctx)
+	entry.Diff = diff
+
+	if entry.Namespace == "" {
+		entry.Namespace = contexts.Namespace(ctx)
+	}
+
+	if err = tx.Create(entry).Error; err != nil {
+		return err
+	}
+
+	return appendEvent(tx, entry)
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+// ImportSchemas imports all the schemas or none, overwriting those in the trash restores them.
+func (s *store) ImportSchemas(ctx context.Context, schemas []*schema.Schema, mode schema.ImportMode) (*schema.ImportResult, error) {
+	s.log.Debug(ctx, "import schemas")
+
+	result := &schema.ImportResult{
+		Created: []string{},
+		Updated: []string{},
+		Skipped: []string{},
+	}
+
+	namespace := contexts.Namespace(ctx)
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range schemas {
+			existing := &schema.Schema{}
+
+			err := tx.Unscoped().
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where(&schema.Schema{Namespace: namespace, SchemaID: model.SchemaID}).
+				Take(existing).Error
+
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if err = s.checkQuota(tx, namespace); err != nil {
+					return err
+				}
+
+				if err = createSchema(tx, importModel(namespace, model), model.Schema.String()); err != nil {
+					return err
+				}
+
+				result.Created = append(result.Created, model.SchemaID)
+
+				continue
+			case err != nil:
+				return err
+			}
+
+			switch mode {
+			case schema.ImportSkipExisting:
+				result.Skipped = append(result.Skipped, model.SchemaID)
+			case schema.ImportOverwrite:
+				if err = overwriteSchema(tx, existing, model); err != nil {
+					return err
+				}
+
+				result.Updated = append(result.Updated, model.SchemaID)
+			default:
+				return fmt.Errorf("%w: %s", exceptions.ErrAlreadyExists, model.SchemaID)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ExportSchemas is not bound to the query timeout, the export lasting as long as the client reads it.
+func (s *store) ExportSchemas(ctx context.Context, filter schema.Filter, fn func(*schema.Schema) error) error {
+	s.log.Debug(ctx, "export schemas")
+
+	query := s.db.WithContext(ctx).Model(&schema.Schema{}).Where("schemas.namespace = ?", contexts.Namespace(ctx))
+
+	rows, err := applyFilter(query, filter).
+		Select("schemas.*, schema_blobs.body").
+		Joins("JOIN schema_blobs ON schema_blobs.digest = schemas.digest").
+		Order("schema_id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &struct {
+			schema.Schema `gorm:"embedded"`
+			Body          string `gorm:"column:body"`
+		}{}
+
+		if err = s.db.ScanRows(rows, row); err != nil {
+			return err
+		}
+
+		body := datatypes.JSON(row.Body)
+		row.Schema.Schema = &body
+
+		if err = fn(&row.Schema); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func importModel(namespace string, model *schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Namespace: namespace,
+		SchemaID:  model.SchemaID,
+		Digest:    schema.Digest(model.Schema.String()),
+		Revision:  1,
+		Metadata: schema.Metadata{
+			Description: model.Description,
+			Owner:       model.Owner,
+			Labels:      model.Labels,
+		},
+	}
+}
+
+func overwriteSchema(tx *gorm.DB, existing, model *schema.Schema) error {
+	payload := model.Schema.String()
+	newDigest := schema.Digest(payload)
+
+	if err := putBlob(tx, newDigest, payload); err != nil {
+		return err
+	}
+
+	previous, err := getBlob(tx, existing.Digest)
+	if err != nil {
+		return err
+	}
+
+	updated := &schema.Schema{}
+
+	if err = tx.Unscoped().
+		Model(updated).
+		Clauses(clause.Returning{}).
+		Where("id = ?", existing.ID).
+		Updates(map[string]interface{}{
+			"digest":      newDigest,
+			"revision":    gorm.Expr("revision + 1"),
+			"description": model.Description,
+			"owner":       model.Owner,
+			"labels":      model.Labels,
+			"deleted_at":  nil,
+		}).Error; err != nil {
+		return err
+	}
+
+	if err = putVersion(tx, updated); err != nil {
+		return err
+	}
+
+	return appendAudit(tx, &audit.Entry{
+		SchemaID:       existing.SchemaID,
+		Action:         audit.ActionUpdate,
+		PreviousDigest: existing.Digest,
+		NewDigest:      newDigest,
+	}, previous.Body, payload)
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/stdlib"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+)
+
+const (
+	// eventsChannel is notified of every event committed, with the event ID as payload.
+	eventsChannel = "schema_events"
+	// eventsLock serializes the writers of events, so that they commit in the order of their IDs
+	// and a consumer resuming after an ID never misses an event committed late.
+	eventsLock = 0x736368656d61
+)
+
+var eventTypes = map[string]string{
+	audit.ActionCreate:  event.TypeCreated,
+	audit.ActionUpdate:  event.TypeUpdated,
+	audit.ActionDelete:  event.TypeDeleted,
+	audit.ActionRestore: event.TypeRestored,
+	audit.ActionPurge:   event.TypePurged,
+
+	audit.ActionActivate:  event.TypeActivated,
+	audit.ActionDeprecate: event.TypeDeprecated,
+	audit.ActionRetire:    event.TypeRetired,
+}
+
+func (s *store) ListEvents(ctx context.Context, after uint64, limit int) ([]*event.Event, error) {
+	s.log.Debug(ctx, "list events")
+
+	var events []*event.Event
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Where("id > ?", after).Order("id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *store) LastEventID(ctx context.Context) (uint64, error) {
+	s.log.Debug(ctx, "get last event id")
+
+	var id uint64
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Model(&event.Event{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Listen calls fn whenever an event is committed, by any replica, until ctx is done or the connection is lost.
+func (s *store) Listen(ctx context.Context, fn func()) error {
+	s.log.Debug(ctx, "listen for events")
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var listenErr error
+
+	_ = conn.Raw(func(dc interface{}) error {
+		c, ok := dc.(*stdlib.Conn)
+		if !ok {
+			listenErr = fmt.Errorf("unexpected driver connection %T", dc)
+
+			return nil
+		}
+
+		if _, listenErr = c.Conn().Exec(ctx, "LISTEN "+eventsChannel); listenErr != nil {
+			return driver.ErrBadConn
+		}
+
+		for {
+			if _, listenErr = c.Conn().WaitForNotification(ctx); listenErr != nil {
+				// the connection is still listening, it must not go back to the pool.
+				return driver.ErrBadConn
+			}
+
+			fn()
+		}
+	})
+
+	if errors.Is(listenErr, context.Canceled) {
+		return nil
+	}
+
+	return listenErr
+}
+
+// appendEvent publishes the change and queues its webhook deliveries within the transaction performing it.
+func appendEvent(tx *gorm.DB, entry *audit.Entry) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", eventsLock).Error; err != nil {
+		return err
+	}
+
+	e := &event.Event{
+		Type:      eventTypes[entry.Action],
+		Namespace: entry.Namespace,
+		SchemaID:  entry.SchemaID,
+		Digest:    entry.NewDigest,
+	}
+
+	if err := tx.Create(e).Error; err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err = enqueueDeliveries(tx, e.Type, e.SchemaID, payload); err != nil {
+		return err
+	}
+
+	return tx.Exec("SELECT pg_notify(?, ?)", eventsChannel, fmt.Sprint(e.ID)).Error
+}
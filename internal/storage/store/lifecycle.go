@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+var lifecycleActions = map[string]string{
+	schema.StateActive:     audit.ActionActivate,
+	schema.StateDeprecated: audit.ActionDeprecate,
+	schema.StateRetired:    audit.ActionRetire,
+}
+
+// UpdateLifecycle moves the schema to lifecycle, provided it is still in the previous state.
+func (s *store) UpdateLifecycle(ctx context.Context, schemaID string, previous, lifecycle schema.Lifecycle) (*schema.Schema, error) {
+	s.log.Debug(ctx, "update schema lifecycle")
+
+	namespace := contexts.Namespace(ctx)
+	model := &schema.Schema{}
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(model).
+			Clauses(clause.Returning{}).
+			Where("namespace = ? AND schema_id = ? AND state = ?", namespace, schemaID, previous.State).
+			Updates(map[string]interface{}{
+				"state":         lifecycle.State,
+				"deprecated_at": lifecycle.DeprecatedAt,
+				"sunset":        lifecycle.Sunset,
+				"successor":     lifecycle.Successor,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return notFoundOrModified(tx, namespace, schemaID)
+		}
+
+		before, err := json.Marshal(previous)
+		if err != nil {
+			return err
+		}
+
+		after, err := json.Marshal(lifecycle)
+		if err != nil {
+			return err
+		}
+
+		return appendAudit(tx, &audit.Entry{
+			SchemaID:       schemaID,
+			Action:         lifecycleActions[lifecycle.State],
+			PreviousDigest: model.Digest,
+			NewDigest:      model.Digest,
+		}, string(before), string(after))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
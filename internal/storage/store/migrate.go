@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jcs"
+)
+
+// migrateSchemaBodies moves the inline bodies of the schemas into blobs.
+func (s *store) migrateSchemaBodies(ctx context.Context) error {
+	db := s.db.WithContext(ctx)
+
+	if !db.Migrator().HasColumn(&schema.Schema{}, "schema") {
+		return nil
+	}
+
+	s.log.Info(ctx, "migrating schema bodies to content addressed blobs")
+
+	var rows []struct {
+		SchemaID string
+		Revision int
+		Body     string
+	}
+
+	if err := db.Table("schemas").
+		Select("schema_id, revision, schema::text AS body").
+		Where("schema IS NOT NULL").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			body, err := jcs.Canonicalize([]byte(row.Body))
+			if err != nil {
+				return err
+			}
+
+			model := &schema.Schema{
+				SchemaID: row.SchemaID,
+				Digest:   schema.Digest(string(body)),
+				Revision: row.Revision,
+			}
+
+			if err = putBlob(tx, model.Digest, string(body)); err != nil {
+				return err
+			}
+
+			if err = tx.Model(&schema.Schema{}).
+				Where("schema_id = ?", model.SchemaID).
+				UpdateColumn("digest", model.Digest).Error; err != nil {
+				return err
+			}
+
+			if err = putVersion(tx, model); err != nil {
+				return err
+			}
+		}
+
+		return tx.Migrator().DropColumn(&schema.Schema{}, "schema")
+	})
+}
+
+// legacyIndexes were replaced by indexes prefixed with the namespace.
+var legacyIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	{&schema.Schema{}, "idx_schemas_schema_id"},
+	{&schema.Version{}, "idx_schema_versions_schema_id_version"},
+	{&audit.Entry{}, "idx_audit_log_schema_id"},
+	{&validation.Result{}, "idx_validation_results_schema_id_created_at"},
+	{&quarantine.Entry{}, "idx_quarantine_schema_id"},
+	{&tag.Move{}, "idx_schema_tag_moves_schema_id_tag"},
+}
+
+// namespacedKeys are the primary keys the namespace was prepended to.
+var namespacedKeys = map[string]string{
+	"schema_tags":         "namespace, schema_id, tag",
+	"quarantine_settings": "namespace, schema_id",
+}
+
+// migrateNamespaces drops the indexes and primary keys of the tables created before namespaces,
+// which kept schema IDs unique across all namespaces. The namespace columns are added by the automigration,
+// the existing rows falling in the default namespace.
+func (s *store) migrateNamespaces(ctx context.Context) error {
+	db := s.db.WithContext(ctx)
+
+	for _, idx := range legacyIndexes {
+		if !db.Migrator().HasIndex(idx.model, idx.name) {
+			continue
+		}
+
+		s.log.Info(ctx, "dropping index "+idx.name)
+
+		if err := db.Migrator().DropIndex(idx.model, idx.name); err != nil {
+			return err
+		}
+	}
+
+	for table, columns := range namespacedKeys {
+		var namespaced int64
+
+		if err := db.Raw(
+			"SELECT count(*) FROM information_schema.table_constraints tc "+
+				"JOIN information_schema.key_column_usage kcu ON kcu.constraint_name = tc.constraint_name AND kcu.table_name = tc.table_name "+
+				"WHERE tc.table_name = ? AND tc.constraint_type = 'PRIMARY KEY' AND kcu.column_name = 'namespace'",
+			table,
+		).Scan(&namespaced).Error; err != nil {
+			return err
+		}
+
+		if namespaced > 0 {
+			continue
+		}
+
+		s.log.Info(ctx, "adding the namespace to the primary key of "+table)
+
+		if err := db.Exec(fmt.Sprintf(
+			"ALTER TABLE %[1]s DROP CONSTRAINT %[1]s_pkey, ADD PRIMARY KEY (%[2]s)", table, columns,
+		)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+func (s *store) GetQuarantineSettings(ctx context.Context, schemaID string) (*quarantine.Settings, error) {
+	s.log.Debug(ctx, "get quarantine settings")
+
+	settings := &quarantine.Settings{}
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Where("namespace = ? AND schema_id = ?", contexts.Namespace(ctx), schemaID).Take(settings).Error; err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (s *store) PutQuarantineSettings(ctx context.Context, settings *quarantine.Settings) error {
+	s.log.Debug(ctx, "put quarantine settings")
+
+	settings.Namespace = contexts.Namespace(ctx)
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "namespace"}, {Name: "schema_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "redact", "updated_at"}),
+	}).Create(settings).Error
+}
+
+// QuarantinePayload stores the entry, evicting the oldest entries of the schema beyond maxEntries.
+func (s *store) QuarantinePayload(ctx context.Context, entry *quarantine.Entry, maxEntries int) error {
+	s.log.Debug(ctx, "quarantine payload")
+
+	entry.Namespace = contexts.Namespace(ctx)
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(
+			"DELETE FROM quarantine WHERE namespace = @ns AND schema_id = @id AND id NOT IN "+
+				"(SELECT id FROM quarantine WHERE namespace = @ns AND schema_id = @id ORDER BY id DESC LIMIT @max)",
+			map[string]interface{}{"ns": entry.Namespace, "id": entry.SchemaID, "max": maxEntries},
+		).Error
+	})
+}
+
+// ListQuarantine returns the entries of the schema not expired at now, most recent first.
+func (s *store) ListQuarantine(ctx context.Context, schemaID string, now time.Time, limit int) ([]*quarantine.Entry, error) {
+	s.log.Debug(ctx, "list quarantine")
+
+	var entries []*quarantine.Entry
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	query := db.Where("namespace = ? AND schema_id = ? AND expires_at > ?", contexts.Namespace(ctx), schemaID, now).Order("id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *store) PurgeQuarantine(ctx context.Context, now time.Time) (int64, error) {
+	s.log.Debug(ctx, "purge quarantine")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	res := db.Where("expires_at <= ?", now).Delete(&quarantine.Entry{})
+
+	return res.RowsAffected, res.Error
+}
+
+func (s *store) GetSchemaVersion(ctx context.Context, schemaID string, version int) (*schema.Schema, error) {
+	s.log.Debug(ctx, "get schema version")
+
+	v := &schema.Version{}
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Where("namespace = ? AND schema_id = ? AND version = ?", contexts.Namespace(ctx), schemaID, version).Take(v).Error; err != nil {
+		return nil, err
+	}
+
+	blob, err := getBlob(db, v.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	body := datatypes.JSON(blob.Body)
+
+	return &schema.Schema{
+		Namespace: v.Namespace,
+		SchemaID:  schemaID,
+		Schema:    &body,
+		Digest:    v.Digest,
+		Revision:  v.Version,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/ratelimit"
+)
+
+// takeToken refills the bucket, then takes a token from it if there is one. The update being a single
+// statement, the replicas taking tokens from the same bucket are serialized by its row lock.
+const takeToken = `
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES (@key, @burst - 1, true, clock_timestamp())
+ON CONFLICT (key) DO UPDATE SET
+	allowed = LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * @rate) >= 1,
+	tokens = LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * @rate) -
+		CASE WHEN LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * @rate) >= 1 THEN 1 ELSE 0 END,
+	updated_at = clock_timestamp()
+RETURNING key, tokens, allowed, updated_at`
+
+func (s *store) TakeToken(ctx context.Context, key string, rate float64, burst int) (*ratelimit.Bucket, error) {
+	s.log.Debug(ctx, "take rate limit token")
+
+	b := &ratelimit.Bucket{}
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	err := db.Raw(takeToken, sql.Named("key", key), sql.Named("rate", rate), sql.Named("burst", burst)).Scan(b).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (s *store) PurgeRateLimits(ctx context.Context, before time.Time) (int64, error) {
+	s.log.Debug(ctx, "purge rate limits")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	res := db.Where("updated_at < ?", before).Delete(&ratelimit.Bucket{})
+
+	return res.RowsAffected, res.Error
+}
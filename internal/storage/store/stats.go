@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+func (s *store) RecordValidations(ctx context.Context, results []*validation.Result) error {
+	s.log.Debug(ctx, "record validations")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.CreateInBatches(results, 100).Error
+}
+
+// ValidationStats sums up the validations within every window, the top failing paths within the last one.
+func (s *store) ValidationStats(ctx context.Context, schemaID string, now time.Time, windows []time.Duration, top int) (*validation.Stats, error) {
+	s.log.Debug(ctx, "get validation stats")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	res := &validation.Stats{SchemaID: schemaID}
+	namespace := contexts.Namespace(ctx)
+
+	for _, d := range windows {
+		w := &validation.Window{Duration: d, Window: d.String()}
+
+		if err := db.Model(&validation.Result{}).
+			Select("count(*) AS total, count(*) FILTER (WHERE NOT valid) AS failed, COALESCE(avg(latency_us), 0) AS avg_latency").
+			Where("namespace = ? AND schema_id = ? AND created_at >= ?", namespace, schemaID, now.Add(-d)).
+			Scan(w).Error; err != nil {
+			return nil, err
+		}
+
+		if w.Total > 0 {
+			w.FailureRate = float64(w.Failed) / float64(w.Total)
+		}
+
+		res.Windows = append(res.Windows, w)
+	}
+
+	if len(windows) == 0 {
+		return res, nil
+	}
+
+	if err := db.Model(&validation.Result{}).
+		Select("instance_path, keyword, count(*) AS count").
+		Where("namespace = ? AND schema_id = ? AND NOT valid AND created_at >= ?", namespace, schemaID, now.Add(-windows[len(windows)-1])).
+		Group("instance_path, keyword").
+		Order("count DESC, instance_path").
+		Limit(top).
+		Scan(&res.TopFailingPaths).Error; err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *store) PurgeValidations(ctx context.Context, before time.Time) (int64, error) {
+	s.log.Debug(ctx, "purge validations")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	res := db.Where("created_at < ?", before).Delete(&validation.Result{})
+
+	return res.RowsAffected, res.Error
+}
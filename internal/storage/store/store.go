@@ -2,16 +2,29 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/datatypes"
 	driver "gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/KarolosLykos/json-validation-service/internal/config"
 	"github.com/KarolosLykos/json-validation-service/internal/logger"
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+	"github.com/KarolosLykos/json-validation-service/internal/models/event"
+	"github.com/KarolosLykos/json-validation-service/internal/models/quarantine"
+	"github.com/KarolosLykos/json-validation-service/internal/models/ratelimit"
 	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/models/validation"
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
 	"github.com/KarolosLykos/json-validation-service/internal/storage"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
 	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
 )
 
@@ -19,38 +32,113 @@ type store struct {
 	db  *gorm.DB
 	cfg *config.Config
 	log logger.Logger
+
+	// dial and after are replaced by the tests.
+	dial  func(dsn string) (*gorm.DB, error)
+	after func(d time.Duration) <-chan time.Time
 }
 
 func New(cfg *config.Config, log logger.Logger) storage.Storage {
 	return &store{
 		cfg: cfg,
 		log: log,
+		dial: func(dsn string) (*gorm.DB, error) {
+			return gorm.Open(driver.Open(dsn), &gorm.Config{})
+		},
+		after: time.After,
 	}
 }
 
 func (s *store) Connect(ctx context.Context) (storage.Storage, error) {
 	s.log.Debug(ctx, "initialize db session")
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s",
+	dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s statement_timeout=%d",
 		s.cfg.Storage.HOST,
 		s.cfg.Storage.PORT,
 		s.cfg.Storage.User,
 		s.cfg.Storage.Name,
 		s.cfg.Storage.Password,
+		s.cfg.Storage.StatementTimeout.Milliseconds(),
 	)
 
-	db, err := gorm.Open(driver.Open(dsn), &gorm.Config{})
+	db, err := s.open(ctx, dsn)
 	if err != nil {
 		s.log.Error(ctx, fmt.Errorf("%v:%w", exceptions.ErrConnectingToDatabase, err), "could not initialise db session")
 
 		return nil, fmt.Errorf("%v:%w", exceptions.ErrConnectingToDatabase, err)
 	}
 
+	sql, err := db.DB()
+	if err != nil {
+		s.log.Error(ctx, fmt.Errorf("%v:%w", exceptions.ErrGetDB, err), "could not get database handle")
+
+		return nil, fmt.Errorf("%v:%w", exceptions.ErrGetDB, err)
+	}
+
+	sql.SetMaxOpenConns(s.cfg.Storage.MaxOpenConns)
+	sql.SetMaxIdleConns(s.cfg.Storage.MaxIdleConns)
+	sql.SetConnMaxLifetime(s.cfg.Storage.ConnMaxLifetime)
+
+	if err = s.instrument(db, sql); err != nil {
+		s.log.Error(ctx, err, "could not instrument database")
+
+		return nil, err
+	}
+
 	s.db = db
 
 	return s, nil
 }
 
+// open retries with exponential backoff until the database is ready.
+func (s *store) open(ctx context.Context, dsn string) (*gorm.DB, error) {
+	backoff := s.cfg.Storage.ConnectBackoff
+
+	for attempt := 0; ; attempt++ {
+		db, err := s.dial(dsn)
+		if err == nil {
+			return db, nil
+		}
+
+		// the session is returned along with the failed ping, its pool must not leak.
+		closeDB(db)
+
+		if attempt >= s.cfg.Storage.ConnectRetries {
+			return nil, err
+		}
+
+		s.log.Error(ctx, err, fmt.Sprintf("database not ready, retrying in %s", backoff))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.after(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.cfg.Storage.ConnectMaxBackoff {
+			backoff = s.cfg.Storage.ConnectMaxBackoff
+		}
+	}
+}
+
+func closeDB(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+
+	if sql, err := db.DB(); err == nil {
+		_ = sql.Close()
+	}
+}
+
+// conn returns a session bound to ctx, limited by the configured query timeout.
+func (s *store) conn(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Storage.QueryTimeout)
+
+	return s.db.WithContext(ctx), cancel
+}
+
 func (s *store) Shutdown(ctx context.Context) error {
 	s.log.Debug(ctx, "close database")
 
@@ -73,36 +161,348 @@ func (s *store) Shutdown(ctx context.Context) error {
 func (s *store) Initialize(ctx context.Context) error {
 	s.log.Debug(ctx, "initialize database")
 
-	if err := s.db.AutoMigrate(schema.Schema{}); err != nil {
+	if err := s.db.WithContext(ctx).AutoMigrate(schema.Schema{}, schema.Blob{}, schema.Version{}, audit.Entry{}, event.Event{}, webhook.Webhook{}, webhook.Delivery{}, validation.Result{},
+		quarantine.Settings{}, quarantine.Entry{}, tag.Tag{}, tag.Move{}, auth.APIKey{}, ratelimit.Bucket{}); err != nil {
 		s.log.Error(ctx, fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err), "could not automigrate")
 
 		return fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err)
 	}
 
+	if err := s.db.WithContext(ctx).Exec(appendOnlyAuditLog).Error; err != nil {
+		s.log.Error(ctx, fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err), "could not protect audit log")
+
+		return fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err)
+	}
+
+	if err := s.migrateNamespaces(ctx); err != nil {
+		s.log.Error(ctx, fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err), "could not migrate namespaces")
+
+		return fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err)
+	}
+
+	if err := s.migrateSchemaBodies(ctx); err != nil {
+		s.log.Error(ctx, fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err), "could not migrate schema bodies")
+
+		return fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err)
+	}
+
+	if err := s.db.WithContext(ctx).Exec(blobForeignKey).Error; err != nil {
+		s.log.Error(ctx, fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err), "could not reference blobs")
+
+		return fmt.Errorf("%v:%w", exceptions.ErrInitializeDatabase, err)
+	}
+
 	return nil
 }
 
-func (s *store) CreateSchema(ctx context.Context, schemaID, schemaPayload string) error {
+func (s *store) CreateSchema(ctx context.Context, schemaID, schemaPayload string, meta schema.Metadata) error {
 	s.log.Debug(ctx, "upload schema")
 
-	schemaJSON := datatypes.JSON(schemaPayload)
-
 	model := &schema.Schema{
-		SchemaID: schemaID,
-		Schema:   &schemaJSON,
+		Namespace: contexts.Namespace(ctx),
+		SchemaID:  schemaID,
+		Digest:    schema.Digest(schemaPayload),
+		Revision:  1,
+		Metadata:  meta,
 	}
 
-	return s.db.Create(model).Error
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkQuota(tx, model.Namespace); err != nil {
+			return err
+		}
+
+		return createSchema(tx, model, schemaPayload)
+	})
 }
 
-func (s *store) GetSchema(ctx context.Context, schemaID string) (string, error) {
+func (s *store) GetSchema(ctx context.Context, schemaID string) (*schema.Schema, error) {
 	s.log.Debug(ctx, "download schema")
 
 	model := &schema.Schema{}
 
-	if err := s.db.Where(&schema.Schema{SchemaID: schemaID}).Take(model).Error; err != nil {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Where(&schema.Schema{Namespace: contexts.Namespace(ctx), SchemaID: schemaID}).Take(model).Error; err != nil {
+		return nil, err
+	}
+
+	blob, err := getBlob(db, model.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaJSON := datatypes.JSON(blob.Body)
+	model.Schema = &schemaJSON
+
+	return model, nil
+}
+
+// GetBlob returns the body with the digest, provided a schema of the namespace of the request points at it.
+func (s *store) GetBlob(ctx context.Context, digest string) (string, error) {
+	s.log.Debug(ctx, "download schema by digest")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	referenced := db.Model(&schema.Version{}).
+		Select("1").
+		Where("schema_versions.digest = schema_blobs.digest AND schema_versions.namespace = ?", contexts.Namespace(ctx))
+
+	blob := &schema.Blob{}
+
+	if err := db.Where("digest = ? AND EXISTS (?)", digest, referenced).Take(blob).Error; err != nil {
 		return "", err
 	}
 
-	return model.Schema.String(), nil
+	return blob.Body, nil
+}
+
+// UpdateSchema points the schema at the new body, only if its current digest still matches the given one.
+func (s *store) UpdateSchema(ctx context.Context, schemaID, payload, digest string) (*schema.Schema, error) {
+	s.log.Debug(ctx, "update schema")
+
+	model := &schema.Schema{}
+	namespace := contexts.Namespace(ctx)
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		newDigest := schema.Digest(payload)
+
+		// the same body leaves the schema as it is, rather than making a new version of it.
+		if newDigest == digest {
+			err := tx.Where("namespace = ? AND schema_id = ? AND digest = ?", namespace, schemaID, digest).Take(model).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return notFoundOrModified(tx, namespace, schemaID)
+			}
+
+			return err
+		}
+
+		if err := putBlob(tx, newDigest, payload); err != nil {
+			return err
+		}
+
+		// a blob is missing only if the given digest never pointed at a schema.
+		previous, err := getBlob(tx, digest)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFoundOrModified(tx, namespace, schemaID)
+		} else if err != nil {
+			return err
+		}
+
+		res := tx.Model(model).
+			Clauses(clause.Returning{}).
+			Where("namespace = ? AND schema_id = ? AND digest = ?", namespace, schemaID, digest).
+			Updates(map[string]interface{}{
+				"digest":   newDigest,
+				"revision": gorm.Expr("revision + 1"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return notFoundOrModified(tx, namespace, schemaID)
+		}
+
+		if err = putVersion(tx, model); err != nil {
+			return err
+		}
+
+		return appendAudit(tx, &audit.Entry{
+			SchemaID:       schemaID,
+			Action:         audit.ActionUpdate,
+			PreviousDigest: digest,
+			NewDigest:      newDigest,
+		}, previous.Body, payload)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schemaJSON := datatypes.JSON(payload)
+	model.Schema = &schemaJSON
+
+	return model, nil
+}
+
+// DeleteSchema moves the schema to the trash, only if its current digest still matches the given one.
+func (s *store) DeleteSchema(ctx context.Context, schemaID, digest string) error {
+	s.log.Debug(ctx, "delete schema")
+
+	namespace := contexts.Namespace(ctx)
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("namespace = ? AND schema_id = ? AND digest = ?", namespace, schemaID, digest).Delete(&schema.Schema{})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return notFoundOrModified(tx, namespace, schemaID)
+		}
+
+		previous, err := getBlob(tx, digest)
+		if err != nil {
+			return err
+		}
+
+		return appendAudit(tx, &audit.Entry{
+			SchemaID:       schemaID,
+			Action:         audit.ActionDelete,
+			PreviousDigest: digest,
+		}, previous.Body, "")
+	})
+}
+
+func (s *store) ListSchemas(ctx context.Context, filter schema.Filter) ([]*schema.Schema, error) {
+	s.log.Debug(ctx, "list schemas")
+
+	var models []*schema.Schema
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := applyFilter(db.Where("namespace = ?", contexts.Namespace(ctx)), filter).Order("schema_id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return models, nil
+}
+
+func applyFilter(db *gorm.DB, filter schema.Filter) *gorm.DB {
+	if filter.Owner != "" {
+		db = db.Where("owner = ?", filter.Owner)
+	}
+
+	if len(filter.Labels) > 0 {
+		db = db.Where("labels @> ?", filter.Labels)
+	}
+
+	return withPrefixes(db, "schemas.schema_id", filter.Prefixes)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// withPrefixes keeps the rows whose column starts with one of the prefixes, if any.
+func withPrefixes(db *gorm.DB, column string, prefixes []string) *gorm.DB {
+	if len(prefixes) == 0 {
+		return db
+	}
+
+	conditions := make([]string, len(prefixes))
+	args := make([]interface{}, len(prefixes))
+
+	for i, prefix := range prefixes {
+		conditions[i] = column + ` LIKE ? ESCAPE '\'`
+		args[i] = likeEscaper.Replace(prefix) + "%"
+	}
+
+	return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// notFoundOrModified tells a missing schema from a lost concurrent update.
+func notFoundOrModified(db *gorm.DB, namespace, schemaID string) error {
+	if err := db.Where(&schema.Schema{Namespace: namespace, SchemaID: schemaID}).Take(&schema.Schema{}).Error; err != nil {
+		return err
+	}
+
+	return exceptions.ErrPreconditionFailed
+}
+
+func createSchema(tx *gorm.DB, model *schema.Schema, payload string) error {
+	if err := putBlob(tx, model.Digest, payload); err != nil {
+		return err
+	}
+
+	if err := tx.Create(model).Error; err != nil {
+		return err
+	}
+
+	if err := putVersion(tx, model); err != nil {
+		return err
+	}
+
+	return appendAudit(tx, &audit.Entry{
+		SchemaID:  model.SchemaID,
+		Action:    audit.ActionCreate,
+		NewDigest: model.Digest,
+	}, "", payload)
+}
+
+// blobForeignKey keeps the blobs referenced by a version from being collected, see PurgeSchemas.
+const blobForeignKey = `
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_schema_versions_blob') THEN
+		ALTER TABLE schema_versions ADD CONSTRAINT fk_schema_versions_blob
+			FOREIGN KEY (digest) REFERENCES schema_blobs (digest);
+	END IF;
+END $$;
+`
+
+// putBlob stores the body unless already stored, locking the blob either way so that it cannot be collected
+// before the version referencing it is recorded.
+func putBlob(tx *gorm.DB, digest, body string) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "digest"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"digest": gorm.Expr("EXCLUDED.digest")}),
+	}).Create(&schema.Blob{Digest: digest, Body: body}).Error
+}
+
+func getBlob(db *gorm.DB, digest string) (*schema.Blob, error) {
+	blob := &schema.Blob{}
+
+	if err := db.Where(&schema.Blob{Digest: digest}).Take(blob).Error; err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+func putVersion(tx *gorm.DB, model *schema.Schema) error {
+	return tx.Create(&schema.Version{
+		Namespace: model.Namespace,
+		SchemaID:  model.SchemaID,
+		Version:   model.Revision,
+		Digest:    model.Digest,
+	}).Error
+}
+
+// checkQuota fails when the namespace holds as many schemas as its quota allows, those in the trash included.
+func (s *store) checkQuota(tx *gorm.DB, namespace string) error {
+	quota := s.cfg.Namespace.MaxSchemas
+	if q, ok := s.cfg.Namespace.Quotas[namespace]; ok {
+		quota = q
+	}
+
+	if quota <= 0 {
+		return nil
+	}
+
+	// serializes the creations within the namespace, so that concurrent ones cannot exceed the quota.
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "namespace:"+namespace).Error; err != nil {
+		return err
+	}
+
+	var count int64
+
+	if err := tx.Unscoped().Model(&schema.Schema{}).Where("namespace = ?", namespace).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count >= int64(quota) {
+		return fmt.Errorf("%w: namespace %s holds %d schemas", exceptions.ErrQuotaExceeded, namespace, quota)
+	}
+
+	return nil
 }
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	driver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+	"github.com/KarolosLykos/json-validation-service/internal/logger/logruslog"
+)
+
+var errNotReady = errors.New("database not ready")
+
+func TestStore_Open(t *testing.T) {
+	tc := []struct {
+		name     string
+		failures int
+		retries  int
+		attempts int
+		waits    []time.Duration
+		err      error
+	}{
+		{
+			name:     "ready",
+			retries:  3,
+			attempts: 1,
+		},
+		{
+			name:     "ready after retries",
+			failures: 4,
+			retries:  5,
+			attempts: 5,
+			waits:    []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second},
+		},
+		{
+			name:     "retries exhausted",
+			failures: 10,
+			retries:  2,
+			attempts: 3,
+			waits:    []time.Duration{time.Second, 2 * time.Second},
+			err:      errNotReady,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			s := helperStore(tt.retries)
+
+			var (
+				pools []*sql.DB
+				waits []time.Duration
+			)
+
+			s.dial = func(string) (*gorm.DB, error) {
+				db, pool := helperSession(t)
+				pools = append(pools, pool)
+
+				if len(pools) <= tt.failures {
+					return db, errNotReady
+				}
+
+				return db, nil
+			}
+			s.after = func(d time.Duration) <-chan time.Time {
+				waits = append(waits, d)
+
+				return time.After(0)
+			}
+
+			db, err := s.open(context.Background(), "")
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, db)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, db)
+			}
+
+			assert.Len(t, pools, tt.attempts)
+			assert.Equal(t, tt.waits, waits)
+
+			// the pools of the failed attempts are closed, the one returned is kept open.
+			for i, pool := range pools {
+				pingErr := pool.Ping()
+				closed := pingErr != nil && pingErr.Error() == "sql: database is closed"
+				assert.Equal(t, tt.err != nil || i < len(pools)-1, closed, "attempt %d", i)
+			}
+		})
+	}
+}
+
+func TestStore_Open_Cancelled(t *testing.T) {
+	s := helperStore(3)
+	s.dial = func(string) (*gorm.DB, error) {
+		return nil, errNotReady
+	}
+	s.after = func(time.Duration) <-chan time.Time {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.open(ctx, "")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func helperStore(retries int) *store {
+	cfg := &config.Config{Storage: config.Storage{
+		ConnectRetries:    retries,
+		ConnectBackoff:    time.Second,
+		ConnectMaxBackoff: 5 * time.Second,
+	}}
+
+	return New(cfg, logruslog.DefaultLogger(cfg)).(*store)
+}
+
+// helperSession returns a session over a pool which never connects, the way gorm returns one along with
+// a failed ping.
+func helperSession(t *testing.T) (*gorm.DB, *sql.DB) {
+	t.Helper()
+
+	pool, err := sql.Open("pgx", "host=127.0.0.1 port=1 connect_timeout=1")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = pool.Close() })
+
+	db, err := gorm.Open(driver.New(driver.Config{Conn: pool}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	return db, pool
+}
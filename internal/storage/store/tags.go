@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/tag"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+func (s *store) GetTag(ctx context.Context, schemaID, name string) (*tag.Tag, error) {
+	s.log.Debug(ctx, "get tag")
+
+	t := &tag.Tag{}
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Where("namespace = ? AND schema_id = ? AND tag = ?", contexts.Namespace(ctx), schemaID, name).Take(t).Error; err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (s *store) ListTags(ctx context.Context, schemaID string) ([]*tag.Tag, error) {
+	s.log.Debug(ctx, "list tags")
+
+	var tags []*tag.Tag
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Where("namespace = ? AND schema_id = ?", contexts.Namespace(ctx), schemaID).Order("tag").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *store) PutTag(ctx context.Context, t *tag.Tag) error {
+	s.log.Debug(ctx, "put tag")
+
+	t.Namespace = contexts.Namespace(ctx)
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		prev := &tag.Tag{}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("namespace = ? AND schema_id = ? AND tag = ?", t.Namespace, t.SchemaID, t.Tag).
+			Take(prev).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "namespace"}, {Name: "schema_id"}, {Name: "tag"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target", "version", "updated_at"}),
+		}).Create(t).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&tag.Move{
+			Namespace:       t.Namespace,
+			SchemaID:        t.SchemaID,
+			Tag:             t.Tag,
+			Target:          t.Target,
+			Version:         t.Version,
+			PreviousTarget:  prev.Target,
+			PreviousVersion: prev.Version,
+		}).Error
+	})
+}
+
+func (s *store) DeleteTag(ctx context.Context, schemaID, name string) error {
+	s.log.Debug(ctx, "delete tag")
+
+	namespace := contexts.Namespace(ctx)
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		prev := &tag.Tag{}
+
+		if err := tx.Clauses(clause.Returning{}).
+			Where("namespace = ? AND schema_id = ? AND tag = ?", namespace, schemaID, name).
+			Delete(prev).Error; err != nil {
+			return err
+		}
+
+		if prev.Target == "" {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(&tag.Move{
+			Namespace:       namespace,
+			SchemaID:        schemaID,
+			Tag:             name,
+			PreviousTarget:  prev.Target,
+			PreviousVersion: prev.Version,
+			Deleted:         true,
+		}).Error
+	})
+}
+
+// TagHistory returns the moves of the tag, most recent first.
+func (s *store) TagHistory(ctx context.Context, schemaID, name string, limit int) ([]*tag.Move, error) {
+	s.log.Debug(ctx, "tag history")
+
+	var moves []*tag.Move
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	query := db.Where("namespace = ? AND schema_id = ? AND tag = ?", contexts.Namespace(ctx), schemaID, name).Order("id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&moves).Error; err != nil {
+		return nil, err
+	}
+
+	return moves, nil
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/audit"
+	"github.com/KarolosLykos/json-validation-service/internal/models/schema"
+	"github.com/KarolosLykos/json-validation-service/internal/utils/contexts"
+)
+
+func (s *store) ListTrash(ctx context.Context) ([]*schema.Schema, error) {
+	s.log.Debug(ctx, "list trash")
+
+	var models []*schema.Schema
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Unscoped().
+		Where("namespace = ? AND deleted_at IS NOT NULL", contexts.Namespace(ctx)).
+		Order("deleted_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return models, nil
+}
+
+func (s *store) RestoreSchema(ctx context.Context, schemaID string) error {
+	s.log.Debug(ctx, "restore schema")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		model := &schema.Schema{}
+
+		res := tx.Unscoped().
+			Model(model).
+			Clauses(clause.Returning{}).
+			Where("namespace = ? AND schema_id = ? AND deleted_at IS NOT NULL", contexts.Namespace(ctx), schemaID).
+			Update("deleted_at", nil)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		current, err := getBlob(tx, model.Digest)
+		if err != nil {
+			return err
+		}
+
+		return appendAudit(tx, &audit.Entry{
+			SchemaID:  schemaID,
+			Action:    audit.ActionRestore,
+			NewDigest: model.Digest,
+		}, "", current.Body)
+	})
+}
+
+// PurgeSchemas removes the schemas deleted before the time, their versions and the blobs left unreferenced.
+func (s *store) PurgeSchemas(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	s.log.Debug(ctx, "purge schemas")
+
+	var expired []*schema.Schema
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at < ?", deletedBefore).
+			Find(&expired).Error; err != nil {
+			return err
+		}
+
+		if len(expired) == 0 {
+			return nil
+		}
+
+		for _, model := range expired {
+			if err := appendAudit(tx, &audit.Entry{
+				Namespace:      model.Namespace,
+				SchemaID:       model.SchemaID,
+				Action:         audit.ActionPurge,
+				PreviousDigest: model.Digest,
+			}, "", ""); err != nil {
+				return err
+			}
+
+			if err := tx.Where("namespace = ? AND schema_id = ?", model.Namespace, model.SchemaID).
+				Delete(&schema.Version{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Delete(&expired).Error; err != nil {
+			return err
+		}
+
+		// the blobs being stored by a concurrent transaction are locked, see putBlob, and skipped.
+		return tx.Exec(
+			"DELETE FROM schema_blobs WHERE digest IN (" +
+				"SELECT digest FROM schema_blobs WHERE NOT EXISTS " +
+				"(SELECT 1 FROM schema_versions WHERE schema_versions.digest = schema_blobs.digest) " +
+				"FOR UPDATE SKIP LOCKED)",
+		).Error
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(expired)), nil
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/webhook"
+)
+
+// enqueueDeliveriesSQL queues a delivery of an event to every webhook subscribed to its type.
+const enqueueDeliveriesSQL = `
+INSERT INTO webhook_deliveries (webhook_id, event_type, schema_id, payload, status, attempts, next_attempt_at, created_at, updated_at)
+SELECT id, @type, @schema_id, CAST(@payload AS jsonb), @status, 0, now(), now(), now()
+FROM webhooks
+WHERE jsonb_array_length(events) = 0 OR jsonb_exists(events, @type)
+`
+
+func (s *store) CreateWebhook(ctx context.Context, wh *webhook.Webhook) error {
+	s.log.Debug(ctx, "create webhook")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Create(wh).Error
+}
+
+func (s *store) GetWebhook(ctx context.Context, id uint) (*webhook.Webhook, error) {
+	s.log.Debug(ctx, "get webhook")
+
+	wh := &webhook.Webhook{}
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Where("id = ?", id).Take(wh).Error; err != nil {
+		return nil, err
+	}
+
+	return wh, nil
+}
+
+func (s *store) ListWebhooks(ctx context.Context) ([]*webhook.Webhook, error) {
+	s.log.Debug(ctx, "list webhooks")
+
+	var webhooks []*webhook.Webhook
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	if err := db.Order("id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (s *store) DeleteWebhook(ctx context.Context, id uint) error {
+	s.log.Debug(ctx, "delete webhook")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	res := db.Where("id = ?", id).Delete(&webhook.Webhook{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (s *store) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	s.log.Debug(ctx, "list webhook deliveries")
+
+	var deliveries []*webhook.Delivery
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	query := db.Order("id DESC")
+
+	if filter.WebhookID != 0 {
+		query = query.Where("webhook_id = ?", filter.WebhookID)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *store) EnqueueDeliveries(ctx context.Context, eventType, schemaID string, payload []byte) error {
+	s.log.Debug(ctx, "enqueue webhook deliveries")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return enqueueDeliveries(db, eventType, schemaID, payload)
+}
+
+// ClaimDeliveries takes up to limit pending deliveries that are due, along with their webhook.
+// They are leased until leaseUntil, so that other replicas skip them meanwhile.
+func (s *store) ClaimDeliveries(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) ([]*webhook.Delivery, error) {
+	s.log.Debug(ctx, "claim webhook deliveries")
+
+	var deliveries []*webhook.Delivery
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", webhook.StatusPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+
+		if err := tx.Model(&webhook.Delivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error; err != nil {
+			return err
+		}
+
+		return tx.Preload("Webhook").Where("id IN ?", ids).Order("id").Find(&deliveries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *store) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	s.log.Debug(ctx, "update webhook delivery")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	return db.Model(d).Select("status", "attempts", "next_attempt_at", "response_status", "last_error", "updated_at").Updates(d).Error
+}
+
+func (s *store) RedeliverDelivery(ctx context.Context, id uint64) error {
+	s.log.Debug(ctx, "redeliver webhook delivery")
+
+	db, cancel := s.conn(ctx)
+	defer cancel()
+
+	res := db.Model(&webhook.Delivery{}).
+		Where("id = ? AND status = ?", id, webhook.StatusDead).
+		Updates(map[string]interface{}{
+			"status":          webhook.StatusPending,
+			"attempts":        0,
+			"next_attempt_at": gorm.Expr("now()"),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func enqueueDeliveries(tx *gorm.DB, eventType, schemaID string, payload []byte) error {
+	return tx.Exec(enqueueDeliveriesSQL, map[string]interface{}{
+		"type":      eventType,
+		"schema_id": schemaID,
+		"payload":   string(payload),
+		"status":    webhook.StatusPending,
+	}).Error
+}
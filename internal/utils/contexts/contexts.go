@@ -0,0 +1,106 @@
+package contexts
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+)
+
+type key string
+
+const (
+	actorKey     key = "actor"
+	requestIDKey key = "requestID"
+	namespaceKey key = "namespace"
+	principalKey key = "principal"
+)
+
+const (
+	// Anonymous is the actor of requests made without credentials.
+	Anonymous = "anonymous"
+	// System is the actor of the changes made by the service itself, e.g. background jobs.
+	System = "system"
+	// DefaultNamespace scopes the requests made outside of any namespace.
+	DefaultNamespace = "default"
+)
+
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns Anonymous when the request has no actor.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+
+	return Anonymous
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+
+	return requestID
+}
+
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey, namespace)
+}
+
+// Namespace returns DefaultNamespace when the request is not scoped to any.
+func Namespace(ctx context.Context) string {
+	if namespace, ok := ctx.Value(namespaceKey).(string); ok && namespace != "" {
+		return namespace
+	}
+
+	return DefaultNamespace
+}
+
+// ValidNamespace tells whether the namespace is a lowercase DNS label.
+func ValidNamespace(namespace string) bool {
+	return namespacePattern.MatchString(namespace)
+}
+
+func WithPrincipal(ctx context.Context, principal *auth.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// Principal returns the caller of the request, nil for the background jobs.
+func Principal(ctx context.Context) *auth.Principal {
+	principal, _ := ctx.Value(principalKey).(*auth.Principal)
+
+	return principal
+}
+
+// Detach keeps the values of ctx, but neither its deadline nor its cancellation.
+func Detach(ctx context.Context) context.Context {
+	return detached{parent: ctx}
+}
+
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -1,22 +1,98 @@
 package exceptions
 
 import (
-	"errors"
+	"net/http"
 )
 
+// Error is a failure known to the service, safe to report to clients.
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+var catalogue []*Error
+
+func newError(code string, status int, message string) *Error {
+	e := &Error{Code: code, Status: status, Message: message}
+	catalogue = append(catalogue, e)
+
+	return e
+}
+
+// Catalogue returns every Error the service reports, see docs/errors.md.
+func Catalogue() []*Error {
+	return append([]*Error(nil), catalogue...)
+}
+
 var (
-	ErrRecover              = errors.New("recovering from error")
-	ErrConnectingToDatabase = errors.New("could not connect to database")
-	ErrGetDB                = errors.New("could not get database")
-	ErrCloseDB              = errors.New("could not close database connection")
-	ErrInitializeDatabase   = errors.New("could not initialize database")
-	ErrInvalidJSON          = errors.New("invalid json")
-	ErrInternalServerError  = errors.New("internal server error")
-	ErrNotFound             = errors.New("not found")
-	ErrValidation           = errors.New("error validating the given json data, against the json-schema")
-	ErrAlreadyExists        = errors.New("already exists")
-
-	ErrCreateSchema   = errors.New("could not create schema")
-	ErrDownloadSchema = errors.New("could not download schema")
-	ErrValidateSchema = errors.New("could not validate schema")
+	ErrRecover              = newError("panic", http.StatusInternalServerError, "recovering from error")
+	ErrConnectingToDatabase = newError("database_unavailable", http.StatusInternalServerError, "could not connect to database")
+	ErrGetDB                = newError("database_handle_failed", http.StatusInternalServerError, "could not get database")
+	ErrCloseDB              = newError("database_close_failed", http.StatusInternalServerError, "could not close database connection")
+	ErrInitializeDatabase   = newError("database_init_failed", http.StatusInternalServerError, "could not initialize database")
+	ErrInvalidJSON          = newError("invalid_json", http.StatusBadRequest, "invalid json")
+	ErrInvalidMetadata      = newError("invalid_metadata", http.StatusBadRequest, "invalid metadata")
+	ErrInvalidDigest        = newError("invalid_digest", http.StatusBadRequest, "invalid sha256 digest")
+	ErrInvalidQuery         = newError("invalid_query", http.StatusBadRequest, "invalid query parameter")
+	ErrInvalidBundle        = newError("invalid_bundle", http.StatusBadRequest, "invalid bundle")
+	ErrInvalidWebhook       = newError("invalid_webhook", http.StatusBadRequest, "invalid webhook")
+	ErrInvalidSettings      = newError("invalid_settings", http.StatusBadRequest, "invalid quarantine settings")
+	ErrInvalidRef           = newError("invalid_ref", http.StatusBadRequest, "invalid schema reference")
+	ErrInvalidTag           = newError("invalid_tag", http.StatusBadRequest, "invalid tag")
+	ErrInvalidNamespace     = newError("invalid_namespace", http.StatusBadRequest, "invalid namespace")
+	ErrInvalidLifecycle     = newError("invalid_lifecycle", http.StatusBadRequest, "invalid lifecycle")
+	ErrInvalidAPIKey        = newError("invalid_api_key", http.StatusBadRequest, "invalid api key")
+	ErrJSONTooComplex       = newError("json_too_complex", http.StatusBadRequest, "json too complex")
+	ErrBodyTooLarge         = newError("body_too_large", http.StatusRequestEntityTooLarge, "request body too large")
+	ErrUnauthorized         = newError("unauthorized", http.StatusUnauthorized, "authentication required")
+	ErrForbidden            = newError("forbidden", http.StatusForbidden, "permission denied")
+	ErrRetired              = newError("schema_retired", http.StatusGone, "schema is retired")
+	ErrQuotaExceeded        = newError("quota_exceeded", http.StatusForbidden, "namespace quota exceeded")
+	ErrRateLimited          = newError("rate_limited", http.StatusTooManyRequests, "too many requests")
+	ErrInternalServerError  = newError("internal", http.StatusInternalServerError, "internal server error")
+	ErrStreamingUnsupported = newError("streaming_unsupported", http.StatusInternalServerError, "streaming unsupported")
+	ErrNotFound             = newError("not_found", http.StatusNotFound, "not found")
+	ErrValidation           = newError("validation_failed", http.StatusBadRequest, "error validating the given json data, against the json-schema")
+	ErrAlreadyExists        = newError("already_exists", http.StatusConflict, "already exists")
+	ErrPreconditionFailed   = newError("precondition_failed", http.StatusPreconditionFailed, "precondition failed, schema has been modified")
+	ErrPreconditionRequired = newError("precondition_required", http.StatusPreconditionRequired, "precondition required, missing If-Match header")
+
+	ErrCreateSchema       = newError("create_schema_failed", http.StatusInternalServerError, "could not create schema")
+	ErrDownloadSchema     = newError("download_schema_failed", http.StatusInternalServerError, "could not download schema")
+	ErrValidateSchema     = newError("validate_schema_failed", http.StatusInternalServerError, "could not validate schema")
+	ErrUpdateSchema       = newError("update_schema_failed", http.StatusInternalServerError, "could not update schema")
+	ErrDeleteSchema       = newError("delete_schema_failed", http.StatusInternalServerError, "could not delete schema")
+	ErrListSchemas        = newError("list_schemas_failed", http.StatusInternalServerError, "could not list schemas")
+	ErrListTrash          = newError("list_trash_failed", http.StatusInternalServerError, "could not list trash")
+	ErrRestoreSchema      = newError("restore_schema_failed", http.StatusInternalServerError, "could not restore schema")
+	ErrPurgeSchemas       = newError("purge_schemas_failed", http.StatusInternalServerError, "could not purge schemas")
+	ErrListAudit          = newError("list_audit_failed", http.StatusInternalServerError, "could not list audit log")
+	ErrImportSchemas      = newError("import_schemas_failed", http.StatusInternalServerError, "could not import schemas")
+	ErrExportSchemas      = newError("export_schemas_failed", http.StatusInternalServerError, "could not export schemas")
+	ErrCreateWebhook      = newError("create_webhook_failed", http.StatusInternalServerError, "could not create webhook")
+	ErrListWebhooks       = newError("list_webhooks_failed", http.StatusInternalServerError, "could not list webhooks")
+	ErrDeleteWebhook      = newError("delete_webhook_failed", http.StatusInternalServerError, "could not delete webhook")
+	ErrListDeliveries     = newError("list_deliveries_failed", http.StatusInternalServerError, "could not list webhook deliveries")
+	ErrRedeliver          = newError("redeliver_failed", http.StatusInternalServerError, "could not redeliver webhook delivery")
+	ErrSchemaStats        = newError("schema_stats_failed", http.StatusInternalServerError, "could not get schema stats")
+	ErrQuarantine         = newError("quarantine_failed", http.StatusInternalServerError, "could not quarantine payload")
+	ErrListQuarantine     = newError("list_quarantine_failed", http.StatusInternalServerError, "could not list quarantine")
+	ErrPurgeQuarantine    = newError("purge_quarantine_failed", http.StatusInternalServerError, "could not purge quarantine")
+	ErrReplayQuarantine   = newError("replay_quarantine_failed", http.StatusInternalServerError, "could not replay quarantine")
+	ErrQuarantineSettings = newError("quarantine_settings_failed", http.StatusInternalServerError, "could not update quarantine settings")
+	ErrListTags           = newError("list_tags_failed", http.StatusInternalServerError, "could not list tags")
+	ErrPutTag             = newError("put_tag_failed", http.StatusInternalServerError, "could not set tag")
+	ErrDeleteTag          = newError("delete_tag_failed", http.StatusInternalServerError, "could not delete tag")
+	ErrUpdateLifecycle    = newError("update_lifecycle_failed", http.StatusInternalServerError, "could not update schema lifecycle")
+	ErrCreateAPIKey       = newError("create_api_key_failed", http.StatusInternalServerError, "could not create api key")
+	ErrListAPIKeys        = newError("list_api_keys_failed", http.StatusInternalServerError, "could not list api keys")
+	ErrDeleteAPIKey       = newError("delete_api_key_failed", http.StatusInternalServerError, "could not delete api key")
+	ErrAuthenticate       = newError("authenticate_failed", http.StatusInternalServerError, "could not authenticate request")
+	ErrTakeToken          = newError("rate_limit_failed", http.StatusInternalServerError, "could not take rate limit token")
+	ErrPurgeRateLimits    = newError("purge_rate_limits_failed", http.StatusInternalServerError, "could not purge rate limits")
 )
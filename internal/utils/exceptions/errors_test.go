@@ -0,0 +1,26 @@
+package exceptions_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/utils/exceptions"
+)
+
+func TestCatalogue(t *testing.T) {
+	doc, err := os.ReadFile("../../../docs/errors.md")
+	require.NoError(t, err)
+
+	codes := make(map[string]bool)
+
+	for _, e := range exceptions.Catalogue() {
+		assert.False(t, codes[e.Code], "duplicate code %s", e.Code)
+		codes[e.Code] = true
+
+		assert.Contains(t, string(doc), fmt.Sprintf("### %s\n\n`%d` %s\n", e.Code, e.Status, e.Message), "undocumented code %s", e.Code)
+	}
+}
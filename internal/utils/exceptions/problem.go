@@ -0,0 +1,77 @@
+package exceptions
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ProblemContentType is the media type of the error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// problemTypes is the base URI of the problem types, each code documented under its anchor.
+const problemTypes = "https://github.com/KarolosLykos/json-validation-service/blob/main/docs/errors.md#"
+
+// Problem describes an error to clients (RFC 7807).
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Code   string `json:"code"`
+	Action string `json:"action,omitempty"`
+	ID     string `json:"id,omitempty"`
+
+	// Message is kept for the clients of the responses predating the problems.
+	Message string `json:"message"`
+}
+
+// NewProblem describes the error to clients, leaving out the details of the server errors.
+func NewProblem(err error) *Problem {
+	e := lookup(err)
+
+	p := &Problem{
+		Type:   problemTypes + e.Code,
+		Title:  e.Message,
+		Status: e.Status,
+		Code:   e.Code,
+	}
+
+	if e.Status < http.StatusInternalServerError && err.Error() != e.Message {
+		p.Detail = err.Error()
+	}
+
+	p.Message = p.Title
+	if p.Detail != "" {
+		p.Message = p.Detail
+	}
+
+	return p
+}
+
+func (p *Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// lookup returns the Error wrapped by err, the decoding failures being invalid JSON.
+func lookup(err error) *Error {
+	var (
+		e            *Error
+		syntaxErr    *json.SyntaxError
+		unmarshalErr *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &e):
+		return e
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &syntaxErr), errors.As(err, &unmarshalErr):
+		return ErrInvalidJSON
+	}
+
+	return ErrInternalServerError
+}
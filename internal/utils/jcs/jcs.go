@@ -0,0 +1,261 @@
+package jcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	ErrTrailingData = errors.New("unexpected data after top-level value")
+	ErrNumber       = errors.New("number is not representable as IEEE 754 double")
+	ErrDuplicateKey = errors.New("duplicate object key")
+)
+
+// maxSafeInteger bounds the integers of I-JSON (RFC 7493), doubles rounding those beyond.
+const maxSafeInteger = 1<<53 - 1
+
+// Canonicalize returns the JSON Canonicalization Scheme (RFC 8785) form of the document.
+func Canonicalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	v, err := decode(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, ErrTrailingData
+	}
+
+	buf := &bytes.Buffer{}
+	if err = encode(buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decode rejects the objects holding a key twice, as I-JSON does.
+func decode(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('['):
+		a := []interface{}{}
+
+		for dec.More() {
+			var e interface{}
+			if e, err = decode(dec); err != nil {
+				return nil, err
+			}
+
+			a = append(a, e)
+		}
+
+		_, err = dec.Token()
+
+		return a, err
+	case json.Delim('{'):
+		m := make(map[string]interface{})
+
+		for dec.More() {
+			var key json.Token
+			if key, err = dec.Token(); err != nil {
+				return nil, err
+			}
+
+			k, _ := key.(string)
+			if _, ok := m[k]; ok {
+				return nil, fmt.Errorf("%w: %q", ErrDuplicateKey, k)
+			}
+
+			if m[k], err = decode(dec); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = dec.Token()
+
+		return m, err
+	}
+
+	return tok, nil
+}
+
+func encode(buf *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(t))
+	case json.Number:
+		n, err := formatNumber(t)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(n)
+	case string:
+		encodeString(buf, t)
+	case []interface{}:
+		buf.WriteByte('[')
+
+		for i, e := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := encode(buf, e); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		return encodeObject(buf, t)
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+
+	return nil
+}
+
+// encodeObject sorts the members by the UTF-16 code units of their names.
+func encodeObject(buf *bytes.Buffer, m map[string]interface{}) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return lessUTF16(keys[i], keys[j])
+	})
+
+	buf.WriteByte('{')
+
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		encodeString(buf, k)
+		buf.WriteByte(':')
+
+		if err := encode(buf, m[k]); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+
+	return len(ua) < len(ub)
+}
+
+// encodeString escapes the string the same way as ECMAScript JSON.stringify.
+func encodeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// formatNumber serializes the number the same way as ECMAScript Number.prototype.toString.
+func formatNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("%w: %s", ErrNumber, n)
+	}
+
+	if !strings.ContainsAny(string(n), ".eE") && math.Abs(f) > maxSafeInteger {
+		return "", fmt.Errorf("%w: %s", ErrNumber, n)
+	}
+
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	// shortest round-trip representation, as d.ddde±x
+	parts := strings.SplitN(strconv.FormatFloat(f, 'e', -1, 64), "e", 2)
+
+	exp, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrNumber, n)
+	}
+
+	digits := strings.Replace(parts[0], ".", "", 1)
+	k, e := len(digits), exp+1
+
+	switch {
+	case k <= e && e <= 21:
+		return sign + digits + strings.Repeat("0", e-k), nil
+	case 0 < e && e <= 21:
+		return sign + digits[:e] + "." + digits[e:], nil
+	case -6 < e && e <= 0:
+		return sign + "0." + strings.Repeat("0", -e) + digits, nil
+	}
+
+	mantissa := digits[:1]
+	if k > 1 {
+		mantissa += "." + digits[1:]
+	}
+
+	expSign := "+"
+	if exp < 0 {
+		expSign, exp = "-", -exp
+	}
+
+	return sign + mantissa + "e" + expSign + strconv.Itoa(exp), nil
+}
@@ -0,0 +1,146 @@
+package jcs_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jcs"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tc := []struct {
+		name  string
+		input string
+		want  string
+		err   bool
+	}{
+		{
+			name: "rfc 8785 example",
+			input: `{
+				"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+				"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+				"literals": [null, true, false]
+			}`,
+			want: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			name:  "sorts keys by utf-16 code units",
+			input: `{"\u20ac":1,"\r":2,"\ufb33":3,"1":4,"\ud83d\ude00":5,"\u0080":6,"\u00f6":7}`,
+			want:  "{\"\\r\":2,\"1\":4,\"\u0080\":6,\"\u00f6\":7,\"\u20ac\":1,\"\U0001F600\":5,\"\ufb33\":3}",
+		},
+		{
+			name:  "nested objects",
+			input: `{ "b": { "d": [ 1, {"f": 1, "e": 2} ], "c": "x" }, "a": 0 }`,
+			want:  `{"a":0,"b":{"c":"x","d":[1,{"e":2,"f":1}]}}`,
+		},
+		{
+			name:  "invalid json",
+			input: `{"a":`,
+			err:   true,
+		},
+		{
+			name:  "trailing data",
+			input: `{"a":1} {"b":2}`,
+			err:   true,
+		},
+		{
+			name:  "number out of range",
+			input: `[1e400]`,
+			err:   true,
+		},
+		{
+			name:  "largest safe integers",
+			input: `[9007199254740991, -9007199254740991, 1e300]`,
+			want:  `[9007199254740991,-9007199254740991,1e+300]`,
+		},
+		{
+			name:  "integer beyond the safe range",
+			input: `{"maximum": 9007199254740993}`,
+			err:   true,
+		},
+		{
+			name:  "empty containers",
+			input: `{"a": [], "b": {}}`,
+			want:  `{"a":[],"b":{}}`,
+		},
+		{
+			name:  "duplicate key",
+			input: `{"a": 1, "a": 2}`,
+			err:   true,
+		},
+		{
+			name:  "duplicate escaped key",
+			input: `{"b": {"a": 1, "\u0061": 2}}`,
+			err:   true,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jcs.Canonicalize([]byte(tt.input))
+			if tt.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestCanonicalize_IJSON(t *testing.T) {
+	_, err := jcs.Canonicalize([]byte(`{"a": 1, "a": 2}`))
+	assert.ErrorIs(t, err, jcs.ErrDuplicateKey)
+
+	_, err = jcs.Canonicalize([]byte(`[18446744073709551615]`))
+	assert.ErrorIs(t, err, jcs.ErrNumber)
+}
+
+// TestCanonicalize_Numbers uses the IEEE 754 test vectors of RFC 8785, appendix B.
+func TestCanonicalize_Numbers(t *testing.T) {
+	tc := []struct {
+		bits uint64
+		want string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.want, func(t *testing.T) {
+			input := strconv.FormatFloat(math.Float64frombits(tt.bits), 'g', -1, 64)
+
+			got, err := jcs.Canonicalize([]byte(input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
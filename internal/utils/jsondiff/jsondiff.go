@@ -0,0 +1,108 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Operation is a JSON Patch (RFC 6902) operation.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON keeps the value of the add and replace operations, even null, and drops the one of removals.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// Diff returns the JSON Patch from one document to the other, an empty one standing for a missing one.
+// Arrays that differ are replaced as a whole.
+func Diff(from, to []byte) ([]Operation, error) {
+	var a, b interface{}
+
+	if len(from) > 0 {
+		if err := json.Unmarshal(from, &a); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(to) > 0 {
+		if err := json.Unmarshal(to, &b); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case len(from) == 0 && len(to) == 0:
+		return []Operation{}, nil
+	case len(from) == 0:
+		return []Operation{{Op: "add", Path: "", Value: b}}, nil
+	case len(to) == 0:
+		return []Operation{{Op: "remove", Path: ""}}, nil
+	}
+
+	return diff("", a, b, []Operation{}), nil
+}
+
+func diff(path string, a, b interface{}, ops []Operation) []Operation {
+	ma, okA := a.(map[string]interface{})
+	mb, okB := b.(map[string]interface{})
+
+	if !okA || !okB {
+		if !reflect.DeepEqual(a, b) {
+			ops = append(ops, Operation{Op: "replace", Path: path, Value: b})
+		}
+
+		return ops
+	}
+
+	for _, k := range sortedKeys(ma) {
+		if _, ok := mb[k]; !ok {
+			ops = append(ops, Operation{Op: "remove", Path: path + "/" + escape(k)})
+		}
+	}
+
+	for _, k := range sortedKeys(mb) {
+		va, ok := ma[k]
+		if !ok {
+			ops = append(ops, Operation{Op: "add", Path: path + "/" + escape(k), Value: mb[k]})
+
+			continue
+		}
+
+		ops = diff(path+"/"+escape(k), va, mb[k], ops)
+	}
+
+	return ops
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// escape encodes a member name as a JSON Pointer (RFC 6901) reference token.
+func escape(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
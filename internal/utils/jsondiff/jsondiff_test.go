@@ -0,0 +1,123 @@
+package jsondiff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsondiff"
+)
+
+func TestDiff(t *testing.T) {
+	tc := []struct {
+		name string
+		from string
+		to   string
+		want []jsondiff.Operation
+		err  bool
+	}{
+		{
+			name: "creation",
+			from: "",
+			to:   `{"type":"object"}`,
+			want: []jsondiff.Operation{{Op: "add", Path: "", Value: map[string]interface{}{"type": "object"}}},
+		},
+		{
+			name: "deletion",
+			from: `{"type":"object"}`,
+			to:   "",
+			want: []jsondiff.Operation{{Op: "remove", Path: ""}},
+		},
+		{
+			name: "identical",
+			from: `{"type":"object","required":["a"]}`,
+			to:   `{"required":["a"],"type":"object"}`,
+			want: []jsondiff.Operation{},
+		},
+		{
+			name: "nested changes",
+			from: `{"properties":{"a":{"type":"string"},"b":{"type":"integer"}},"required":["a"]}`,
+			to:   `{"properties":{"a":{"type":"number"},"c/d":{"type":"boolean"}},"required":["a","c/d"]}`,
+			want: []jsondiff.Operation{
+				{Op: "remove", Path: "/properties/b"},
+				{Op: "replace", Path: "/properties/a/type", Value: "number"},
+				{Op: "add", Path: "/properties/c~1d", Value: map[string]interface{}{"type": "boolean"}},
+				{Op: "replace", Path: "/required", Value: []interface{}{"a", "c/d"}},
+			},
+		},
+		{
+			name: "type change",
+			from: `{"a":{"b":1}}`,
+			to:   `{"a":[1]}`,
+			want: []jsondiff.Operation{{Op: "replace", Path: "/a", Value: []interface{}{float64(1)}}},
+		},
+		{
+			name: "null values",
+			from: `{"default":null,"const":1}`,
+			to:   `{"default":1,"const":null}`,
+			want: []jsondiff.Operation{
+				{Op: "replace", Path: "/const", Value: nil},
+				{Op: "replace", Path: "/default", Value: float64(1)},
+			},
+		},
+		{
+			name: "invalid json",
+			from: `{`,
+			to:   `{}`,
+			err:  true,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := jsondiff.Diff([]byte(tt.from), []byte(tt.to))
+			if tt.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ops)
+		})
+	}
+}
+
+func TestOperation_MarshalJSON(t *testing.T) {
+	tc := []struct {
+		name string
+		op   jsondiff.Operation
+		want string
+	}{
+		{
+			name: "replace with null",
+			op:   jsondiff.Operation{Op: "replace", Path: "/default"},
+			want: `{"op":"replace","path":"/default","value":null}`,
+		},
+		{
+			name: "add null",
+			op:   jsondiff.Operation{Op: "add", Path: "/default"},
+			want: `{"op":"add","path":"/default","value":null}`,
+		},
+		{
+			name: "replace with zero",
+			op:   jsondiff.Operation{Op: "replace", Path: "/minimum", Value: float64(0)},
+			want: `{"op":"replace","path":"/minimum","value":0}`,
+		},
+		{
+			name: "remove",
+			op:   jsondiff.Operation{Op: "remove", Path: "/default"},
+			want: `{"op":"remove","path":"/default"}`,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.op)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(b))
+		})
+	}
+}
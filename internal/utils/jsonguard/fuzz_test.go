@@ -0,0 +1,107 @@
+//go:build go1.18
+
+package jsonguard_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsonguard"
+)
+
+// FuzzCheck checks that the valid JSON values are rejected if, and only if, they exceed a bound, as
+// measured by the tokens of the standard decoder, and that no input makes Check panic.
+func FuzzCheck(f *testing.F) {
+	for _, seed := range []string{
+		`{}`, `[]`, `"a"`, `1`, `null`,
+		`{"a": [1, [2, 3], {}], "b": {"c": []}}`,
+		`[[[[[]]]]]`,
+		`[1, 2, 3, 4]`,
+		`{"a": 1, "b": 2, "c": 3}`,
+		`{"[": "]", "a\"{": "\\", "b": ["\",", ","]}`,
+		`]]}}[,,{`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err := jsonguard.Check(data, limits)
+
+		if !json.Valid(data) {
+			return
+		}
+
+		exceeded, mErr := measure(data)
+		if mErr != nil {
+			t.Fatalf("could not measure %q: %v", data, mErr)
+		}
+
+		if exceeded != (err != nil) {
+			t.Fatalf("exceeded %v, got %v for %q", exceeded, err, data)
+		}
+	})
+}
+
+// measure tells whether the JSON value exceeds the limits, reading it token by token.
+func measure(data []byte) (bool, error) {
+	type container struct {
+		object    bool
+		count     int
+		expectKey bool
+	}
+
+	var stack []*container
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		if d, ok := tok.(json.Delim); ok && (d == ']' || d == '}') {
+			stack = stack[:len(stack)-1]
+
+			if len(stack) > 0 && stack[len(stack)-1].object {
+				stack[len(stack)-1].expectKey = true
+			}
+
+			continue
+		}
+
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+
+			switch {
+			case !top.object:
+				top.count++
+			case top.expectKey:
+				top.count++
+				top.expectKey = false
+			default:
+				top.expectKey = true
+			}
+
+			if (top.object && top.count > limits.MaxObjectKeys) || (!top.object && top.count > limits.MaxArrayLength) {
+				return true, nil
+			}
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			if len(stack) >= limits.MaxDepth {
+				return true, nil
+			}
+
+			stack = append(stack, &container{object: d == '{', expectKey: d == '{'})
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package jsonguard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KarolosLykos/json-validation-service/internal/config"
+)
+
+var (
+	ErrDepth       = errors.New("json nested too deeply")
+	ErrArrayLength = errors.New("json array too long")
+	ErrObjectKeys  = errors.New("json object has too many keys")
+)
+
+// Limits bound the complexity of a JSON value, 0 meaning unbounded.
+type Limits struct {
+	MaxDepth       int
+	MaxArrayLength int
+	MaxObjectKeys  int
+}
+
+func FromConfig(cfg *config.Config) Limits {
+	return Limits{
+		MaxDepth:       cfg.Limits.MaxDepth,
+		MaxArrayLength: cfg.Limits.MaxArrayLength,
+		MaxObjectKeys:  cfg.Limits.MaxObjectKeys,
+	}
+}
+
+// container is an array or object being scanned.
+type container struct {
+	object bool
+	count  int
+	// pending is set until the next element, or key, starts.
+	pending bool
+}
+
+// Check scans the JSON data, without decoding nor validating it, and fails as soon as a bound is exceeded.
+func Check(data []byte, limits Limits) error {
+	sc := &scanner{limits: limits}
+
+	return sc.scan(data)
+}
+
+type scanner struct {
+	limits   Limits
+	stack    []container
+	inString bool
+	escaped  bool
+}
+
+func (sc *scanner) scan(data []byte) error {
+	for _, c := range data {
+		if sc.inString {
+			switch {
+			case sc.escaped:
+				sc.escaped = false
+			case c == '\\':
+				sc.escaped = true
+			case c == '"':
+				sc.inString = false
+			}
+
+			continue
+		}
+
+		switch c {
+		case ' ', '\t', '\n', '\r', ':':
+			continue
+		case ']', '}':
+			if len(sc.stack) > 0 {
+				sc.stack = sc.stack[:len(sc.stack)-1]
+			}
+
+			continue
+		case ',':
+			if len(sc.stack) > 0 {
+				sc.stack[len(sc.stack)-1].pending = true
+			}
+
+			continue
+		}
+
+		// a value, or key, starts.
+		if len(sc.stack) > 0 && sc.stack[len(sc.stack)-1].pending {
+			if err := count(&sc.stack[len(sc.stack)-1], sc.limits); err != nil {
+				return err
+			}
+		}
+
+		switch c {
+		case '"':
+			sc.inString = true
+		case '[', '{':
+			if sc.limits.MaxDepth > 0 && len(sc.stack) >= sc.limits.MaxDepth {
+				return fmt.Errorf("%w: more than %d levels", ErrDepth, sc.limits.MaxDepth)
+			}
+
+			sc.stack = append(sc.stack, container{object: c == '{', pending: true})
+		}
+	}
+
+	return nil
+}
+
+func count(c *container, limits Limits) error {
+	c.pending = false
+	c.count++
+
+	switch {
+	case c.object && limits.MaxObjectKeys > 0 && c.count > limits.MaxObjectKeys:
+		return fmt.Errorf("%w: more than %d keys", ErrObjectKeys, limits.MaxObjectKeys)
+	case !c.object && limits.MaxArrayLength > 0 && c.count > limits.MaxArrayLength:
+		return fmt.Errorf("%w: more than %d elements", ErrArrayLength, limits.MaxArrayLength)
+	}
+
+	return nil
+}
+
+func Unmarshal(data []byte, v interface{}, limits Limits) error {
+	if err := Check(data, limits); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// NewReader returns a reader of the JSON data of r, failing the read exceeding a bound.
+func NewReader(r io.Reader, limits Limits) io.Reader {
+	return &reader{r: r, sc: scanner{limits: limits}}
+}
+
+// reader scans the data it reads, the first bound exceeded failing every read after it.
+type reader struct {
+	r   io.Reader
+	sc  scanner
+	err error
+}
+
+func (g *reader) Read(p []byte) (int, error) {
+	if g.err != nil {
+		return 0, g.err
+	}
+
+	n, err := g.r.Read(p)
+	if g.err = g.sc.scan(p[:n]); g.err != nil {
+		return 0, g.err
+	}
+
+	return n, err
+}
+
+func Exceeded(err error) bool {
+	return errors.Is(err, ErrDepth) || errors.Is(err, ErrArrayLength) || errors.Is(err, ErrObjectKeys)
+}
@@ -0,0 +1,126 @@
+package jsonguard_test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jsonguard"
+)
+
+var limits = jsonguard.Limits{MaxDepth: 3, MaxArrayLength: 3, MaxObjectKeys: 2}
+
+func TestCheck(t *testing.T) {
+	tc := []struct {
+		name   string
+		input  string
+		limits jsonguard.Limits
+		err    error
+	}{
+		{
+			name:  "within limits",
+			input: `{"a": [1, [2, 3], {}], "b": {"c": []}}`,
+		},
+		{
+			name:  "scalar",
+			input: `"[[[[{"`,
+		},
+		{
+			name:  "too deep",
+			input: `{"a": [[{}]]}`,
+			err:   jsonguard.ErrDepth,
+		},
+		{
+			name:  "array too long",
+			input: `[1, "2", null, true]`,
+			err:   jsonguard.ErrArrayLength,
+		},
+		{
+			name:  "nested array too long",
+			input: `{"a": [[], [], [], []]}`,
+			err:   jsonguard.ErrArrayLength,
+		},
+		{
+			name:  "too many keys",
+			input: `{"a": 1, "b": {"c": 2, "d": 3}, "e": 4}`,
+			err:   jsonguard.ErrObjectKeys,
+		},
+		{
+			name:  "brackets in strings",
+			input: `{"[[[[": "{{{{", "a\"[[": "\\"}`,
+		},
+		{
+			name:  "commas in strings",
+			input: `["a,b,c,d,e", "\",\",\""]`,
+		},
+		{
+			name:   "unbounded",
+			input:  `[[[[[[1, 2, 3, 4, 5]]]]]]`,
+			limits: jsonguard.Limits{},
+		},
+		{
+			name:  "malformed",
+			input: `]]}}[,,{`,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			l := limits
+			if tt.name == "unbounded" {
+				l = tt.limits
+			}
+
+			err := jsonguard.Check([]byte(tt.input), l)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.True(t, jsonguard.Exceeded(err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// the reader checks the input alike, however it is split.
+			_, err = io.ReadAll(jsonguard.NewReader(iotest.OneByteReader(strings.NewReader(tt.input)), l))
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	v := make(map[string]interface{})
+
+	require.NoError(t, jsonguard.Unmarshal([]byte(`{"a": [1, 2]}`), &v, limits))
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{1.0, 2.0}}, v)
+
+	err := jsonguard.Unmarshal([]byte(strings.Repeat("[", 10000)), &v, limits)
+	assert.ErrorIs(t, err, jsonguard.ErrDepth)
+
+	err = jsonguard.Unmarshal([]byte(`{"a":`), &v, limits)
+	assert.Error(t, err)
+	assert.False(t, jsonguard.Exceeded(err))
+}
+
+func TestNewReader(t *testing.T) {
+	v := make(map[string]interface{})
+
+	require.NoError(t, json.NewDecoder(jsonguard.NewReader(strings.NewReader(`{"a": [1, 2]}`), limits)).Decode(&v))
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{1.0, 2.0}}, v)
+
+	r := jsonguard.NewReader(strings.NewReader(strings.Repeat("[", 10000)), limits)
+
+	err := json.NewDecoder(r).Decode(&v)
+	assert.ErrorIs(t, err, jsonguard.ErrDepth)
+
+	// the error sticks to the reader.
+	_, err = r.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, jsonguard.ErrDepth)
+}
@@ -0,0 +1,184 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/KarolosLykos/json-validation-service/internal/models/auth"
+)
+
+// methods are the accepted signing algorithms, those of public keys only.
+var methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+// Claims are the claims of the accepted tokens.
+type Claims struct {
+	jwt.RegisteredClaims
+	Namespace string      `json:"namespace,omitempty"`
+	Grants    auth.Grants `json:"grants,omitempty"`
+}
+
+// Verifier verifies JWT bearer tokens against the public keys of a JWKS (RFC 7517).
+type Verifier struct {
+	keys     map[string]interface{}
+	issuer   string
+	audience string
+}
+
+// key is a JSON Web Key, of which the RSA and EC public keys are supported.
+type key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func Load(path, issuer, audience string) (*Verifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data, issuer, audience)
+}
+
+// Parse reads the JWKS, leaving out the keys not meant for signatures.
+func Parse(data []byte, issuer, audience string) (*Verifier, error) {
+	set := struct {
+		Keys []*key `json:"keys"`
+	}{}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+
+	v := &Verifier{
+		keys:     make(map[string]interface{}, len(set.Keys)),
+		issuer:   issuer,
+		audience: audience,
+	}
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		pub, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %q: %w", k.Kid, err)
+		}
+
+		v.keys[k.Kid] = pub
+	}
+
+	if len(v.keys) == 0 {
+		return nil, errors.New("invalid jwks: no signing keys")
+	}
+
+	return v, nil
+}
+
+// Verify checks the signature, expiry, issuer and audience of the token, which must have a subject.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+
+	if _, err := jwt.ParseWithClaims(token, claims, v.key, jwt.WithValidMethods(methods)); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case claims.ExpiresAt == nil:
+		return nil, errors.New("token has no expiry")
+	case claims.Subject == "":
+		return nil, errors.New("token has no subject")
+	case v.issuer != "" && !claims.VerifyIssuer(v.issuer, true):
+		return nil, fmt.Errorf("token is not issued by %q", v.issuer)
+	case v.audience != "" && !claims.VerifyAudience(v.audience, true):
+		return nil, fmt.Errorf("token is not meant for %q", v.audience)
+	}
+
+	return claims, nil
+}
+
+// key returns the public key of the token, by its key ID.
+func (v *Verifier) key(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	pub, ok := v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	return pub, nil
+}
+
+func (k *key) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
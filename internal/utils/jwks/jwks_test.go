@@ -0,0 +1,148 @@
+package jwks_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/json-validation-service/internal/utils/jwks"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	v, err := jwks.Parse(helperJWKS(t, rsaKey, ecKey), "https://issuer.example.com", "json-validation-service")
+	require.NoError(t, err)
+
+	claims := func() *jwks.Claims {
+		return &jwks.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "alice",
+				Issuer:    "https://issuer.example.com",
+				Audience:  jwt.ClaimStrings{"json-validation-service"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+			Namespace: "team-a",
+		}
+	}
+
+	tc := []struct {
+		name   string
+		method jwt.SigningMethod
+		kid    string
+		key    interface{}
+		claims func(c *jwks.Claims)
+		err    bool
+	}{
+		{name: "rsa", method: jwt.SigningMethodRS256, kid: "rsa", key: rsaKey},
+		{name: "ec", method: jwt.SigningMethodES256, kid: "ec", key: ecKey},
+		{name: "rsa pss", method: jwt.SigningMethodPS256, kid: "rsa", key: rsaKey},
+		{name: "unknown key", method: jwt.SigningMethodRS256, kid: "other", key: otherKey, err: true},
+		{name: "forged signature", method: jwt.SigningMethodRS256, kid: "rsa", key: otherKey, err: true},
+		{name: "key of another type", method: jwt.SigningMethodES256, kid: "rsa", key: ecKey, err: true},
+		{name: "symmetric algorithm", method: jwt.SigningMethodHS256, kid: "rsa", key: []byte("secret"), err: true},
+		{
+			name: "expired", method: jwt.SigningMethodRS256, kid: "rsa", key: rsaKey, err: true,
+			claims: func(c *jwks.Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) },
+		},
+		{
+			name: "no expiry", method: jwt.SigningMethodRS256, kid: "rsa", key: rsaKey, err: true,
+			claims: func(c *jwks.Claims) { c.ExpiresAt = nil },
+		},
+		{
+			name: "no subject", method: jwt.SigningMethodRS256, kid: "rsa", key: rsaKey, err: true,
+			claims: func(c *jwks.Claims) { c.Subject = "" },
+		},
+		{
+			name: "other issuer", method: jwt.SigningMethodRS256, kid: "rsa", key: rsaKey, err: true,
+			claims: func(c *jwks.Claims) { c.Issuer = "https://evil.example.com" },
+		},
+		{
+			name: "other audience", method: jwt.SigningMethodRS256, kid: "rsa", key: rsaKey, err: true,
+			claims: func(c *jwks.Claims) { c.Audience = jwt.ClaimStrings{"another-service"} },
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			c := claims()
+			if tt.claims != nil {
+				tt.claims(c)
+			}
+
+			token := jwt.NewWithClaims(tt.method, c)
+			token.Header["kid"] = tt.kid
+
+			signed, err := token.SignedString(tt.key)
+			require.NoError(t, err)
+
+			got, err := v.Verify(signed)
+			if tt.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "alice", got.Subject)
+			assert.Equal(t, "team-a", got.Namespace)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tc := []struct {
+		name string
+		jwks string
+	}{
+		{name: "not json", jwks: `keys`},
+		{name: "no keys", jwks: `{"keys":[]}`},
+		{name: "encryption keys only", jwks: `{"keys":[{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"}]}`},
+		{name: "unsupported key type", jwks: `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`},
+		{name: "unsupported curve", jwks: `{"keys":[{"kty":"EC","crv":"P-192","x":"AQ","y":"AQ"}]}`},
+		{name: "point off the curve", jwks: `{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`},
+		{name: "invalid modulus", jwks: `{"keys":[{"kty":"RSA","n":"!","e":"AQAB"}]}`},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jwks.Parse([]byte(tt.jwks), "", "")
+			require.Error(t, err)
+		})
+	}
+}
+
+func helperJWKS(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) []byte {
+	t.Helper()
+
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encode(rsaKey.N), "e": encode(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encode(ecKey.X), "y": encode(ecKey.Y)},
+		},
+	})
+	require.NoError(t, err)
+
+	return data
+}